@@ -0,0 +1,26 @@
+// Package api embeds the API definitions, so the service could serve them
+// and validate the published events against the event schemas.
+package api
+
+import "embed"
+
+// AsyncAPI is the AsyncAPI definition of the published user events.
+//
+//go:embed asyncapi.yaml
+var AsyncAPI []byte
+
+// EventSchemas contains the JSON Schemas of the user events in the `schemas` directory,
+// named by the lower case event type (i.e. `schemas/user_created.json`).
+//
+//go:embed schemas/*.json
+var EventSchemas embed.FS
+
+// UserEventsProto is the protobuf schema of the user events what are published with protobuf encoding.
+//
+//go:embed proto/userevents/v1/user_events.proto
+var UserEventsProto []byte
+
+// UsersGraphQL is the GraphQL schema of the users what is served on `/graphql`.
+//
+//go:embed users.graphql
+var UsersGraphQL string
@@ -0,0 +1,87 @@
+// Command replay re-emits the stored user events or a snapshot of every user to RabbitMQ,
+// so the event consumers could rebuild their state. It uses the same environment variables as the service.
+//
+// Usage:
+//
+//	replay [-since 2023-01-02T15:04:05Z] [-user-ids id1,id2] [-types USER_CREATED,USER_DELETED] [-exchange name] [-routing-key key]
+//	replay -snapshot [-exchange name] [-routing-key key]
+package main
+
+import (
+	"context"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	snapshot := flag.Bool("snapshot", false, "publish a USER_CREATED snapshot of every user instead of replaying the stored events")
+	since := flag.String("since", "", "replay the events created since this RFC3339 time")
+	userIDs := flag.String("user-ids", "", "comma separated list of user ids to replay")
+	types := flag.String("types", "", "comma separated list of event types to replay")
+	exchange := flag.String("exchange", "", "existing target exchange (default is the user event exchange)")
+	routingKey := flag.String("routing-key", "", "routing key of the published events (default #)")
+	flag.Parse()
+
+	target := user.ReplayTarget{Exchange: *exchange, RoutingKey: *routingKey}
+	req := user.ReplayRequest{ReplayTarget: target}
+	if *since != "" {
+		t, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			log.Fatal().Msgf("invalid since: %+v", err)
+		}
+		req.Since = t
+	}
+	for _, id := range split(*userIDs) {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			log.Fatal().Msgf("invalid user id %s: %+v", id, err)
+		}
+		req.UserIDs = append(req.UserIDs, userID)
+	}
+	for _, t := range split(*types) {
+		req.Types = append(req.Types, user.UserEventType(t))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = context.WithValue(ctx, common.CorrelationID, uuid.NewString())
+
+	published, err := run(ctx, *snapshot, req)
+	if err != nil {
+		log.Fatal().Int("published", published).Msgf("failed to publish user events: %+v", err)
+	}
+	log.Info().Int("published", published).Msg("user events published")
+}
+
+func run(ctx context.Context, snapshot bool, req user.ReplayRequest) (int, error) {
+	replayer, err := user.NewReplayer()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := replayer.Close(context.Background()); err != nil {
+			log.Err(err).Msg("failed to close replayer")
+		}
+	}()
+
+	if snapshot {
+		return replayer.Snapshot(ctx, req.ReplayTarget)
+	}
+	return replayer.Replay(ctx, req)
+}
+
+func split(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
@@ -0,0 +1,56 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package api
+
+import (
+	echo "github.com/labstack/echo/v4"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockServerInterface is an autogenerated mock type for the ServerInterface type
+type MockServerInterface struct {
+	mock.Mock
+}
+
+// PublishSnapshot provides a mock function with given fields: ctx
+func (_m *MockServerInterface) PublishSnapshot(ctx echo.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplayEvents provides a mock function with given fields: ctx
+func (_m *MockServerInterface) ReplayEvents(ctx echo.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMockServerInterface interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockServerInterface creates a new instance of MockServerInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockServerInterface(t mockConstructorTestingTNewMockServerInterface) *MockServerInterface {
+	mock := &MockServerInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
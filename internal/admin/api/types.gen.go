@@ -0,0 +1,75 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.12.3 DO NOT EDIT.
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for EventType.
+const (
+	USERCOUNTRYCHANGED  EventType = "USER_COUNTRY_CHANGED"
+	USERCREATED         EventType = "USER_CREATED"
+	USERDELETED         EventType = "USER_DELETED"
+	USEREMAILCHANGED    EventType = "USER_EMAIL_CHANGED"
+	USERNICKNAMECHANGED EventType = "USER_NICKNAME_CHANGED"
+	USERPASSWORDCHANGED EventType = "USER_PASSWORD_CHANGED"
+	USERUPDATED         EventType = "USER_UPDATED"
+)
+
+// Error defines model for Error.
+type Error struct {
+	CorrelationId uuid.UUID `json:"correlation_id"`
+	Message       string    `json:"message"`
+	Status        int       `json:"status"`
+	Time          time.Time `json:"time"`
+}
+
+// EventType defines model for EventType.
+type EventType string
+
+// ReplayRequest defines model for ReplayRequest.
+type ReplayRequest struct {
+	// EventTypes replay only these event types
+	EventTypes *[]EventType `json:"event_types,omitempty"`
+
+	// Exchange existing exchange where the events are published, the user event exchange is used when it is empty
+	Exchange *string `json:"exchange,omitempty"`
+
+	// RoutingKey routing key of the published events, `#` is used when it is empty
+	RoutingKey *string `json:"routing_key,omitempty"`
+
+	// Since replay the events created since this time
+	Since *time.Time `json:"since,omitempty"`
+
+	// UserIds replay only the events of these users
+	UserIds *[]uuid.UUID `json:"user_ids,omitempty"`
+}
+
+// ReplayResult defines model for ReplayResult.
+type ReplayResult struct {
+	// Published number of published events
+	Published int `json:"published"`
+}
+
+// ReplayTarget defines model for ReplayTarget.
+type ReplayTarget struct {
+	// Exchange existing exchange where the events are published, the user event exchange is used when it is empty
+	Exchange *string `json:"exchange,omitempty"`
+
+	// RoutingKey routing key of the published events, `#` is used when it is empty
+	RoutingKey *string `json:"routing_key,omitempty"`
+}
+
+// ReplayEventsJSONRequestBody defines body for ReplayEvents for application/json ContentType.
+type ReplayEventsJSONRequestBody = ReplayRequest
+
+// PublishSnapshotJSONRequestBody defines body for PublishSnapshot for application/json ContentType.
+type PublishSnapshotJSONRequestBody = ReplayTarget
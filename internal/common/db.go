@@ -0,0 +1,20 @@
+package common
+
+import (
+	"fmt"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// OpenPostgres creates a new DB connection configured by the PG_* environment variables
+func OpenPostgres() (*gorm.DB, error) {
+	pgHost := GetEnv("PG_HOST", "localhost")
+	pgPort := GetEnv("PG_PORT", "5432")
+	pgUser := GetEnv("PG_USER", "admin")
+	pgPass := GetEnv("PG_PASSWORD", "pass")
+	pgDb := GetEnv("PG_DATABASE", "users")
+
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s", pgHost, pgPort, pgUser, pgPass, pgDb)
+	return gorm.Open(postgres.Open(dsn))
+}
@@ -3,9 +3,12 @@ package common
 import (
 	"context"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
 )
 
 func Ptr[T any](in T) *T {
@@ -19,6 +22,42 @@ func GetEnv(key string, def string) string {
 	return def
 }
 
+func GetEnvInt(key string, def int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("failed to parse integer environment variable")
+		return def
+	}
+	return i
+}
+
+func GetEnvDuration(key string, def time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("failed to parse duration environment variable")
+		return def
+	}
+	return d
+}
+
+// CorrelationIDOrNew returns the correlation id received from the client, or a new one when it's empty.
+// Both the REST and the gRPC API use it, so the requests are correlated the same way.
+func CorrelationIDOrNew(received string) string {
+	if received != "" {
+		log.Info().Msgf("keeping existing correlationID %s", received)
+		return received
+	}
+	return uuid.NewString()
+}
+
 func GetEchoCorrelationID(ctx echo.Context) string {
 	correlationID := ""
 	if ctx == nil {
@@ -51,3 +90,10 @@ func GetCorrelationID(ctx context.Context) string {
 	}
 	return correlationID
 }
+
+// DetachedContext returns a context with the correlation id of ctx and its own timeout, what isn't canceled with ctx,
+// i.e. to finish a write what must not be lost when the request is canceled or timed out.
+func DetachedContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	c := context.WithValue(context.Background(), CorrelationID, GetCorrelationID(ctx))
+	return context.WithTimeout(c, timeout)
+}
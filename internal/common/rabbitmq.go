@@ -0,0 +1,17 @@
+package common
+
+import (
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DialRabbitMQ creates a new RabbitMQ connection configured by the RMQ_* environment variables
+func DialRabbitMQ() (*amqp.Connection, error) {
+	mqHost := GetEnv("RMQ_HOST", "localhost")
+	mqPort := GetEnv("RMQ_PORT", "5672")
+	mqUser := GetEnv("RMQ_USER", "guest")
+	mqPass := GetEnv("RMQ_PASSWORD", "guest")
+
+	return amqp.Dial(fmt.Sprintf("amqp://%s:%s@%s:%s/", mqUser, mqPass, mqHost, mqPort))
+}
@@ -0,0 +1,59 @@
+package inbound
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"faceit/internal/user"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidMessage = errors.New("invalid inbound message")
+
+// command applies an inbound message on the users
+type command func(ctx context.Context, svc userService, body []byte) error
+
+// commands are the supported inbound messages by their type
+var commands = map[string]command{
+	"account.country_verified": countryVerified,
+}
+
+// countryVerifiedMessage is sent by the account service when the country of the user was verified
+type countryVerifiedMessage struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Country string    `json:"country"`
+}
+
+// countryVerified sets the verified country of the user. It's skipped when the user has the same country already,
+// so a redelivered message doesn't publish a new user event.
+func countryVerified(ctx context.Context, svc userService, body []byte) error {
+	var msg countryVerifiedMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidMessage, err.Error())
+	}
+	if msg.UserID == uuid.Nil || msg.Country == "" {
+		return fmt.Errorf("%w: user_id and country are required", ErrInvalidMessage)
+	}
+
+	u, err := svc.Get(ctx, msg.UserID, nil)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(u.Country, msg.Country) {
+		return nil
+	}
+
+	_, err = svc.Update(ctx, msg.UserID, user.User{Country: msg.Country}, "")
+	return err
+}
+
+// isPoison is true for the messages what would fail on every attempt
+func isPoison(err error) bool {
+	return errors.Is(err, ErrInvalidMessage) ||
+		errors.Is(err, user.ErrInvalidUserInputData) ||
+		errors.Is(err, user.ErrNilUUIDNotAllowed) ||
+		errors.Is(err, user.ErrUserNotFound)
+}
@@ -0,0 +1,342 @@
+package inbound
+
+import (
+	"context"
+	"errors"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog/log"
+)
+
+// headerAttempt counts the processing attempts of the retried messages
+const headerAttempt = "x-inbound-attempt"
+
+var ErrInvalidBinding = errors.New("invalid inbound binding")
+
+type (
+	userService interface {
+		Get(ctx context.Context, id uuid.UUID, fields user.Fields) (*user.User, error)
+		Update(ctx context.Context, id uuid.UUID, user user.User, password string) (*user.User, error)
+	}
+
+	amqpChannel interface {
+		ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+		QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+		QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+		Qos(prefetchCount, prefetchSize int, global bool) error
+		Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+		Cancel(consumer string, noWait bool) error
+		PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+		Close() error
+	}
+
+	// Binding subscribes the inbound queue to the messages of the exchange with the routing key
+	Binding struct {
+		Exchange   string
+		RoutingKey string
+	}
+
+	// Config defines the queue and the subscriptions of the inbound Consumer
+	Config struct {
+		Queue       string
+		Bindings    []Binding
+		Prefetch    int
+		MaxAttempts int
+		RetryDelay  time.Duration
+		Timeout     time.Duration
+	}
+
+	// Consumer receives the commands and events of other services and applies them on the users.
+	// The messages are acknowledged after the change was committed to the database. The failed messages
+	// are retried through a delayed retry queue, and the invalid messages (poison messages) and the messages
+	// what failed Config.MaxAttempts times are dead-lettered.
+	Consumer struct {
+		config  Config
+		userSvc userService
+		conn    *amqp.Connection
+		channel amqpChannel
+		tag     string
+		cancel  context.CancelFunc
+		done    chan struct{}
+	}
+)
+
+// NewConfig reads the inbound consumer configuration from the environment.
+// INBOUND_BINDINGS holds the comma separated `exchange:routing key` pairs, the consumer is disabled when it's empty.
+func NewConfig() (Config, error) {
+	bindings, err := parseBindings(common.GetEnv("INBOUND_BINDINGS", ""))
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		Queue:       common.GetEnv("INBOUND_QUEUE", "users.inbound"),
+		Bindings:    bindings,
+		Prefetch:    common.GetEnvInt("INBOUND_PREFETCH", 10),
+		MaxAttempts: common.GetEnvInt("INBOUND_MAX_ATTEMPTS", 5),
+		RetryDelay:  common.GetEnvDuration("INBOUND_RETRY_DELAY", 10*time.Second),
+		Timeout:     common.GetEnvDuration("INBOUND_TIMEOUT", 5*time.Second),
+	}, nil
+}
+
+func parseBindings(bindings string) ([]Binding, error) {
+	var parsed []Binding
+	for _, b := range strings.Split(bindings, ",") {
+		b = strings.TrimSpace(b)
+		if b == "" {
+			continue
+		}
+		exchange, routingKey, ok := strings.Cut(b, ":")
+		if !ok || exchange == "" || routingKey == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidBinding, b)
+		}
+		parsed = append(parsed, Binding{Exchange: exchange, RoutingKey: routingKey})
+	}
+	return parsed, nil
+}
+
+// Enabled is true when the consumer has bindings
+func (c Config) Enabled() bool {
+	return len(c.Bindings) > 0
+}
+
+// RetryQueue is the name of the queue where the failed messages wait for the next attempt
+func (c Config) RetryQueue() string {
+	return c.Queue + ".retry"
+}
+
+// DeadLetterExchange is the name of the exchange where the rejected messages are routed
+func (c Config) DeadLetterExchange() string {
+	return c.Queue + ".dlx"
+}
+
+// DeadLetterQueue is the name of the queue what holds the rejected messages
+func (c Config) DeadLetterQueue() string {
+	return c.Queue + ".dlq"
+}
+
+// NewConsumer starts consuming the messages of the bindings and applies them with the user service,
+// what is shared with the APIs, so the changes are published by the same event publisher.
+// The user service isn't closed by the consumer.
+func NewConsumer(config Config, svc *user.Service) (*Consumer, error) {
+	conn, err := common.DialRabbitMQ()
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newConsumer(ch, svc, config)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	return c, c.start()
+}
+
+func newConsumer(ch amqpChannel, svc userService, config Config) (*Consumer, error) {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	if err := declareTopology(ch, config); err != nil {
+		return nil, err
+	}
+
+	return &Consumer{
+		config:  config,
+		userSvc: svc,
+		channel: ch,
+		tag:     config.Queue + "-consumer",
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// declareTopology declares the bound exchanges as durable topic exchanges, the inbound queue,
+// the retry queue what returns the messages to the inbound queue after the retry delay
+// and the dead-letter exchange and queue of the rejected messages.
+func declareTopology(ch amqpChannel, config Config) error {
+	if err := ch.ExchangeDeclare(config.DeadLetterExchange(), "fanout", true, false, false, false, nil); err != nil {
+		return err
+	}
+	if _, err := ch.QueueDeclare(config.DeadLetterQueue(), true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := ch.QueueBind(config.DeadLetterQueue(), "", config.DeadLetterExchange(), false, nil); err != nil {
+		return err
+	}
+
+	if _, err := ch.QueueDeclare(config.Queue, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange": config.DeadLetterExchange(),
+	}); err != nil {
+		return err
+	}
+	for _, b := range config.Bindings {
+		if err := ch.ExchangeDeclare(b.Exchange, "topic", true, false, false, false, nil); err != nil {
+			return err
+		}
+		if err := ch.QueueBind(config.Queue, b.RoutingKey, b.Exchange, false, nil); err != nil {
+			return err
+		}
+	}
+
+	_, err := ch.QueueDeclare(config.RetryQueue(), true, false, false, false, amqp.Table{
+		"x-message-ttl":             config.RetryDelay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": config.Queue,
+	})
+	return err
+}
+
+// start consumes the messages one by one in the background until the consumer is closed
+func (c *Consumer) start() error {
+	if err := c.channel.Qos(c.config.Prefetch, 0, false); err != nil {
+		return err
+	}
+	deliveries, err := c.channel.Consume(c.config.Queue, c.tag, false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go c.run(ctx, deliveries)
+	return nil
+}
+
+func (c *Consumer) run(ctx context.Context, deliveries <-chan amqp.Delivery) {
+	defer close(c.done)
+	for {
+		select {
+		case <-ctx.Done():
+			if err := c.channel.Cancel(c.tag, false); err != nil {
+				log.Err(err).Str("queue", c.config.Queue).Msg("failed to cancel inbound consumer")
+			}
+			return
+		case d, ok := <-deliveries:
+			if !ok {
+				log.Error().Str("queue", c.config.Queue).Msg("inbound deliveries are closed")
+				return
+			}
+			if err := c.handle(ctx, d); err != nil {
+				log.Err(err).
+					Str("queue", c.config.Queue).
+					Str("message_id", d.MessageId).
+					Msg("failed to acknowledge inbound message")
+			}
+		}
+	}
+}
+
+// handle applies the message and acknowledges it after the change was committed.
+// The poison messages are dead-lettered immediately, the other failures are retried.
+func (c *Consumer) handle(ctx context.Context, d amqp.Delivery) error {
+	attempt := attemptOf(d)
+	err := c.process(ctx, d)
+	if err == nil {
+		return d.Ack(false)
+	}
+
+	logger := log.Err(err).
+		Str("queue", c.config.Queue).
+		Str("message_id", d.MessageId).
+		Str("type", messageType(d)).
+		Str(common.CorrelationID, d.CorrelationId).
+		Int("attempt", attempt)
+	if isPoison(err) || attempt >= c.config.MaxAttempts {
+		logger.Msg("dead-lettering inbound message")
+		return d.Nack(false, false)
+	}
+
+	logger.Msg("failed to process inbound message")
+	if err := c.retry(ctx, d, attempt); err != nil {
+		log.Err(err).Str("message_id", d.MessageId).Msg("failed to schedule inbound message retry")
+		return d.Nack(false, true)
+	}
+	return d.Ack(false)
+}
+
+func (c *Consumer) process(ctx context.Context, d amqp.Delivery) error {
+	t := messageType(d)
+	cmd, ok := commands[t]
+	if !ok {
+		return fmt.Errorf("%w: unknown message type %s", ErrInvalidMessage, t)
+	}
+
+	correlationID := d.CorrelationId
+	if correlationID == "" {
+		correlationID = uuid.NewString()
+	}
+	cctx, cancel := context.WithTimeout(context.WithValue(ctx, common.CorrelationID, correlationID), c.config.Timeout)
+	defer cancel()
+	return cmd(cctx, c.userSvc, d.Body)
+}
+
+// retry publishes the original message to the retry queue with the number of the attempts.
+// The original message is acknowledged only after the retry was published.
+func (c *Consumer) retry(ctx context.Context, d amqp.Delivery, attempt int) error {
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[headerAttempt] = int32(attempt)
+
+	return c.channel.PublishWithContext(ctx, "", c.config.RetryQueue(), false, false, amqp.Publishing{
+		Headers:       headers,
+		ContentType:   d.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: d.CorrelationId,
+		MessageId:     d.MessageId,
+		Type:          messageType(d),
+		Timestamp:     d.Timestamp,
+		Body:          d.Body,
+	})
+}
+
+// Close stops consuming, waits for the message in progress and releases the connections of the consumer.
+func (c *Consumer) Close(ctx context.Context) error {
+	c.cancel()
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := c.channel.Close(); err != nil {
+		log.Err(err).Msg("failed to close inbound RMQ channel")
+	}
+	if c.conn != nil {
+		if err := c.conn.Close(); err != nil {
+			log.Err(err).Msg("failed to close inbound RMQ connection")
+		}
+	}
+	return nil
+}
+
+// messageType is the AMQP type of the message, or the routing key when the type is not set
+func messageType(d amqp.Delivery) string {
+	if d.Type != "" {
+		return d.Type
+	}
+	return d.RoutingKey
+}
+
+// attemptOf returns the current attempt of the message, the retried messages have the number of the previous attempts
+func attemptOf(d amqp.Delivery) int {
+	switch a := d.Headers[headerAttempt].(type) {
+	case int32:
+		return int(a) + 1
+	case int64:
+		return int(a) + 1
+	case int:
+		return a + 1
+	}
+	return 1
+}
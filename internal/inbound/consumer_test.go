@@ -0,0 +1,211 @@
+package inbound
+
+import (
+	"context"
+	"errors"
+	"faceit/internal/user"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	exchangeDeclare    = "ExchangeDeclare"
+	queueDeclare       = "QueueDeclare"
+	queueBind          = "QueueBind"
+	publishWithContext = "PublishWithContext"
+	Get                = "Get"
+	Update             = "Update"
+
+	countryVerifiedType = "account.country_verified"
+)
+
+var userID = uuid.MustParse("9b0e5e7f-6a4b-4bd5-9c55-1d9c5b0b1b0e")
+
+type (
+	consumerTestSuite struct {
+		channelMock *mockAmqpChannel
+		userSvcMock *mockUserService
+		consumer    *Consumer
+		suite.Suite
+	}
+
+	// acknowledger records how the delivery was acknowledged
+	acknowledger struct {
+		acked   bool
+		nacked  bool
+		requeue bool
+	}
+)
+
+func (a *acknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *acknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	a.nacked = true
+	a.requeue = requeue
+	return nil
+}
+
+func (a *acknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func TestConsumerTestSuite(t *testing.T) {
+	suite.Run(t, new(consumerTestSuite))
+}
+
+func (s *consumerTestSuite) SetupTest() {
+	s.channelMock = newMockAmqpChannel(s.T())
+	s.userSvcMock = newMockUserService(s.T())
+	s.consumer = &Consumer{
+		config:  s.config(),
+		userSvc: s.userSvcMock,
+		channel: s.channelMock,
+	}
+}
+
+func (s *consumerTestSuite) TestParseBindings() {
+	bindings, err := parseBindings(" events.account:account.country_verified, events.billing:billing.#,")
+	s.NoError(err)
+	s.Equal([]Binding{
+		{Exchange: "events.account", RoutingKey: "account.country_verified"},
+		{Exchange: "events.billing", RoutingKey: "billing.#"},
+	}, bindings)
+
+	for _, b := range []string{"events.account", ":account.country_verified", "events.account:"} {
+		_, err := parseBindings(b)
+		s.ErrorIs(err, ErrInvalidBinding)
+	}
+}
+
+func (s *consumerTestSuite) TestNewConsumer_DeclaresTopology() {
+	s.channelMock.On(exchangeDeclare, "users.inbound.dlx", "fanout", true, false, false, false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.On(queueDeclare, "users.inbound.dlq", true, false, false, false, amqp.Table(nil)).Return(amqp.Queue{}, nil).Once()
+	s.channelMock.On(queueBind, "users.inbound.dlq", "", "users.inbound.dlx", false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.
+		On(queueDeclare, "users.inbound", true, false, false, false, amqp.Table{"x-dead-letter-exchange": "users.inbound.dlx"}).
+		Return(amqp.Queue{}, nil).
+		Once()
+	s.channelMock.On(exchangeDeclare, "events.account", "topic", true, false, false, false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.On(queueBind, "users.inbound", countryVerifiedType, "events.account", false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.
+		On(queueDeclare, "users.inbound.retry", true, false, false, false, amqp.Table{
+			"x-message-ttl":             int64(1000),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": "users.inbound",
+		}).
+		Return(amqp.Queue{}, nil).
+		Once()
+
+	_, err := newConsumer(s.channelMock, s.userSvcMock, s.config())
+	s.NoError(err)
+}
+
+func (s *consumerTestSuite) TestHandle_UpdatesCountryAndAcks() {
+	s.userSvcMock.On(Get, mock.Anything, userID, user.Fields(nil)).Return(&user.User{ID: userID, Country: "US"}, nil).Once()
+	s.userSvcMock.
+		On(Update, mock.Anything, userID, user.User{Country: "hu"}, "").
+		Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			_, ok := ctx.Deadline()
+			s.True(ok, "the update must have a timeout")
+		}).
+		Return(&user.User{ID: userID, Country: "HU"}, nil).
+		Once()
+
+	ack := &acknowledger{}
+	s.NoError(s.consumer.handle(context.TODO(), s.delivery(ack, `{"user_id":"`+userID.String()+`","country":"hu"}`, nil)))
+	s.True(ack.acked)
+}
+
+func (s *consumerTestSuite) TestHandle_SkipsVerifiedCountry() {
+	s.userSvcMock.On(Get, mock.Anything, userID, user.Fields(nil)).Return(&user.User{ID: userID, Country: "HU"}, nil).Once()
+
+	ack := &acknowledger{}
+	s.NoError(s.consumer.handle(context.TODO(), s.delivery(ack, `{"user_id":"`+userID.String()+`","country":"hu"}`, nil)))
+	s.True(ack.acked)
+	s.userSvcMock.AssertNotCalled(s.T(), Update, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *consumerTestSuite) TestHandle_DeadLettersPoisonMessages() {
+	s.userSvcMock.On(Get, mock.Anything, userID, user.Fields(nil)).Return(nil, user.ErrUserNotFound).Once()
+	s.userSvcMock.On(Get, mock.Anything, userID, user.Fields(nil)).Return(&user.User{ID: userID, Country: "US"}, nil).Once()
+	s.userSvcMock.On(Update, mock.Anything, userID, user.User{Country: "HUN"}, "").Return(nil, user.ErrInvalidUserInputData).Once()
+
+	for _, d := range []amqp.Delivery{
+		s.delivery(nil, `not json`, nil),
+		s.delivery(nil, `{"country":"HU"}`, nil),
+		s.delivery(nil, `{"user_id":"`+userID.String()+`","country":"HU"}`, nil),
+		s.delivery(nil, `{"user_id":"`+userID.String()+`","country":"HUN"}`, nil),
+		{Type: "account.closed", Body: []byte(`{}`)},
+	} {
+		ack := &acknowledger{}
+		d.Acknowledger = ack
+		s.NoError(s.consumer.handle(context.TODO(), d))
+		s.True(ack.nacked)
+		s.False(ack.requeue)
+	}
+	s.channelMock.AssertNotCalled(s.T(), publishWithContext, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *consumerTestSuite) TestHandle_RetriesFailedMessage() {
+	s.userSvcMock.On(Get, mock.Anything, userID, user.Fields(nil)).Return(nil, errors.New("connection refused")).Once()
+	s.channelMock.
+		On(publishWithContext, mock.Anything, "", "users.inbound.retry", false, false, mock.MatchedBy(func(msg amqp.Publishing) bool {
+			return msg.Headers[headerAttempt] == int32(2) && msg.Type == countryVerifiedType && msg.MessageId == "message-1"
+		})).
+		Return(nil).
+		Once()
+
+	ack := &acknowledger{}
+	s.NoError(s.consumer.handle(context.TODO(), s.delivery(ack, `{"user_id":"`+userID.String()+`","country":"HU"}`, amqp.Table{headerAttempt: int32(1)})))
+	s.True(ack.acked)
+}
+
+func (s *consumerTestSuite) TestHandle_RequeuesWhenRetryFails() {
+	s.userSvcMock.On(Get, mock.Anything, userID, user.Fields(nil)).Return(nil, errors.New("connection refused")).Once()
+	s.channelMock.On(publishWithContext, mock.Anything, "", "users.inbound.retry", false, false, mock.Anything).Return(amqp.ErrClosed).Once()
+
+	ack := &acknowledger{}
+	s.NoError(s.consumer.handle(context.TODO(), s.delivery(ack, `{"user_id":"`+userID.String()+`","country":"HU"}`, nil)))
+	s.True(ack.nacked)
+	s.True(ack.requeue)
+}
+
+func (s *consumerTestSuite) TestHandle_DeadLettersMessageAfterLastAttempt() {
+	s.userSvcMock.On(Get, mock.Anything, userID, user.Fields(nil)).Return(nil, errors.New("connection refused")).Once()
+
+	ack := &acknowledger{}
+	s.NoError(s.consumer.handle(context.TODO(), s.delivery(ack, `{"user_id":"`+userID.String()+`","country":"HU"}`, amqp.Table{headerAttempt: int32(2)})))
+	s.True(ack.nacked)
+	s.False(ack.requeue)
+	s.channelMock.AssertNotCalled(s.T(), publishWithContext, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *consumerTestSuite) config() Config {
+	return Config{
+		Queue:       "users.inbound",
+		Bindings:    []Binding{{Exchange: "events.account", RoutingKey: countryVerifiedType}},
+		Prefetch:    10,
+		MaxAttempts: 3,
+		RetryDelay:  time.Second,
+		Timeout:     time.Second,
+	}
+}
+
+func (s *consumerTestSuite) delivery(ack amqp.Acknowledger, body string, headers amqp.Table) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: ack,
+		Headers:      headers,
+		MessageId:    "message-1",
+		RoutingKey:   countryVerifiedType,
+		Body:         []byte(body),
+	}
+}
@@ -0,0 +1,159 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package inbound
+
+import (
+	context "context"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockAmqpChannel is an autogenerated mock type for the amqpChannel type
+type mockAmqpChannel struct {
+	mock.Mock
+}
+
+// Cancel provides a mock function with given fields: consumer, noWait
+func (_m *mockAmqpChannel) Cancel(consumer string, noWait bool) error {
+	ret := _m.Called(consumer, noWait)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, bool) error); ok {
+		r0 = rf(consumer, noWait)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Close provides a mock function with given fields:
+func (_m *mockAmqpChannel) Close() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Consume provides a mock function with given fields: queue, consumer, autoAck, exclusive, noLocal, noWait, args
+func (_m *mockAmqpChannel) Consume(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+	ret := _m.Called(queue, consumer, autoAck, exclusive, noLocal, noWait, args)
+
+	var r0 <-chan amqp091.Delivery
+	if rf, ok := ret.Get(0).(func(string, string, bool, bool, bool, bool, amqp091.Table) <-chan amqp091.Delivery); ok {
+		r0 = rf(queue, consumer, autoAck, exclusive, noLocal, noWait, args)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan amqp091.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, bool, bool, bool, bool, amqp091.Table) error); ok {
+		r1 = rf(queue, consumer, autoAck, exclusive, noLocal, noWait, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExchangeDeclare provides a mock function with given fields: name, kind, durable, autoDelete, internal, noWait, args
+func (_m *mockAmqpChannel) ExchangeDeclare(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
+	ret := _m.Called(name, kind, durable, autoDelete, internal, noWait, args)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, bool, bool, bool, bool, amqp091.Table) error); ok {
+		r0 = rf(name, kind, durable, autoDelete, internal, noWait, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishWithContext provides a mock function with given fields: ctx, exchange, key, mandatory, immediate, msg
+func (_m *mockAmqpChannel) PublishWithContext(ctx context.Context, exchange string, key string, mandatory bool, immediate bool, msg amqp091.Publishing) error {
+	ret := _m.Called(ctx, exchange, key, mandatory, immediate, msg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, bool, amqp091.Publishing) error); ok {
+		r0 = rf(ctx, exchange, key, mandatory, immediate, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Qos provides a mock function with given fields: prefetchCount, prefetchSize, global
+func (_m *mockAmqpChannel) Qos(prefetchCount int, prefetchSize int, global bool) error {
+	ret := _m.Called(prefetchCount, prefetchSize, global)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, int, bool) error); ok {
+		r0 = rf(prefetchCount, prefetchSize, global)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueueBind provides a mock function with given fields: name, key, exchange, noWait, args
+func (_m *mockAmqpChannel) QueueBind(name string, key string, exchange string, noWait bool, args amqp091.Table) error {
+	ret := _m.Called(name, key, exchange, noWait, args)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, bool, amqp091.Table) error); ok {
+		r0 = rf(name, key, exchange, noWait, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueueDeclare provides a mock function with given fields: name, durable, autoDelete, exclusive, noWait, args
+func (_m *mockAmqpChannel) QueueDeclare(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+	ret := _m.Called(name, durable, autoDelete, exclusive, noWait, args)
+
+	var r0 amqp091.Queue
+	if rf, ok := ret.Get(0).(func(string, bool, bool, bool, bool, amqp091.Table) amqp091.Queue); ok {
+		r0 = rf(name, durable, autoDelete, exclusive, noWait, args)
+	} else {
+		r0 = ret.Get(0).(amqp091.Queue)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool, bool, bool, bool, amqp091.Table) error); ok {
+		r1 = rf(name, durable, autoDelete, exclusive, noWait, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTnewMockAmqpChannel interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockAmqpChannel creates a new instance of mockAmqpChannel. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockAmqpChannel(t mockConstructorTestingTnewMockAmqpChannel) *mockAmqpChannel {
+	mock := &mockAmqpChannel{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
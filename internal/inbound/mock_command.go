@@ -0,0 +1,43 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package inbound
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockCommand is an autogenerated mock type for the command type
+type mockCommand struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: ctx, svc, body
+func (_m *mockCommand) Execute(ctx context.Context, svc userService, body []byte) error {
+	ret := _m.Called(ctx, svc, body)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, userService, []byte) error); ok {
+		r0 = rf(ctx, svc, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTnewMockCommand interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockCommand creates a new instance of mockCommand. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockCommand(t mockConstructorTestingTnewMockCommand) *mockCommand {
+	mock := &mockCommand{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,78 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package inbound
+
+import (
+	context "context"
+	user "faceit/internal/user"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// mockUserService is an autogenerated mock type for the userService type
+type mockUserService struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, id, fields
+func (_m *mockUserService) Get(ctx context.Context, id uuid.UUID, fields user.Fields) (*user.User, error) {
+	ret := _m.Called(ctx, id, fields)
+
+	var r0 *user.User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, user.Fields) *user.User); ok {
+		r0 = rf(ctx, id, fields)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*user.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, user.Fields) error); ok {
+		r1 = rf(ctx, id, fields)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, _a2, password
+func (_m *mockUserService) Update(ctx context.Context, id uuid.UUID, _a2 user.User, password string) (*user.User, error) {
+	ret := _m.Called(ctx, id, _a2, password)
+
+	var r0 *user.User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, user.User, string) *user.User); ok {
+		r0 = rf(ctx, id, _a2, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*user.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, user.User, string) error); ok {
+		r1 = rf(ctx, id, _a2, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTnewMockUserService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockUserService creates a new instance of mockUserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockUserService(t mockConstructorTestingTnewMockUserService) *mockUserService {
+	mock := &mockUserService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
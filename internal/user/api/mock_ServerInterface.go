@@ -14,13 +14,27 @@ type MockServerInterface struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: ctx
-func (_m *MockServerInterface) Create(ctx echo.Context) error {
-	ret := _m.Called(ctx)
+// BatchGet provides a mock function with given fields: ctx, params
+func (_m *MockServerInterface) BatchGet(ctx echo.Context, params BatchGetParams) error {
+	ret := _m.Called(ctx, params)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(echo.Context) error); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(echo.Context, BatchGetParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: ctx, params
+func (_m *MockServerInterface) Create(ctx echo.Context, params CreateParams) error {
+	ret := _m.Called(ctx, params)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, CreateParams) error); ok {
+		r0 = rf(ctx, params)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -42,13 +56,13 @@ func (_m *MockServerInterface) DeleteByID(ctx echo.Context, id uuid.UUID) error
 	return r0
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *MockServerInterface) GetByID(ctx echo.Context, id uuid.UUID) error {
-	ret := _m.Called(ctx, id)
+// GetByID provides a mock function with given fields: ctx, id, params
+func (_m *MockServerInterface) GetByID(ctx echo.Context, id uuid.UUID, params GetByIDParams) error {
+	ret := _m.Called(ctx, id, params)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, GetByIDParams) error); ok {
+		r0 = rf(ctx, id, params)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,6 +84,48 @@ func (_m *MockServerInterface) List(ctx echo.Context, params ListParams) error {
 	return r0
 }
 
+// ListEventKeys provides a mock function with given fields: ctx
+func (_m *MockServerInterface) ListEventKeys(ctx echo.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, params
+func (_m *MockServerInterface) Search(ctx echo.Context, params SearchParams) error {
+	ret := _m.Called(ctx, params)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, SearchParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamEvents provides a mock function with given fields: ctx, params
+func (_m *MockServerInterface) StreamEvents(ctx echo.Context, params StreamEventsParams) error {
+	ret := _m.Called(ctx, params)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, StreamEventsParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateByID provides a mock function with given fields: ctx, id
 func (_m *MockServerInterface) UpdateByID(ctx echo.Context, id uuid.UUID) error {
 	ret := _m.Called(ctx, id)
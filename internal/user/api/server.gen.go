@@ -19,16 +19,31 @@ type ServerInterface interface {
 	List(ctx echo.Context, params ListParams) error
 	// Create user
 	// (POST /users)
-	Create(ctx echo.Context) error
+	Create(ctx echo.Context, params CreateParams) error
+	// Stream of user events
+	// (GET /users/events)
+	StreamEvents(ctx echo.Context, params StreamEventsParams) error
+	// Keys to verify the signatures of the user events
+	// (GET /users/events/keys)
+	ListEventKeys(ctx echo.Context) error
+	// Search users by a filter expression
+	// (POST /users/search)
+	Search(ctx echo.Context, params SearchParams) error
 	// Delete user by id
 	// (DELETE /users/{id})
 	DeleteByID(ctx echo.Context, id uuid.UUID) error
 	// Get user by id
 	// (GET /users/{id})
-	GetByID(ctx echo.Context, id uuid.UUID) error
+	GetByID(ctx echo.Context, id uuid.UUID, params GetByIDParams) error
 	// Update user by id
 	// (PATCH /users/{id})
 	UpdateByID(ctx echo.Context, id uuid.UUID) error
+	// Replace user by id
+	// (PUT /users/{id})
+	ReplaceByID(ctx echo.Context, id uuid.UUID) error
+	// Get users by ids
+	// (POST /users:batchGet)
+	BatchGet(ctx echo.Context, params BatchGetParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -91,6 +106,27 @@ func (w *ServerInterfaceWrapper) List(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter country: %s", err))
 	}
 
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", ctx.QueryParams(), &params.Fields)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fields: %s", err))
+	}
+
+	// ------------- Optional query parameter "id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "id", ctx.QueryParams(), &params.Id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.List(ctx, params)
 	return err
@@ -100,8 +136,104 @@ func (w *ServerInterfaceWrapper) List(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) Create(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.Create(ctx)
+	err = w.Handler.Create(ctx, params)
+	return err
+}
+
+// StreamEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) StreamEvents(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamEventsParams
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", ctx.QueryParams(), &params.Type)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter type: %s", err))
+	}
+
+	// ------------- Optional query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "user_id", ctx.QueryParams(), &params.UserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID int64
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Last-Event-ID, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, valueList[0], &LastEventID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Last-Event-ID: %s", err))
+		}
+
+		params.LastEventID = &LastEventID
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.StreamEvents(ctx, params)
+	return err
+}
+
+// ListEventKeys converts echo context to params.
+func (w *ServerInterfaceWrapper) ListEventKeys(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListEventKeys(ctx)
+	return err
+}
+
+// Search converts echo context to params.
+func (w *ServerInterfaceWrapper) Search(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "pagesize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pagesize", ctx.QueryParams(), &params.Pagesize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pagesize: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.Search(ctx, params)
 	return err
 }
 
@@ -132,8 +264,17 @@ func (w *ServerInterfaceWrapper) GetByID(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetByIDParams
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", ctx.QueryParams(), &params.Fields)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fields: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetByID(ctx, id)
+	err = w.Handler.GetByID(ctx, id, params)
 	return err
 }
 
@@ -153,6 +294,40 @@ func (w *ServerInterfaceWrapper) UpdateByID(ctx echo.Context) error {
 	return err
 }
 
+// ReplaceByID converts echo context to params.
+func (w *ServerInterfaceWrapper) ReplaceByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id uuid.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ReplaceByID(ctx, id)
+	return err
+}
+
+// BatchGet converts echo context to params.
+func (w *ServerInterfaceWrapper) BatchGet(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params BatchGetParams
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", ctx.QueryParams(), &params.Fields)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter fields: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.BatchGet(ctx, params)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -183,8 +358,13 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 
 	router.GET(baseURL+"/users", wrapper.List)
 	router.POST(baseURL+"/users", wrapper.Create)
+	router.GET(baseURL+"/users/events", wrapper.StreamEvents)
+	router.GET(baseURL+"/users/events/keys", wrapper.ListEventKeys)
+	router.POST(baseURL+"/users/search", wrapper.Search)
 	router.DELETE(baseURL+"/users/:id", wrapper.DeleteByID)
 	router.GET(baseURL+"/users/:id", wrapper.GetByID)
 	router.PATCH(baseURL+"/users/:id", wrapper.UpdateByID)
+	router.PUT(baseURL+"/users/:id", wrapper.ReplaceByID)
+	router.POST(baseURL+"/users:batchGet", wrapper.BatchGet)
 
 }
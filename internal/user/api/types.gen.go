@@ -10,6 +10,71 @@ import (
 	"github.com/google/uuid"
 )
 
+// Defines values for EventKeyAlg.
+const (
+	Ed25519 EventKeyAlg = "ed25519"
+)
+
+// Defines values for FieldErrorRule.
+const (
+	FieldErrorRuleEmail     FieldErrorRule = "email"
+	FieldErrorRuleLength    FieldErrorRule = "length"
+	FieldErrorRuleMinLength FieldErrorRule = "min_length"
+)
+
+// Defines values for FilterField.
+const (
+	FilterFieldCountry   FilterField = "country"
+	FilterFieldCreatedAt FilterField = "created_at"
+	FilterFieldEmail     FilterField = "email"
+	FilterFieldFirstName FilterField = "first_name"
+	FilterFieldId        FilterField = "id"
+	FilterFieldLastName  FilterField = "last_name"
+	FilterFieldNickname  FilterField = "nickname"
+	FilterFieldUpdatedAt FilterField = "updated_at"
+)
+
+// Defines values for FilterOp.
+const (
+	Contains FilterOp = "contains"
+	Eq       FilterOp = "eq"
+	In       FilterOp = "in"
+	Prefix   FilterOp = "prefix"
+	Range    FilterOp = "range"
+)
+
+// Defines values for JSONPatchOperationOp.
+const (
+	Add     JSONPatchOperationOp = "add"
+	Copy    JSONPatchOperationOp = "copy"
+	Move    JSONPatchOperationOp = "move"
+	Remove  JSONPatchOperationOp = "remove"
+	Replace JSONPatchOperationOp = "replace"
+	Test    JSONPatchOperationOp = "test"
+)
+
+// Defines values for StreamEventsParamsType.
+const (
+	USERCOUNTRYCHANGED  StreamEventsParamsType = "USER_COUNTRY_CHANGED"
+	USERCREATED         StreamEventsParamsType = "USER_CREATED"
+	USERDELETED         StreamEventsParamsType = "USER_DELETED"
+	USEREMAILCHANGED    StreamEventsParamsType = "USER_EMAIL_CHANGED"
+	USERNICKNAMECHANGED StreamEventsParamsType = "USER_NICKNAME_CHANGED"
+	USERPASSWORDCHANGED StreamEventsParamsType = "USER_PASSWORD_CHANGED"
+	USERUPDATED         StreamEventsParamsType = "USER_UPDATED"
+)
+
+// BatchGetRequest defines model for BatchGetRequest.
+type BatchGetRequest struct {
+	Ids []uuid.UUID `json:"ids"`
+}
+
+// BatchGetResult defines model for BatchGetResult.
+type BatchGetResult struct {
+	Missing []uuid.UUID    `json:"missing"`
+	Users   []UserResponse `json:"users"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	CorrelationId uuid.UUID `json:"correlation_id"`
@@ -18,6 +83,100 @@ type Error struct {
 	Time          time.Time `json:"time"`
 }
 
+// EventKey defines model for EventKey.
+type EventKey struct {
+	Alg EventKeyAlg `json:"alg"`
+
+	// Kid key id what is sent in the `x-signature-key-id` header
+	Kid string `json:"kid"`
+
+	// PublicKey base64 encoded raw Ed25519 public key
+	PublicKey []byte `json:"public_key"`
+}
+
+// EventKeyAlg defines model for EventKey.Alg.
+type EventKeyAlg string
+
+// EventKeys defines model for EventKeys.
+type EventKeys struct {
+	Keys []EventKey `json:"keys"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string         `json:"field"`
+	Message string         `json:"message"`
+	Rule    FieldErrorRule `json:"rule"`
+}
+
+// FieldErrorRule defines model for FieldError.Rule.
+type FieldErrorRule string
+
+// Filter defines model for Filter.
+type Filter struct {
+	And   *[]Filter    `json:"and,omitempty"`
+	Field *FilterField `json:"field,omitempty"`
+	Not   *Filter      `json:"not,omitempty"`
+	Op    *FilterOp    `json:"op,omitempty"`
+	Or    *[]Filter    `json:"or,omitempty"`
+
+	// Value string, array of strings or an object with `from` and `to` date-time depending on the operator
+	Value *interface{} `json:"value,omitempty"`
+}
+
+// FilterField defines model for Filter.Field.
+type FilterField string
+
+// FilterOp defines model for Filter.Op.
+type FilterOp string
+
+// JSONPatch defines model for JSONPatch.
+type JSONPatch = []JSONPatchOperation
+
+// JSONPatchOperation defines model for JSONPatchOperation.
+type JSONPatchOperation struct {
+	// From JSON Pointer of the source field of `move` and `copy`
+	From *string              `json:"from,omitempty"`
+	Op   JSONPatchOperationOp `json:"op"`
+
+	// Path JSON Pointer of the changed field, i.e. `/nickname`
+	Path string `json:"path"`
+
+	// Value value of `add`, `replace` and `test`
+	Value *interface{} `json:"value,omitempty"`
+}
+
+// JSONPatchOperationOp defines model for JSONPatchOperation.Op.
+type JSONPatchOperationOp string
+
+// Problem RFC 7807 problem details, every error response is returned in this shape with `application/problem+json`
+// content type instead of `Error` when the request accepts `application/problem+json`
+type Problem struct {
+	// Code stable code of the error, i.e. `user_not_found`, `invalid_user`, `invalid_sort`, or the snake case status text
+	// (i.e. `bad_request`) when the error has no specific code
+	Code          string  `json:"code"`
+	CorrelationId string  `json:"correlation_id"`
+	Detail        *string `json:"detail,omitempty"`
+
+	// Errors every invalid field of the user with the failed rule
+	Errors *[]FieldError `json:"errors,omitempty"`
+
+	// Instance path of the request
+	Instance *string `json:"instance,omitempty"`
+	Status   int     `json:"status"`
+
+	// Title the text of the status code
+	Title string `json:"title"`
+
+	// Type always `about:blank`, the problem is identified by the `code`
+	Type string `json:"type"`
+}
+
+// SearchRequest defines model for SearchRequest.
+type SearchRequest struct {
+	Filter Filter `json:"filter"`
+}
+
 // UpdateUserWithPassword defines model for UpdateUserWithPassword.
 type UpdateUserWithPassword struct {
 	Country   *string              `json:"country,omitempty"`
@@ -81,10 +240,76 @@ type ListParams struct {
 
 	// Country filter results by country code
 	Country *string `form:"country,omitempty" json:"country,omitempty"`
+
+	// Sort comma separated fields to sort by, prefixed by `-` for descending order (i.e. `last_name,-created_at`).
+	// Sortable fields: `id`, `last_name`, `nickname`, `email`, `country`, `created_at`
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Fields comma separated fields of the returned users (i.e. `id,nickname`), the `id` is always returned.
+	// Every field is returned when it's not set
+	Fields *string `form:"fields,omitempty" json:"fields,omitempty"`
+
+	// Id ids of the users to look up (i.e. `id=...&id=...`), at most 100. The found users are returned in the order
+	// of the ids and the ids of the missing users are listed in the `Missing-Ids` header.
+	// It can't be combined with the other parameters except `fields`
+	Id *[]uuid.UUID `form:"id,omitempty" json:"id,omitempty"`
+}
+
+// CreateParams defines parameters for Create.
+type CreateParams struct {
+	// IdempotencyKey unique key of the request chosen by the client (i.e. a UUID)
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
+}
+
+// StreamEventsParams defines parameters for StreamEvents.
+type StreamEventsParams struct {
+	// Type filter events by type
+	Type *[]StreamEventsParamsType `form:"type,omitempty" json:"type,omitempty"`
+
+	// UserId filter events by user id
+	UserId *[]uuid.UUID `form:"user_id,omitempty" json:"user_id,omitempty"`
+
+	// LastEventID id of the last received event
+	LastEventID *int64 `json:"Last-Event-ID,omitempty"`
+}
+
+// StreamEventsParamsType defines parameters for StreamEvents.
+type StreamEventsParamsType string
+
+// SearchParams defines parameters for Search.
+type SearchParams struct {
+	// Page page number
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// Pagesize number of listed items
+	Pagesize *int `form:"pagesize,omitempty" json:"pagesize,omitempty"`
+}
+
+// GetByIDParams defines parameters for GetByID.
+type GetByIDParams struct {
+	// Fields comma separated fields of the returned users (i.e. `id,nickname`), the `id` is always returned.
+	// Every field is returned when it's not set
+	Fields *string `form:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// BatchGetParams defines parameters for BatchGet.
+type BatchGetParams struct {
+	// Fields comma separated fields of the returned users (i.e. `id,nickname`), the `id` is always returned.
+	// Every field is returned when it's not set
+	Fields *string `form:"fields,omitempty" json:"fields,omitempty"`
 }
 
 // CreateJSONRequestBody defines body for Create for application/json ContentType.
 type CreateJSONRequestBody = UserWithPassword
 
+// SearchJSONRequestBody defines body for Search for application/json ContentType.
+type SearchJSONRequestBody = SearchRequest
+
 // UpdateByIDJSONRequestBody defines body for UpdateByID for application/json ContentType.
 type UpdateByIDJSONRequestBody = UpdateUserWithPassword
+
+// ReplaceByIDJSONRequestBody defines body for ReplaceByID for application/json ContentType.
+type ReplaceByIDJSONRequestBody = UserWithPassword
+
+// BatchGetJSONRequestBody defines body for BatchGet for application/json ContentType.
+type BatchGetJSONRequestBody = BatchGetRequest
@@ -0,0 +1,235 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package apiv2
+
+import (
+	echo "github.com/labstack/echo/v4"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockEchoRouter is an autogenerated mock type for the EchoRouter type
+type MockEchoRouter struct {
+	mock.Mock
+}
+
+// CONNECT provides a mock function with given fields: path, h, m
+func (_m *MockEchoRouter) CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	_va := make([]interface{}, len(m))
+	for _i := range m {
+		_va[_i] = m[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, path, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *echo.Route
+	if rf, ok := ret.Get(0).(func(string, echo.HandlerFunc, ...echo.MiddlewareFunc) *echo.Route); ok {
+		r0 = rf(path, h, m...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*echo.Route)
+		}
+	}
+
+	return r0
+}
+
+// DELETE provides a mock function with given fields: path, h, m
+func (_m *MockEchoRouter) DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	_va := make([]interface{}, len(m))
+	for _i := range m {
+		_va[_i] = m[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, path, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *echo.Route
+	if rf, ok := ret.Get(0).(func(string, echo.HandlerFunc, ...echo.MiddlewareFunc) *echo.Route); ok {
+		r0 = rf(path, h, m...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*echo.Route)
+		}
+	}
+
+	return r0
+}
+
+// GET provides a mock function with given fields: path, h, m
+func (_m *MockEchoRouter) GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	_va := make([]interface{}, len(m))
+	for _i := range m {
+		_va[_i] = m[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, path, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *echo.Route
+	if rf, ok := ret.Get(0).(func(string, echo.HandlerFunc, ...echo.MiddlewareFunc) *echo.Route); ok {
+		r0 = rf(path, h, m...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*echo.Route)
+		}
+	}
+
+	return r0
+}
+
+// HEAD provides a mock function with given fields: path, h, m
+func (_m *MockEchoRouter) HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	_va := make([]interface{}, len(m))
+	for _i := range m {
+		_va[_i] = m[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, path, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *echo.Route
+	if rf, ok := ret.Get(0).(func(string, echo.HandlerFunc, ...echo.MiddlewareFunc) *echo.Route); ok {
+		r0 = rf(path, h, m...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*echo.Route)
+		}
+	}
+
+	return r0
+}
+
+// OPTIONS provides a mock function with given fields: path, h, m
+func (_m *MockEchoRouter) OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	_va := make([]interface{}, len(m))
+	for _i := range m {
+		_va[_i] = m[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, path, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *echo.Route
+	if rf, ok := ret.Get(0).(func(string, echo.HandlerFunc, ...echo.MiddlewareFunc) *echo.Route); ok {
+		r0 = rf(path, h, m...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*echo.Route)
+		}
+	}
+
+	return r0
+}
+
+// PATCH provides a mock function with given fields: path, h, m
+func (_m *MockEchoRouter) PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	_va := make([]interface{}, len(m))
+	for _i := range m {
+		_va[_i] = m[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, path, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *echo.Route
+	if rf, ok := ret.Get(0).(func(string, echo.HandlerFunc, ...echo.MiddlewareFunc) *echo.Route); ok {
+		r0 = rf(path, h, m...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*echo.Route)
+		}
+	}
+
+	return r0
+}
+
+// POST provides a mock function with given fields: path, h, m
+func (_m *MockEchoRouter) POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	_va := make([]interface{}, len(m))
+	for _i := range m {
+		_va[_i] = m[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, path, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *echo.Route
+	if rf, ok := ret.Get(0).(func(string, echo.HandlerFunc, ...echo.MiddlewareFunc) *echo.Route); ok {
+		r0 = rf(path, h, m...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*echo.Route)
+		}
+	}
+
+	return r0
+}
+
+// PUT provides a mock function with given fields: path, h, m
+func (_m *MockEchoRouter) PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	_va := make([]interface{}, len(m))
+	for _i := range m {
+		_va[_i] = m[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, path, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *echo.Route
+	if rf, ok := ret.Get(0).(func(string, echo.HandlerFunc, ...echo.MiddlewareFunc) *echo.Route); ok {
+		r0 = rf(path, h, m...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*echo.Route)
+		}
+	}
+
+	return r0
+}
+
+// TRACE provides a mock function with given fields: path, h, m
+func (_m *MockEchoRouter) TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	_va := make([]interface{}, len(m))
+	for _i := range m {
+		_va[_i] = m[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, path, h)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *echo.Route
+	if rf, ok := ret.Get(0).(func(string, echo.HandlerFunc, ...echo.MiddlewareFunc) *echo.Route); ok {
+		r0 = rf(path, h, m...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*echo.Route)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMockEchoRouter interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockEchoRouter creates a new instance of MockEchoRouter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockEchoRouter(t mockConstructorTestingTNewMockEchoRouter) *MockEchoRouter {
+	mock := &MockEchoRouter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
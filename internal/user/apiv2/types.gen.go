@@ -0,0 +1,121 @@
+// Package apiv2 provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.12.3 DO NOT EDIT.
+package apiv2
+
+import (
+	"time"
+
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/google/uuid"
+)
+
+// Defines values for FieldErrorRule.
+const (
+	Email     FieldErrorRule = "email"
+	Length    FieldErrorRule = "length"
+	MinLength FieldErrorRule = "min_length"
+)
+
+// CreateUser defines model for CreateUser.
+type CreateUser struct {
+	Country   string              `json:"country"`
+	Email     openapi_types.Email `json:"email"`
+	FirstName string              `json:"first_name"`
+	LastName  string              `json:"last_name"`
+	Nickname  string              `json:"nickname"`
+	Password  *string             `json:"password,omitempty"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string         `json:"field"`
+	Message string         `json:"message"`
+	Rule    FieldErrorRule `json:"rule"`
+}
+
+// FieldErrorRule defines model for FieldError.Rule.
+type FieldErrorRule string
+
+// Problem RFC 7807 problem details of every error response
+type Problem struct {
+	// Code stable code of the error, i.e. `user_not_found`, `invalid_user`, `email_taken`, or the snake case status text
+	// (i.e. `bad_request`) when the error has no specific code
+	Code          string  `json:"code"`
+	CorrelationId string  `json:"correlation_id"`
+	Detail        *string `json:"detail,omitempty"`
+
+	// Errors every invalid field of the user with the failed rule
+	Errors *[]FieldError `json:"errors,omitempty"`
+
+	// Instance path of the request
+	Instance *string `json:"instance,omitempty"`
+	Status   int     `json:"status"`
+
+	// Title the text of the status code
+	Title string `json:"title"`
+
+	// Type always `about:blank`, the problem is identified by the `code`
+	Type string `json:"type"`
+}
+
+// UpdateUser defines model for UpdateUser.
+type UpdateUser struct {
+	Country   *string              `json:"country,omitempty"`
+	Email     *openapi_types.Email `json:"email,omitempty"`
+	FirstName *string              `json:"first_name,omitempty"`
+	LastName  *string              `json:"last_name,omitempty"`
+	Nickname  *string              `json:"nickname,omitempty"`
+	Password  *string              `json:"password,omitempty"`
+}
+
+// User defines model for User.
+type User struct {
+	Country   string              `json:"country"`
+	CreatedAt time.Time           `json:"created_at"`
+	Email     openapi_types.Email `json:"email"`
+	FirstName string              `json:"first_name"`
+	Id        uuid.UUID           `json:"id"`
+	LastName  string              `json:"last_name"`
+	Nickname  string              `json:"nickname"`
+	UpdatedAt *time.Time          `json:"updated_at,omitempty"`
+}
+
+// UserEnvelope defines model for UserEnvelope.
+type UserEnvelope struct {
+	Data User `json:"data"`
+}
+
+// UserPage defines model for UserPage.
+type UserPage struct {
+	Data []User `json:"data"`
+
+	// NextCursor cursor of the next page, it's missing on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// ID defines model for ID.
+type ID = uuid.UUID
+
+// ListParams defines parameters for List.
+type ListParams struct {
+	// Cursor the `next_cursor` of the previous page, the first page is returned when it's not set
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit number of listed users
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// CreateParams defines parameters for Create.
+type CreateParams struct {
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
+}
+
+// CreateJSONRequestBody defines body for Create for application/json ContentType.
+type CreateJSONRequestBody = CreateUser
+
+// UpdateByIDJSONRequestBody defines body for UpdateByID for application/json ContentType.
+type UpdateByIDJSONRequestBody = UpdateUser
+
+// ReplaceByIDJSONRequestBody defines body for ReplaceByID for application/json ContentType.
+type ReplaceByIDJSONRequestBody = CreateUser
@@ -0,0 +1,463 @@
+package user
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"faceit/internal/common"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// BackpressurePolicy defines what happens with a new event when the publish queue is full.
+type BackpressurePolicy string
+
+const (
+	// BackpressureBlock waits until the queue has free capacity or the request context is done.
+	BackpressureBlock BackpressurePolicy = "block"
+	// BackpressureDrop discards the event and increases the dropped events metric.
+	BackpressureDrop BackpressurePolicy = "drop"
+	// BackpressureSpill appends the event to the spill file what is replayed on the next start.
+	BackpressureSpill BackpressurePolicy = "spill"
+)
+
+var (
+	ErrPublisherClosed = errors.New("event publisher is closed")
+	ErrEventDropped    = errors.New("event publish queue is full, event dropped")
+
+	publishedEvents = expvar.NewInt("user_events_published")
+	failedEvents    = expvar.NewInt("user_events_failed")
+	droppedEvents   = expvar.NewInt("user_events_dropped")
+	spilledEvents   = expvar.NewInt("user_events_spilled")
+)
+
+type (
+	eventSender interface {
+		publish(ctx context.Context, event UserEvent) error
+		close(ctx context.Context) error
+	}
+
+	queuedEvent struct {
+		CorrelationID string    `json:"correlation_id"`
+		Event         UserEvent `json:"event"`
+		// Notified is set when the listeners got the event, so the spilled events don't notify them again on replay
+		Notified bool `json:"notified,omitempty"`
+		// done is called with true when the event was published or spilled, i.e. to know when a replay is finished
+		done func(kept bool)
+	}
+
+	// AsyncEventPublisherConfig holds the tuning parameters of the publish queue.
+	AsyncEventPublisherConfig struct {
+		QueueSize    int
+		Workers      int
+		Backpressure BackpressurePolicy
+		SpillFile    string
+		// PublishAttempts is the number of the attempts to publish an event before it's spilled or dropped
+		PublishAttempts int
+		// RetryBackoff is the wait before the first retry, it's doubled for every next one
+		RetryBackoff time.Duration
+	}
+
+	// AsyncEventPublisher queues the user events in memory and publishes them from worker goroutines,
+	// so the callers are not blocked by the message broker. Every worker has its own queue and the events of a user
+	// are always queued to the same worker, so they are published in order.
+	AsyncEventPublisher struct {
+		sender eventSender
+		config AsyncEventPublisherConfig
+		queues []chan queuedEvent
+		ctx    context.Context
+		cancel context.CancelFunc
+		mu     sync.RWMutex
+		closed bool
+		spill  *eventSpill
+		wg     sync.WaitGroup
+		// notifier forwards every queued event once to the listeners, after its publish attempts
+		notifier eventNotifier
+	}
+)
+
+// NewAsyncEventPublisherConfig reads the publish queue configuration from the environment.
+func NewAsyncEventPublisherConfig() AsyncEventPublisherConfig {
+	return AsyncEventPublisherConfig{
+		QueueSize:    common.GetEnvInt("EVENT_QUEUE_SIZE", 1000),
+		Workers:      common.GetEnvInt("EVENT_PUBLISH_WORKERS", 4),
+		Backpressure: BackpressurePolicy(strings.ToLower(common.GetEnv("EVENT_BACKPRESSURE", string(BackpressureBlock)))),
+		SpillFile:    common.GetEnv("EVENT_SPILL_FILE", "user_events.spill"),
+
+		PublishAttempts: common.GetEnvInt("EVENT_PUBLISH_ATTEMPTS", 3),
+		RetryBackoff:    common.GetEnvDuration("EVENT_PUBLISH_RETRY_BACKOFF", 100*time.Millisecond),
+	}
+}
+
+// NewAsyncEventPublisher starts the publisher workers.
+// When the backpressure policy is BackpressureSpill the previously spilled events are queued again.
+func NewAsyncEventPublisher(sender eventSender, config AsyncEventPublisherConfig) (*AsyncEventPublisher, error) {
+	switch config.Backpressure {
+	case BackpressureBlock, BackpressureDrop, BackpressureSpill:
+	default:
+		return nil, fmt.Errorf("unknown backpressure policy %q", config.Backpressure)
+	}
+	if config.QueueSize < 1 || config.Workers < 1 {
+		return nil, errors.New("event queue size and publish workers must be positive numbers")
+	}
+	if config.PublishAttempts < 1 {
+		config.PublishAttempts = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &AsyncEventPublisher{
+		sender: sender,
+		config: config,
+		queues: make([]chan queuedEvent, config.Workers),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	queueSize := config.QueueSize / config.Workers
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan queuedEvent, queueSize)
+	}
+
+	var spilled []queuedEvent
+	if config.Backpressure == BackpressureSpill {
+		var err error
+		if spilled, err = takeSpill(config.SpillFile); err != nil {
+			cancel()
+			return nil, err
+		}
+		if p.spill, err = openSpill(config.SpillFile); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	for _, q := range p.queues {
+		p.wg.Add(1)
+		go p.work(q)
+	}
+
+	if len(spilled) > 0 {
+		log.Info().Int("events", len(spilled)).Msg("replaying spilled user events")
+		go p.replaySpilled(spilled)
+	}
+
+	return p, nil
+}
+
+// QueueLength returns the number of events waiting to be published.
+func (p *AsyncEventPublisher) QueueLength() int {
+	length := 0
+	for _, q := range p.queues {
+		length += len(q)
+	}
+	return length
+}
+
+func (p *AsyncEventPublisher) publish(ctx context.Context, event UserEvent) error {
+	return p.enqueue(ctx, queuedEvent{
+		CorrelationID: common.GetCorrelationID(ctx),
+		Event:         event,
+	})
+}
+
+// close stops accepting new events and waits until the queued events are published.
+// When the context is done before the queue is drained, the remaining events are spilled or dropped
+// according to the backpressure policy.
+func (p *AsyncEventPublisher) close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	for _, q := range p.queues {
+		close(q)
+	}
+	p.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		log.Warn().Int("events", p.QueueLength()).Msg("event queue was not drained before shutdown")
+		p.cancel()
+		<-drained
+		err = ctx.Err()
+	}
+	p.cancel()
+
+	if p.spill != nil {
+		if e := p.spill.close(); e != nil {
+			log.Err(e).Msg("failed to close event spill file")
+		}
+	}
+
+	if e := p.sender.close(ctx); e != nil && err == nil {
+		err = e
+	}
+	return err
+}
+
+func (p *AsyncEventPublisher) enqueue(ctx context.Context, e queuedEvent) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+
+	queue := p.queueOf(e.Event.UserID)
+	select {
+	case queue <- e:
+		return nil
+	default:
+	}
+
+	switch p.config.Backpressure {
+	case BackpressureBlock:
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		select {
+		case queue <- e:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	default:
+		return p.overflow(e)
+	}
+}
+
+// queueOf returns the queue of the user, so the events of the same user are published by the same worker in order
+func (p *AsyncEventPublisher) queueOf(userID uuid.UUID) chan queuedEvent {
+	h := fnv.New32a()
+	_, _ = h.Write(userID[:])
+	return p.queues[h.Sum32()%uint32(len(p.queues))]
+}
+
+// overflow spills the event when the spill file is open, otherwise drops it
+func (p *AsyncEventPublisher) overflow(e queuedEvent) error {
+	if p.spill != nil {
+		if err := p.spill.write(e); err != nil {
+			log.Err(err).
+				Str(common.CorrelationID, e.CorrelationID).
+				Stringer("ID", e.Event.UserID).
+				Msg("failed to spill user event")
+		} else {
+			spilledEvents.Add(1)
+			e.finish(true)
+			return nil
+		}
+	}
+
+	droppedEvents.Add(1)
+	log.Warn().
+		Str(common.CorrelationID, e.CorrelationID).
+		Stringer("ID", e.Event.UserID).
+		Str("type", string(e.Event.Type)).
+		Int64("dropped_events", droppedEvents.Value()).
+		Msg("user event dropped")
+	e.finish(false)
+	return ErrEventDropped
+}
+
+func (p *AsyncEventPublisher) work(queue chan queuedEvent) {
+	defer p.wg.Done()
+	for e := range queue {
+		if p.ctx.Err() != nil {
+			_ = p.overflow(e)
+			continue
+		}
+
+		err := p.publishWithRetry(e)
+		if !e.Notified {
+			p.notifier.notify(context.WithValue(p.ctx, common.CorrelationID, e.CorrelationID), e.Event)
+			e.Notified = true
+		}
+		if err != nil {
+			failedEvents.Add(1)
+			log.Err(err).
+				Str(common.CorrelationID, e.CorrelationID).
+				Stringer("ID", e.Event.UserID).
+				Str("type", string(e.Event.Type)).
+				Msg("failed to publish user event")
+			// the failed event is kept in the spill file when it's enabled
+			_ = p.overflow(e)
+			continue
+		}
+		publishedEvents.Add(1)
+		e.finish(true)
+	}
+}
+
+// publishWithRetry publishes the event with exponential backoff between the attempts. The worker waits for the
+// retries, so the next events of the user aren't published before it.
+func (p *AsyncEventPublisher) publishWithRetry(e queuedEvent) error {
+	ctx := context.WithValue(p.ctx, common.CorrelationID, e.CorrelationID)
+	backoff := p.config.RetryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = p.sender.publish(ctx, e.Event); err == nil || attempt >= p.config.PublishAttempts {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-p.ctx.Done():
+			return err
+		}
+	}
+}
+
+// replaySpilled queues the spilled events of the previous run again and removes the taken spill file only when
+// every event was published or spilled again, so they aren't lost when the service stops during the replay.
+func (p *AsyncEventPublisher) replaySpilled(events []queuedEvent) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	kept := true
+	for _, e := range events {
+		wg.Add(1)
+		e.done = func(ok bool) {
+			mu.Lock()
+			kept = kept && ok
+			mu.Unlock()
+			wg.Done()
+		}
+		if err := p.enqueue(p.ctx, e); err != nil && !errors.Is(err, ErrEventDropped) {
+			// the event wasn't queued, so it's only in the taken spill file
+			e.finish(false)
+		}
+	}
+	wg.Wait()
+
+	if !kept {
+		log.Warn().Str("file", replaySpillFile(p.config.SpillFile)).Msg("spilled user events were not replayed, they are replayed on the next start")
+		return
+	}
+	if err := os.Remove(replaySpillFile(p.config.SpillFile)); err != nil {
+		log.Err(err).Msg("failed to remove replayed spill file")
+	}
+}
+
+// finish calls the done callback of the event when it's set
+func (e queuedEvent) finish(kept bool) {
+	if e.done != nil {
+		e.done(kept)
+	}
+}
+
+type eventSpill struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// openSpill opens the spill file for appending the overflowed events
+func openSpill(path string) (*eventSpill, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &eventSpill{file: f}, nil
+}
+
+// replaySpillFile is the file of the spilled events while they are replayed
+func replaySpillFile(path string) string {
+	return path + ".replay"
+}
+
+// takeSpill moves the spilled events to the replay file and returns every event of it. The replay file is kept
+// until the events are published, so the events of an interrupted replay are replayed again on the next start.
+func takeSpill(path string) ([]queuedEvent, error) {
+	replayPath := replaySpillFile(path)
+	if err := appendFile(replayPath, path); err != nil {
+		return nil, err
+	}
+	return readSpill(replayPath)
+}
+
+// appendFile appends the content of the src file to the dst file and removes the src file, if it exists
+func appendFile(dst string, src string) error {
+	content, err := os.ReadFile(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+func readSpill(path string) ([]queuedEvent, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []queuedEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e queuedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			log.Err(err).Str("line", scanner.Text()).Msg("skipping invalid spilled user event")
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+func (s *eventSpill) write(e queuedEvent) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+func (s *eventSpill) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,290 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"faceit/internal/common"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	publish = "publish"
+	closeFn = "close"
+)
+
+type (
+	asyncEventPublisherTestSuite struct {
+		senderMock *mockEventSender
+		suite.Suite
+	}
+)
+
+func TestAsyncEventPublisherTestSuite(t *testing.T) {
+	suite.Run(t, new(asyncEventPublisherTestSuite))
+}
+
+func (s *asyncEventPublisherTestSuite) SetupTest() {
+	s.senderMock = newMockEventSender(s.T())
+}
+
+func (s *asyncEventPublisherTestSuite) TestPublish() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.TODO(), common.CorrelationID, "test-correlation-id")
+	s.senderMock.
+		On(publish, mock.MatchedBy(func(c context.Context) bool {
+			return common.GetCorrelationID(c) == "test-correlation-id"
+		}), mock.MatchedBy(func(e UserEvent) bool {
+			return e.Type == UserEventTypeDeleted && e.UserID == userID && e.Version == 2 && e.EventID != uuid.Nil
+		})).
+		Return(nil).
+		Once()
+	s.senderMock.On(closeFn, mock.Anything).Return(nil).Once()
+
+	p, err := NewAsyncEventPublisher(s.senderMock, s.config(BackpressureBlock, ""))
+	s.Require().NoError(err)
+
+	s.NoError(p.publish(ctx, newUserEvent(UserEventTypeDeleted, userID, 2, nil)))
+	s.NoError(p.close(context.TODO()))
+}
+
+func (s *asyncEventPublisherTestSuite) TestPublish_DropsEventsWhenQueueIsFull() {
+	release := s.blockSender()
+	p, err := NewAsyncEventPublisher(s.senderMock, s.config(BackpressureDrop, ""))
+	s.Require().NoError(err)
+
+	dropped := droppedEvents.Value()
+	s.NoError(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, uuid.New(), 2, nil)))
+	s.Eventually(func() bool { return p.QueueLength() == 0 }, time.Second, 10*time.Millisecond)
+	s.NoError(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, uuid.New(), 2, nil)))
+	s.ErrorIs(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, uuid.New(), 2, nil)), ErrEventDropped)
+	s.Equal(dropped+1, droppedEvents.Value())
+
+	close(release)
+	s.NoError(p.close(context.TODO()))
+}
+
+func (s *asyncEventPublisherTestSuite) TestPublish_SpillsEventsWhenQueueIsFull() {
+	spillFile := filepath.Join(s.T().TempDir(), "events.spill")
+	release := s.blockSender()
+	p, err := NewAsyncEventPublisher(s.senderMock, s.config(BackpressureSpill, spillFile))
+	s.Require().NoError(err)
+
+	spilledUserID := uuid.New()
+	s.NoError(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, uuid.New(), 2, nil)))
+	s.Eventually(func() bool { return p.QueueLength() == 0 }, time.Second, 10*time.Millisecond)
+	s.NoError(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, uuid.New(), 2, nil)))
+	s.NoError(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, spilledUserID, 2, nil)))
+
+	close(release)
+	s.NoError(p.close(context.TODO()))
+
+	spilled, err := readSpill(spillFile)
+	s.NoError(err)
+	s.Require().Len(spilled, 1)
+	s.Equal(spilledUserID, spilled[0].Event.UserID)
+
+	// spilled events are replayed by the next publisher
+	replayed := make(chan UserEvent, 1)
+	sender := newMockEventSender(s.T())
+	sender.
+		On(publish, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { replayed <- args.Get(1).(UserEvent) }).
+		Return(nil).
+		Once()
+	sender.On(closeFn, mock.Anything).Return(nil).Once()
+
+	p, err = NewAsyncEventPublisher(sender, s.config(BackpressureSpill, spillFile))
+	s.Require().NoError(err)
+	select {
+	case e := <-replayed:
+		s.Equal(spilledUserID, e.UserID)
+	case <-time.After(time.Second):
+		s.Fail("spilled event was not replayed")
+	}
+	// the replayed events are removed only after they were published
+	s.Eventually(func() bool {
+		_, err := os.Stat(replaySpillFile(spillFile))
+		return errors.Is(err, os.ErrNotExist)
+	}, time.Second, 10*time.Millisecond)
+	s.NoError(p.close(context.TODO()))
+}
+
+func (s *asyncEventPublisherTestSuite) TestPublish_KeepsSpilledEventsUntilReplayed() {
+	spillFile := filepath.Join(s.T().TempDir(), "events.spill")
+	spill, err := openSpill(spillFile)
+	s.Require().NoError(err)
+	s.Require().NoError(spill.write(queuedEvent{Event: newUserEvent(UserEventTypeDeleted, uuid.New(), 2, nil)}))
+	s.Require().NoError(spill.close())
+
+	// the replayed event isn't published until it's released
+	publishing := make(chan struct{})
+	release := make(chan struct{})
+	s.senderMock.
+		On(publish, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) {
+			close(publishing)
+			<-release
+		}).
+		Return(nil).
+		Once()
+	s.senderMock.On(closeFn, mock.Anything).Return(nil).Once()
+	p, err := NewAsyncEventPublisher(s.senderMock, s.config(BackpressureSpill, spillFile))
+	s.Require().NoError(err)
+	<-publishing
+
+	replaying, err := readSpill(replaySpillFile(spillFile))
+	s.NoError(err)
+	s.Len(replaying, 1)
+	spilled, err := readSpill(spillFile)
+	s.NoError(err)
+	s.Empty(spilled)
+
+	close(release)
+	s.NoError(p.close(context.TODO()))
+}
+
+func (s *asyncEventPublisherTestSuite) TestPublish_KeepsOrderOfUser() {
+	userID := uuid.New()
+	var mu sync.Mutex
+	var versions []int64
+	s.senderMock.
+		On(publish, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			e := args.Get(1).(UserEvent)
+			if e.UserID == userID {
+				mu.Lock()
+				versions = append(versions, e.Version)
+				mu.Unlock()
+			}
+		}).
+		Return(nil)
+	s.senderMock.On(closeFn, mock.Anything).Return(nil).Once()
+
+	config := s.config(BackpressureBlock, "")
+	config.Workers = 4
+	config.QueueSize = 100
+	p, err := NewAsyncEventPublisher(s.senderMock, config)
+	s.Require().NoError(err)
+
+	for v := int64(1); v <= 20; v++ {
+		s.NoError(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, userID, v, nil)))
+		s.NoError(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, uuid.New(), v, nil)))
+	}
+	s.NoError(p.close(context.TODO()))
+
+	s.Len(versions, 20)
+	s.IsIncreasing(versions)
+}
+
+func (s *asyncEventPublisherTestSuite) TestPublish_RetriesFailedEvents() {
+	s.senderMock.On(publish, mock.Anything, mock.Anything).Return(errors.New("channel closed")).Once()
+	s.senderMock.On(publish, mock.Anything, mock.Anything).Return(nil).Once()
+	s.senderMock.On(closeFn, mock.Anything).Return(nil).Once()
+
+	config := s.config(BackpressureBlock, "")
+	config.PublishAttempts = 2
+	p, err := NewAsyncEventPublisher(s.senderMock, config)
+	s.Require().NoError(err)
+
+	published := publishedEvents.Value()
+	s.NoError(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, uuid.New(), 2, nil)))
+	s.NoError(p.close(context.TODO()))
+	s.Equal(published+1, publishedEvents.Value())
+}
+
+func (s *asyncEventPublisherTestSuite) TestPublish_SpillsFailedEvents() {
+	spillFile := filepath.Join(s.T().TempDir(), "events.spill")
+	s.senderMock.On(publish, mock.Anything, mock.Anything).Return(errors.New("channel closed")).Twice()
+	s.senderMock.On(closeFn, mock.Anything).Return(nil).Once()
+
+	config := s.config(BackpressureSpill, spillFile)
+	config.PublishAttempts = 2
+	p, err := NewAsyncEventPublisher(s.senderMock, config)
+	s.Require().NoError(err)
+
+	userID := uuid.New()
+	s.NoError(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, userID, 2, nil)))
+	s.NoError(p.close(context.TODO()))
+
+	spilled, err := readSpill(spillFile)
+	s.NoError(err)
+	s.Require().Len(spilled, 1)
+	s.Equal(userID, spilled[0].Event.UserID)
+}
+
+func (s *asyncEventPublisherTestSuite) TestPublish_NotifiesListenersOnceDespiteRetries() {
+	spillFile := filepath.Join(s.T().TempDir(), "events.spill")
+	s.senderMock.On(publish, mock.Anything, mock.Anything).Return(errors.New("channel closed")).Times(3)
+	s.senderMock.On(closeFn, mock.Anything).Return(nil).Once()
+	userID := uuid.New()
+	listenerMock := NewMockEventListener(s.T())
+	listenerMock.
+		On("OnUserEvent", mock.MatchedBy(func(c context.Context) bool {
+			return common.GetCorrelationID(c) == "test-correlation-id"
+		}), mock.MatchedBy(func(e UserEvent) bool { return e.UserID == userID })).
+		Once()
+
+	config := s.config(BackpressureSpill, spillFile)
+	config.PublishAttempts = 3
+	p, err := NewAsyncEventPublisher(s.senderMock, config)
+	s.Require().NoError(err)
+	p.notifier = eventNotifier{listeners: []EventListener{listenerMock}}
+
+	ctx := context.WithValue(context.TODO(), common.CorrelationID, "test-correlation-id")
+	s.NoError(p.publish(ctx, newUserEvent(UserEventTypeDeleted, userID, 2, nil)))
+	s.NoError(p.close(context.TODO()))
+
+	spilled, err := readSpill(spillFile)
+	s.NoError(err)
+	s.Require().Len(spilled, 1)
+	s.True(spilled[0].Notified, "the replay of the spilled event doesn't notify the listeners again")
+}
+
+func (s *asyncEventPublisherTestSuite) TestClose_RejectsNewEvents() {
+	s.senderMock.On(closeFn, mock.Anything).Return(nil).Once()
+	p, err := NewAsyncEventPublisher(s.senderMock, s.config(BackpressureBlock, ""))
+	s.Require().NoError(err)
+
+	s.NoError(p.close(context.TODO()))
+	s.ErrorIs(p.publish(context.TODO(), newUserEvent(UserEventTypeDeleted, uuid.New(), 2, nil)), ErrPublisherClosed)
+	s.senderMock.AssertNotCalled(s.T(), publish)
+}
+
+func (s *asyncEventPublisherTestSuite) TestNewAsyncEventPublisher_ReturnsErrorOnInvalidConfig() {
+	_, err := NewAsyncEventPublisher(s.senderMock, s.config("unknown", ""))
+	s.Error(err)
+
+	config := s.config(BackpressureBlock, "")
+	config.Workers = 0
+	_, err = NewAsyncEventPublisher(s.senderMock, config)
+	s.Error(err)
+}
+
+func (s *asyncEventPublisherTestSuite) config(policy BackpressurePolicy, spillFile string) AsyncEventPublisherConfig {
+	return AsyncEventPublisherConfig{
+		QueueSize:    1,
+		Workers:      1,
+		Backpressure: policy,
+		SpillFile:    spillFile,
+		RetryBackoff: time.Millisecond,
+	}
+}
+
+// blockSender makes the sender wait with publishing until the returned channel is closed
+func (s *asyncEventPublisherTestSuite) blockSender() chan struct{} {
+	release := make(chan struct{})
+	s.senderMock.
+		On(publish, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).
+		Return(nil)
+	s.senderMock.On(closeFn, mock.Anything).Return(nil).Once()
+	return release
+}
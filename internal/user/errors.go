@@ -0,0 +1,113 @@
+package user
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/jackc/pgconn"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrorKind is the class of a user service error, the APIs map their status codes by it
+type ErrorKind string
+
+const (
+	// KindValidation is an invalid request, i.e. an invalid user or pagination
+	KindValidation ErrorKind = "validation"
+	// KindNotFound is a missing user
+	KindNotFound ErrorKind = "not_found"
+	// KindConflict is a request what conflicts with the current state, i.e. a taken email
+	KindConflict ErrorKind = "conflict"
+	// KindPrecondition is a valid request what can't be processed, i.e. a reused idempotency key
+	KindPrecondition ErrorKind = "precondition"
+	// KindTimeout is a request what didn't finish in time
+	KindTimeout ErrorKind = "timeout"
+	// KindUnavailable is a request what failed because a dependency, i.e. the database, isn't reachable
+	KindUnavailable ErrorKind = "unavailable"
+	// KindInternal is an unexpected error
+	KindInternal ErrorKind = "internal"
+)
+
+// pgUniqueViolation is the Postgres error code of a unique constraint violation
+const pgUniqueViolation = "23505"
+
+var (
+	errTimeout     = &Error{Kind: KindTimeout, Code: "timeout", Message: "request timed out"}
+	errUnavailable = &Error{Kind: KindUnavailable, Code: "service_unavailable", Message: "service is temporarily unavailable"}
+	errInternal    = &Error{Kind: KindInternal, Code: "internal_error", Message: "internal error"}
+)
+
+// Error is a typed user service error. The code is stable, so the clients could rely on it, and the message is safe
+// to be returned to them. The cause is the original error what is only logged.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Cause   error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is matches the errors of the same code, so the classified error still matches its sentinel error
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// AsError classifies the error into a typed error. The message of the client errors is the message of the error
+// with its details, while the timeout, unavailable and internal errors get a generic message, so the details
+// of the dependencies aren't leaked.
+func AsError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var typed *Error
+	switch {
+	case errors.As(err, &typed):
+	case errors.Is(err, context.DeadlineExceeded):
+		typed = errTimeout
+	case isUnavailable(err):
+		typed = errUnavailable
+	default:
+		typed = errInternal
+	}
+
+	e := &Error{Kind: typed.Kind, Code: typed.Code, Message: typed.Message, Cause: err}
+	if typed.Kind.isClientError() {
+		e.Message = err.Error()
+	}
+	return e
+}
+
+// isClientError is true for the kinds what are caused by the request, their message could have the details of it
+func (k ErrorKind) isClientError() bool {
+	switch k {
+	case KindValidation, KindNotFound, KindConflict, KindPrecondition:
+		return true
+	default:
+		return false
+	}
+}
+
+// isUnavailable is true when the database or the message broker couldn't be reached
+func isUnavailable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, amqp.ErrClosed)
+}
+
+// isUniqueViolation is true when the error is a unique constraint violation of Postgres
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
@@ -0,0 +1,60 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	errorsTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(errorsTestSuite))
+}
+
+func (s *errorsTestSuite) TestAsError() {
+	for _, test := range []struct {
+		name            string
+		err             error
+		expectedKind    ErrorKind
+		expectedCode    string
+		expectedMessage string
+	}{
+		{"not found", ErrUserNotFound, KindNotFound, "user_not_found", "user not found"},
+		{"wrapped", fmt.Errorf("%w: unknown field", ErrInvalidSort), KindValidation, "invalid_sort", "invalid sort: unknown field"},
+		{"validation", &ValidationError{Fields: []FieldError{{Field: "email", Rule: RuleEmail, Message: "invalid email address"}}},
+			KindValidation, "invalid_user", "input user data is invalid: invalid email address"},
+		{"conflict", ErrEmailTaken, KindConflict, "email_taken", "email is already taken"},
+		{"precondition", ErrIdempotencyKeyReused, KindPrecondition, "idempotency_key_reused", "idempotency key was used with a different request"},
+		{"timeout", fmt.Errorf("query: %w", context.DeadlineExceeded), KindTimeout, "timeout", "request timed out"},
+		{"unavailable", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, KindUnavailable, "service_unavailable", "service is temporarily unavailable"},
+		{"internal", errors.New("column doesn't exist"), KindInternal, "internal_error", "internal error"},
+	} {
+		s.Run(test.name, func() {
+			e := AsError(test.err)
+
+			s.Equal(test.expectedKind, e.Kind)
+			s.Equal(test.expectedCode, e.Code)
+			s.Equal(test.expectedMessage, e.Error())
+			s.ErrorIs(e, test.err)
+		})
+	}
+
+	s.Nil(AsError(nil))
+}
+
+func (s *errorsTestSuite) TestHandleConflictError() {
+	s.ErrorIs(handleConflictError(&pgconn.PgError{Code: pgUniqueViolation}), ErrEmailTaken)
+
+	err := &pgconn.PgError{Code: "23503"}
+	s.Equal(err, handleConflictError(err))
+}
@@ -0,0 +1,95 @@
+package user
+
+import (
+	"encoding/json"
+	"faceit/pkg/userevents/pb"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EventEncoding is the wire format of the published user events
+type EventEncoding string
+
+const (
+	// EventEncodingJSON encodes the events as JSON what is described by the JSON Schemas in `api/schemas`
+	EventEncodingJSON EventEncoding = "json"
+	// EventEncodingProtobuf encodes the events as the UserEvent message of `api/proto/userevents/v1/user_events.proto`
+	EventEncodingProtobuf EventEncoding = "protobuf"
+
+	protobufType = "application/protobuf"
+
+	// HeaderSchemaVersion is the AMQP header of the event schema version
+	HeaderSchemaVersion = "x-schema-version"
+	// EventSchemaVersion is the major version of the JSON and protobuf event schemas
+	EventSchemaVersion = "1"
+)
+
+type (
+	// eventEncoder encodes the events to the message body of the content type
+	eventEncoder interface {
+		contentType() string
+		encode(event UserEvent) ([]byte, error)
+	}
+
+	jsonEncoder     struct{}
+	protobufEncoder struct{}
+)
+
+func newEventEncoder(encoding EventEncoding) (eventEncoder, error) {
+	switch encoding {
+	case EventEncodingJSON:
+		return jsonEncoder{}, nil
+	case EventEncodingProtobuf:
+		return protobufEncoder{}, nil
+	}
+	return nil, fmt.Errorf("unknown event encoding %q", encoding)
+}
+
+func (jsonEncoder) contentType() string {
+	return jsonType
+}
+
+func (jsonEncoder) encode(event UserEvent) ([]byte, error) {
+	return json.Marshal(&event)
+}
+
+func (protobufEncoder) contentType() string {
+	return protobufType
+}
+
+func (protobufEncoder) encode(event UserEvent) ([]byte, error) {
+	return proto.Marshal(ToProtoEvent(event))
+}
+
+// ToProtoEvent converts the user event to its protobuf message
+func ToProtoEvent(event UserEvent) *pb.UserEvent {
+	e := pb.UserEvent{
+		EventId: event.EventID.String(),
+		Type:    string(event.Type),
+		UserId:  event.UserID.String(),
+		Version: event.Version,
+		Time:    timestamppb.New(event.Time),
+	}
+
+	if u := event.UserChanges; u != nil {
+		e.UserChanges = &pb.User{
+			Id:        u.ID.String(),
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Nickname:  u.Nickname,
+			Email:     u.Email,
+			Country:   u.Country,
+			CreatedAt: timestamppb.New(u.CreatedAt),
+		}
+		if u.UpdatedAt != nil {
+			e.UserChanges.UpdatedAt = timestamppb.New(*u.UpdatedAt)
+		}
+	}
+
+	if event.Change != nil {
+		e.Change = &pb.FieldChange{Old: event.Change.Old, New: event.Change.New}
+	}
+	return &e
+}
@@ -0,0 +1,84 @@
+package user
+
+import (
+	"encoding/json"
+	"faceit/pkg/userevents/pb"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+)
+
+type (
+	eventEncoderTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestEventEncoderTestSuite(t *testing.T) {
+	suite.Run(t, new(eventEncoderTestSuite))
+}
+
+func (s *eventEncoderTestSuite) TestNewEventEncoder() {
+	e, err := newEventEncoder(EventEncodingJSON)
+	s.NoError(err)
+	s.Equal(jsonType, e.contentType())
+
+	e, err = newEventEncoder(EventEncodingProtobuf)
+	s.NoError(err)
+	s.Equal(protobufType, e.contentType())
+
+	_, err = newEventEncoder("xml")
+	s.Error(err)
+}
+
+func (s *eventEncoderTestSuite) TestJSONEncoder() {
+	event := newUserEvent(UserEventTypeDeleted, uuid.New(), 3, nil)
+
+	data, err := jsonEncoder{}.encode(event)
+	s.NoError(err)
+
+	var decoded UserEvent
+	s.NoError(json.Unmarshal(data, &decoded))
+	s.Equal(event.EventID, decoded.EventID)
+	s.Equal(event.UserID, decoded.UserID)
+	s.EqualValues(3, decoded.Version)
+}
+
+func (s *eventEncoderTestSuite) TestProtobufEncoder() {
+	u := validUser
+	u.ID = uuid.New()
+	u.CreatedAt = time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	updatedAt := u.CreatedAt.Add(time.Hour)
+	u.UpdatedAt = &updatedAt
+	updated := newUserEvent(UserEventTypeUpdated, u.ID, 2, &u)
+	changed := newUserEvent(UserEventTypeEmailChanged, u.ID, 2, nil)
+	changed.Change = &FieldChange{Old: "old@email.com", New: u.Email}
+
+	data, err := protobufEncoder{}.encode(updated)
+	s.NoError(err)
+	var msg pb.UserEvent
+	s.NoError(proto.Unmarshal(data, &msg))
+	s.Equal(updated.EventID.String(), msg.EventId)
+	s.Equal("USER_UPDATED", msg.Type)
+	s.Equal(u.ID.String(), msg.UserId)
+	s.EqualValues(2, msg.Version)
+	s.True(updated.Time.Equal(msg.Time.AsTime()))
+	s.Equal(u.ID.String(), msg.UserChanges.Id)
+	s.Equal("johndoe", msg.UserChanges.Nickname)
+	s.Equal("johndoe@email.com", msg.UserChanges.Email)
+	s.Equal(u.CreatedAt, msg.UserChanges.CreatedAt.AsTime())
+	s.Equal(updatedAt, msg.UserChanges.UpdatedAt.AsTime())
+	s.Nil(msg.Change)
+
+	data, err = protobufEncoder{}.encode(changed)
+	s.NoError(err)
+	msg.Reset()
+	s.NoError(proto.Unmarshal(data, &msg))
+	s.Equal("USER_EMAIL_CHANGED", msg.Type)
+	s.Nil(msg.UserChanges)
+	s.Equal("old@email.com", msg.Change.Old)
+	s.Equal("johndoe@email.com", msg.Change.New)
+}
@@ -0,0 +1,189 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// fieldAction defines how a user field is published in the events
+type fieldAction string
+
+const (
+	fieldInclude fieldAction = "include"
+	fieldMask    fieldAction = "mask"
+	fieldOmit    fieldAction = "omit"
+
+	// projectionDefault is the event type of the rule what is applied to the event types without own rule
+	projectionDefault = "*"
+)
+
+var (
+	ErrInvalidEventProjection = errors.New("invalid event projection")
+
+	// projectedFields are the personal data fields of the users what could be omitted or masked in the events,
+	// the id and the timestamps are always published
+	projectedFields = []string{"first_name", "last_name", "nickname", "email", "country"}
+
+	// changedFields are the fields of the specific change events
+	changedFields = map[UserEventType]string{
+		UserEventTypeEmailChanged:    "email",
+		UserEventTypeNicknameChanged: "nickname",
+		UserEventTypeCountryChanged:  "country",
+	}
+
+	// piiFreeProjection keeps only the country of the users, what is not a personal data on its own
+	piiFreeProjection = eventProjection{projectionDefault: fieldProjection{"country": fieldInclude}}
+)
+
+type (
+	// fieldProjection is the action of the user fields by their JSON name, the missing fields are omitted
+	fieldProjection map[string]fieldAction
+
+	// omittedFields is the bit set of the omitted projectedFields by their index
+	omittedFields uint8
+
+	// eventProjection is the field projection of the event types. The events are published with all the fields
+	// when neither their type nor the default `*` type has a projection.
+	eventProjection map[string]fieldProjection
+)
+
+// parseEventProjection parses the semicolon separated `<event type or *>=<field>[:mask],...` rules,
+// i.e. `USER_CREATED=nickname,country,email:mask;*=nickname,country`.
+// Only the listed fields are published from the user, the masked fields are published partially hidden.
+func parseEventProjection(rules string) (eventProjection, error) {
+	projection := eventProjection{}
+	for _, rule := range strings.Split(rules, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		eventType, fields, ok := strings.Cut(rule, "=")
+		eventType = strings.TrimSpace(eventType)
+		if !ok || (eventType != projectionDefault && !knownEventTypes[UserEventType(eventType)]) {
+			return nil, fmt.Errorf("%w: invalid rule %q", ErrInvalidEventProjection, rule)
+		}
+		if _, ok := projection[eventType]; ok {
+			return nil, fmt.Errorf("%w: duplicated rule of %s", ErrInvalidEventProjection, eventType)
+		}
+
+		fp := fieldProjection{}
+		for _, field := range strings.Split(fields, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			action := fieldInclude
+			if name, mask, ok := strings.Cut(field, ":"); ok {
+				if mask != string(fieldMask) {
+					return nil, fmt.Errorf("%w: unknown action of %s", ErrInvalidEventProjection, field)
+				}
+				field, action = name, fieldMask
+			}
+			if !isProjectedField(field) {
+				return nil, fmt.Errorf("%w: unknown field %s", ErrInvalidEventProjection, field)
+			}
+			fp[field] = action
+		}
+		projection[eventType] = fp
+	}
+	return projection, nil
+}
+
+func isProjectedField(field string) bool {
+	for _, f := range projectedFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// apply returns the projected copy of the event, the original event is not changed.
+// The omitted fields are left out from the JSON of the projected user.
+// False is returned for the specific change events when their field is omitted, as they would be empty.
+func (p eventProjection) apply(event UserEvent) (UserEvent, bool) {
+	fp, ok := p[string(event.Type)]
+	if !ok {
+		fp, ok = p[projectionDefault]
+	}
+	if !ok {
+		return event, true
+	}
+
+	if event.UserChanges != nil {
+		u := *event.UserChanges
+		for i, field := range projectedFields {
+			if fp.action(field) == fieldOmit {
+				u.omitted = u.omitted.with(i)
+			}
+		}
+		u.FirstName = fp.project("first_name", u.FirstName)
+		u.LastName = fp.project("last_name", u.LastName)
+		u.Nickname = fp.project("nickname", u.Nickname)
+		u.Email = fp.project("email", u.Email)
+		u.Country = fp.project("country", u.Country)
+		event.UserChanges = &u
+	}
+
+	if field, ok := changedFields[event.Type]; ok && event.Change != nil {
+		if fp.action(field) == fieldOmit {
+			return event, false
+		}
+		event.Change = &FieldChange{
+			Old: fp.project(field, event.Change.Old),
+			New: fp.project(field, event.Change.New),
+		}
+	}
+	return event, true
+}
+
+func (o omittedFields) with(i int) omittedFields {
+	return o | 1<<i
+}
+
+func (o omittedFields) has(i int) bool {
+	return o&(1<<i) != 0
+}
+
+func (fp fieldProjection) action(field string) fieldAction {
+	if action, ok := fp[field]; ok {
+		return action
+	}
+	return fieldOmit
+}
+
+func (fp fieldProjection) project(field string, value string) string {
+	switch fp.action(field) {
+	case fieldInclude:
+		return value
+	case fieldMask:
+		return mask(field, value)
+	}
+	return ""
+}
+
+// mask keeps the first letter of the value and the domain of the email addresses, i.e. `a***@bob.com`.
+// The country codes are fully masked, so they keep their length.
+func mask(field string, value string) string {
+	if value == "" {
+		return ""
+	}
+	if field == "country" {
+		return strings.Repeat("*", utf8.RuneCountInString(value))
+	}
+
+	suffix := ""
+	if field == "email" {
+		if at := strings.LastIndex(value, "@"); at >= 0 {
+			value, suffix = value[:at], value[at:]
+		}
+	}
+	first, _ := utf8.DecodeRuneInString(value)
+	if first == utf8.RuneError {
+		return "***" + suffix
+	}
+	return string(first) + "***" + suffix
+}
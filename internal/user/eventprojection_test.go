@@ -0,0 +1,108 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	eventProjectionTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestEventProjectionTestSuite(t *testing.T) {
+	suite.Run(t, new(eventProjectionTestSuite))
+}
+
+func (s *eventProjectionTestSuite) TestParseEventProjection() {
+	p, err := parseEventProjection(" USER_CREATED=nickname, country,email:mask ; *=country;")
+	s.NoError(err)
+	s.Equal(eventProjection{
+		"USER_CREATED": fieldProjection{"nickname": fieldInclude, "country": fieldInclude, "email": fieldMask},
+		"*":            fieldProjection{"country": fieldInclude},
+	}, p)
+
+	p, err = parseEventProjection("")
+	s.NoError(err)
+	s.Empty(p)
+}
+
+func (s *eventProjectionTestSuite) TestParseEventProjection_ReturnsErrorOnInvalidRules() {
+	for _, rules := range []string{
+		"USER_CREATED",
+		"USER_LOGGED_IN=nickname",
+		"USER_CREATED=password",
+		"USER_CREATED=email:hash",
+		"USER_CREATED=email;USER_CREATED=nickname",
+	} {
+		_, err := parseEventProjection(rules)
+		s.ErrorIs(err, ErrInvalidEventProjection, rules)
+	}
+}
+
+func (s *eventProjectionTestSuite) TestApply() {
+	u := validUser
+	u.ID = uuid.New()
+	event := newUserEvent(UserEventTypeCreated, u.ID, 1, &u)
+	p := eventProjection{"USER_CREATED": fieldProjection{"nickname": fieldInclude, "email": fieldMask, "country": fieldMask}}
+
+	projected, ok := p.apply(event)
+	s.True(ok)
+	data, err := json.Marshal(projected.UserChanges)
+	s.NoError(err)
+	s.JSONEq(`{"id": "`+u.ID.String()+`", "nickname": "johndoe", "email": "j***@email.com", "country": "**", "created_at": "0001-01-01T00:00:00Z", "updated_at": null}`, string(data))
+	s.Equal(validUser.FirstName, event.UserChanges.FirstName, "the original event must not be changed")
+
+	updated := newUserEvent(UserEventTypeUpdated, u.ID, 2, &u)
+	projected, ok = p.apply(updated)
+	s.True(ok)
+	s.Equal(updated, projected, "the event types without projection are published with all fields")
+}
+
+func (s *eventProjectionTestSuite) TestApply_ProjectsChangeEvents() {
+	event := newUserEvent(UserEventTypeEmailChanged, uuid.New(), 2, nil)
+	event.Change = &FieldChange{Old: "old@email.com", New: "new@email.com"}
+
+	projected, ok := eventProjection{"*": fieldProjection{"email": fieldMask}}.apply(event)
+	s.True(ok)
+	s.Equal(FieldChange{Old: "o***@email.com", New: "n***@email.com"}, *projected.Change)
+	s.Equal("old@email.com", event.Change.Old)
+
+	_, ok = piiFreeProjection.apply(event)
+	s.False(ok)
+
+	event.Type = UserEventTypeCountryChanged
+	event.Change = &FieldChange{Old: "US", New: "HU"}
+	projected, ok = piiFreeProjection.apply(event)
+	s.True(ok)
+	s.Equal(event, projected)
+}
+
+func (s *eventProjectionTestSuite) TestMask() {
+	s.Equal("a***@bob.com", mask("email", "alice@bob.com"))
+	s.Equal("***@bob.com", mask("email", "@bob.com"))
+	s.Equal("J***", mask("first_name", "John"))
+	s.Equal("É***", mask("last_name", "Éva"))
+	s.Equal("**", mask("country", "US"))
+	s.Equal("", mask("nickname", ""))
+}
+
+func (s *eventProjectionTestSuite) TestEventNotifier_ProjectsEventsOfListeners() {
+	u := validUser
+	u.ID = uuid.New()
+	event := newUserEvent(UserEventTypeCreated, u.ID, 1, &u)
+	listenerMock := NewMockEventListener(s.T())
+	listenerMock.
+		On("OnUserEvent", mock.Anything, mock.MatchedBy(func(e UserEvent) bool {
+			return e.EventID == event.EventID && e.UserChanges.Email == "" && e.UserChanges.Country == u.Country
+		})).
+		Once()
+
+	eventNotifier{projection: piiFreeProjection, listeners: []EventListener{listenerMock}}.notify(context.TODO(), event)
+}
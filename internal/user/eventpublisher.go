@@ -2,7 +2,7 @@ package user
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"faceit/internal/common"
 	"fmt"
 	"time"
@@ -15,19 +15,53 @@ import (
 const jsonType = "application/json"
 
 type RmqEventPublisher struct {
-	exchange string
-	conn     amqp.Connection
-	channel  amqp.Channel
+	topology    Topology
+	projection  eventProjection
+	encoder     eventEncoder
+	schemaCheck eventSchemaCheck
+	signer      eventSigner
+	conn        *amqp.Connection
+	channel     *amqp.Channel
 }
 
 // NewEventPublisher creates a new RabbitMQ connection to publish user related events.
+// The events are encoded by the EVENT_ENCODING environment variable, JSON by default,
+// and they are signed by the EVENT_SIGNING_* configuration, unsigned by default.
+// The encoded events are validated against their schema by the EVENT_SCHEMA_VALIDATION mode, logged by default.
+// The user fields of the events are projected by the EVENT_FIELD_PROJECTION rules, and the events are published
+// without personal data to the PII-free exchange of the topology as well when it's set.
+// The exchanges of the topology (see NewTopology) are declared on startup.
 func NewEventPublisher() (*RmqEventPublisher, error) {
-	mqHost := common.GetEnv("RMQ_HOST", "localhost")
-	mqPort := common.GetEnv("RMQ_PORT", "5672")
-	mqUser := common.GetEnv("RMQ_USER", "guest")
-	mqPass := common.GetEnv("RMQ_PASSWORD", "guest")
+	encoder, err := newEventEncoder(EventEncoding(common.GetEnv("EVENT_ENCODING", string(EventEncodingJSON))))
+	if err != nil {
+		return nil, err
+	}
+
+	schemaCheck, err := newEventSchemaCheck(EventValidationMode(common.GetEnv("EVENT_SCHEMA_VALIDATION", string(EventValidationLog))))
+	if err != nil {
+		return nil, err
+	}
+
+	signingConfig, err := NewEventSigningConfig()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := newEventSigner(signingConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	projection, err := parseEventProjection(common.GetEnv("EVENT_FIELD_PROJECTION", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	topology, err := NewTopology()
+	if err != nil {
+		return nil, err
+	}
 
-	conn, err := amqp.Dial(fmt.Sprintf("amqp://%s:%s@%s:%s/", mqUser, mqPass, mqHost, mqPort))
+	conn, err := common.DialRabbitMQ()
 	if err != nil {
 		return nil, err
 	}
@@ -37,19 +71,22 @@ func NewEventPublisher() (*RmqEventPublisher, error) {
 		return nil, err
 	}
 
-	exchange := common.GetEnv("USER_EVENT_EXCHANGE", "events.user")
-	if err := ch.ExchangeDeclare(exchange, "topic", true, false, false, false, nil); err != nil {
+	if err := topology.declare(ch); err != nil {
 		return nil, err
 	}
 
 	return &RmqEventPublisher{
-		exchange: exchange,
-		conn:     *conn,
-		channel:  *ch,
+		topology:    topology,
+		projection:  projection,
+		encoder:     encoder,
+		schemaCheck: schemaCheck,
+		signer:      signer,
+		conn:        conn,
+		channel:     ch,
 	}, nil
 }
 
-func (e RmqEventPublisher) close() error {
+func (e *RmqEventPublisher) close(ctx context.Context) error {
 	if err := e.channel.Close(); err != nil {
 		log.Err(err).Msg("failed to close RMQ channel")
 	}
@@ -57,49 +94,160 @@ func (e RmqEventPublisher) close() error {
 }
 
 // Channel returns the created RabbitMQ channel
-func (e RmqEventPublisher) Channel() amqp.Channel {
+func (e *RmqEventPublisher) Channel() *amqp.Channel {
 	return e.channel
 }
 
 // Channel returns the created RabbitMQ exchange name for user events
-func (e RmqEventPublisher) ExchangeName() string {
-	return e.exchange
+func (e *RmqEventPublisher) ExchangeName() string {
+	return e.topology.Exchange.Name
 }
 
-func (e RmqEventPublisher) publishCreated(ctx context.Context, userID uuid.UUID, userChanges *User) error {
-	return e.publishEvent(ctx, UserEventTypeCreated, userID, userChanges)
+// IsConnected is false when the RabbitMQ connection of the publisher was closed
+func (e *RmqEventPublisher) IsConnected() bool {
+	return !e.conn.IsClosed()
 }
 
-func (e RmqEventPublisher) publishDeleted(ctx context.Context, userID uuid.UUID) error {
-	return e.publishEvent(ctx, UserEventTypeDeleted, userID, nil)
+// VerifyTopology checks the exchanges and the queues of the topology with passive declares on separate channels,
+// and returns the missing ones. An error is returned when the broker couldn't be reached.
+// The passive declares only detect the missing exchanges and queues, a changed exchange type or changed arguments
+// are not detected.
+func (e *RmqEventPublisher) VerifyTopology() ([]string, error) {
+	return e.topology.verify(func() (topologyChannel, error) {
+		ch, err := e.conn.Channel()
+		if err != nil {
+			return nil, err
+		}
+		return ch, nil
+	})
 }
 
-func (e RmqEventPublisher) publishUpdated(ctx context.Context, userID uuid.UUID, userChanges *User) error {
-	return e.publishEvent(ctx, UserEventTypeUpdated, userID, userChanges)
+// VerificationKeys returns the public keys what verify the signatures of the published events
+func (e *RmqEventPublisher) VerificationKeys() []VerificationKey {
+	return e.signer.verificationKeys()
 }
 
-func (e RmqEventPublisher) publishPasswordChanged(ctx context.Context, userID uuid.UUID) error {
-	return e.publishEvent(ctx, UserEventTypePasswordChanged, userID, nil)
+// publish publishes the event to the user event exchange, and to the PII-free exchange when it's set.
+// The event is published again to both exchanges when the PII-free publish fails and the event is retried,
+// the consumers drop the duplicates by the event id.
+func (e *RmqEventPublisher) publish(ctx context.Context, event UserEvent) error {
+	if err := e.publishTo(ctx, ReplayTarget{}, event, nil); err != nil {
+		return err
+	}
+	if e.topology.PIIFreeExchange == nil {
+		return nil
+	}
+	return e.publishProjected(ctx, e.topology.PIIFreeExchange.Name, "#", piiFreeProjection, event, nil)
 }
 
-func (e RmqEventPublisher) publishEvent(ctx context.Context, eventType UserEventType, userID uuid.UUID, userChanges *User) error {
-	event := UserEvent{
-		Type:        eventType,
-		UserID:      userID,
-		UserChanges: userChanges,
-		Time:        time.Now(),
+// checkExchange verifies that the exchange exists on a separate channel,
+// because publishing to a missing exchange would close the publisher channel.
+func (e *RmqEventPublisher) checkExchange(exchange string) error {
+	ch, err := e.conn.Channel()
+	if err != nil {
+		return err
 	}
 
-	body, err := json.Marshal(&event)
+	err = ch.ExchangeDeclarePassive(exchange, "topic", true, false, false, false, nil)
+	if err == nil {
+		return ch.Close()
+	}
+
+	// the broker closes the channel on a missing exchange, but it could stay open on the other errors
+	_ = ch.Close()
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		return fmt.Errorf("%w: exchange %s doesn't exist", ErrInvalidReplayRequest, exchange)
+	}
+	return err
+}
+
+// publishTo publishes the event with the given headers to the target exchange and routing key,
+// or to the exchange of the event type with `#` routing key when they are not set.
+// The event id is sent as the message id, so the consumers could drop the duplicates without parsing the body.
+// The body is encoded by the encoder of the publisher and the schema version is sent in the HeaderSchemaVersion header.
+// The message id and the body are signed by the signer of the publisher, what overrides the signature of the given headers.
+// The user fields are projected by the projection of the publisher.
+func (e *RmqEventPublisher) publishTo(ctx context.Context, target ReplayTarget, event UserEvent, headers amqp.Table) error {
+	exchange := e.topology.exchangeFor(event.Type)
+	if target.Exchange != "" {
+		exchange = target.Exchange
+	}
+	routingKey := "#"
+	if target.RoutingKey != "" {
+		routingKey = target.RoutingKey
+	}
+
+	return e.publishProjected(ctx, exchange, routingKey, e.projection, event, headers)
+}
+
+// publishProjected publishes the projection of the event, the specific change events of the omitted fields are skipped.
+// The encoded projection is checked against the schema of the event type.
+func (e *RmqEventPublisher) publishProjected(ctx context.Context, exchange string, routingKey string, projection eventProjection, event UserEvent, headers amqp.Table) error {
+	event, ok := projection.apply(event)
+	if !ok {
+		return nil
+	}
+
+	body, err := e.encoder.encode(event)
 	if err != nil {
 		return err
 	}
+	if err := e.schemaCheck.check(ctx, event, body, e.encoder.contentType()); err != nil {
+		return err
+	}
+
+	msgHeaders := amqp.Table{HeaderSchemaVersion: EventSchemaVersion}
+	for k, v := range headers {
+		msgHeaders[k] = v
+	}
+	messageID := event.EventID.String()
+	for k, v := range e.signer.sign(messageID, body) {
+		msgHeaders[k] = v
+	}
 
 	msg := amqp.Publishing{
+		Headers:       msgHeaders,
+		MessageId:     messageID,
 		CorrelationId: common.GetCorrelationID(ctx),
-		ContentType:   jsonType,
+		ContentType:   e.encoder.contentType(),
 		Body:          body,
 	}
 
-	return e.channel.PublishWithContext(ctx, e.exchange, "#", false, false, msg)
+	return e.channel.PublishWithContext(ctx, exchange, routingKey, false, false, msg)
+}
+
+func newUserEvent(eventType UserEventType, userID uuid.UUID, version int64, userChanges *User) UserEvent {
+	return UserEvent{
+		EventID:     uuid.New(),
+		Type:        eventType,
+		UserID:      userID,
+		Version:     version,
+		UserChanges: userChanges,
+		Time:        time.Now(),
+	}
+}
+
+func newFieldChangeEvent(eventType UserEventType, userID uuid.UUID, version int64, change FieldChange) UserEvent {
+	event := newUserEvent(eventType, userID, version, nil)
+	event.Change = &change
+	return event
+}
+
+// eventNotifier forwards the projection of the events to the listeners. It's called once per event regardless
+// of the publish result, so the listeners don't depend on the broker availability.
+type eventNotifier struct {
+	projection eventProjection
+	listeners  []EventListener
+}
+
+func (n eventNotifier) notify(ctx context.Context, event UserEvent) {
+	if len(n.listeners) == 0 {
+		return
+	}
+	if projected, ok := n.projection.apply(event); ok {
+		for _, l := range n.listeners {
+			l.OnUserEvent(ctx, projected)
+		}
+	}
 }
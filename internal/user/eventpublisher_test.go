@@ -5,7 +5,9 @@ package user
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	apispec "faceit/api"
 	"faceit/internal/common"
 	"testing"
 	"time"
@@ -18,6 +20,7 @@ import (
 type (
 	eventPublisherTestSuite struct {
 		publisher    RmqEventPublisher
+		validator    *eventValidator
 		consumedMsgs <-chan amqp091.Delivery
 		suite.Suite
 	}
@@ -31,6 +34,16 @@ func (s *eventPublisherTestSuite) SetupSuite() {
 	p, err := NewEventPublisher()
 	s.Require().NoError(err)
 	s.publisher = *p
+	s.publisher.signer, err = newEventSigner(EventSigningConfig{
+		Algorithm: EventSigningHMAC,
+		KeyID:     "itest",
+		Key:       base64.StdEncoding.EncodeToString([]byte("secret")),
+	})
+	s.Require().NoError(err)
+
+	// every published event must conform to its schema
+	s.validator, err = newEventValidator(apispec.EventSchemas)
+	s.Require().NoError(err)
 
 	q, err := p.channel.QueueDeclare("itest", true, false, false, false, nil)
 	if err != nil {
@@ -54,15 +67,15 @@ func (s *eventPublisherTestSuite) SetupTest() {
 	s.T().Logf("purged %d messages from test queue", total)
 }
 
-func (s eventPublisherTestSuite) TestPublishCreated() {
+func (s *eventPublisherTestSuite) TestPublishCreated() {
 	correlationID := uuid.New()
 	ctx := context.WithValue(context.TODO(), common.CorrelationID, correlationID)
-	user := User{
-		ID:       uuid.New(),
-		Nickname: "johndoe",
-	}
+	user := validUser
+	user.ID = uuid.New()
+	user.CreatedAt = time.Now()
+	user.Version = 1
 
-	err := s.publisher.publishCreated(ctx, user.ID, &user)
+	err := s.publisher.publish(ctx, newUserEvent(UserEventTypeCreated, user.ID, user.Version, &user))
 	s.NoError(err)
 
 	consumedEvent, consumedCorrelationID, err := s.getUserEvent()
@@ -71,14 +84,15 @@ func (s eventPublisherTestSuite) TestPublishCreated() {
 	s.Equal(UserEventTypeCreated, consumedEvent.Type)
 	s.Equal(user.ID, consumedEvent.UserID)
 	s.Equal("johndoe", consumedEvent.UserChanges.Nickname)
+	s.EqualValues(1, consumedEvent.Version)
 }
 
-func (s eventPublisherTestSuite) TestPublishDeleted() {
+func (s *eventPublisherTestSuite) TestPublishDeleted() {
 	correlationID := uuid.New()
 	ctx := context.WithValue(context.TODO(), common.CorrelationID, correlationID)
 	userID := uuid.New()
 
-	err := s.publisher.publishDeleted(ctx, userID)
+	err := s.publisher.publish(ctx, newUserEvent(UserEventTypeDeleted, userID, 3, nil))
 	s.NoError(err)
 
 	consumedEvent, consumedCorrelationID, err := s.getUserEvent()
@@ -86,17 +100,18 @@ func (s eventPublisherTestSuite) TestPublishDeleted() {
 	s.Equal(correlationID, consumedCorrelationID)
 	s.Equal(UserEventTypeDeleted, consumedEvent.Type)
 	s.Equal(userID, consumedEvent.UserID)
+	s.EqualValues(3, consumedEvent.Version)
 }
 
-func (s eventPublisherTestSuite) TestPublishUpdated() {
+func (s *eventPublisherTestSuite) TestPublishUpdated() {
 	correlationID := uuid.New()
 	ctx := context.WithValue(context.TODO(), common.CorrelationID, correlationID)
-	user := User{
-		ID:    uuid.New(),
-		Email: "new@email.com",
-	}
+	user := validUser
+	user.ID = uuid.New()
+	user.Email = "new@email.com"
+	user.UpdatedAt = common.Ptr(time.Now())
 
-	err := s.publisher.publishUpdated(ctx, user.ID, &user)
+	err := s.publisher.publish(ctx, newUserEvent(UserEventTypeUpdated, user.ID, user.Version, &user))
 	s.NoError(err)
 
 	consumedEvent, consumedCorrelationID, err := s.getUserEvent()
@@ -107,12 +122,12 @@ func (s eventPublisherTestSuite) TestPublishUpdated() {
 	s.Equal("new@email.com", consumedEvent.UserChanges.Email)
 }
 
-func (s eventPublisherTestSuite) TestPasswordChanged() {
+func (s *eventPublisherTestSuite) TestPasswordChanged() {
 	correlationID := uuid.New()
 	ctx := context.WithValue(context.TODO(), common.CorrelationID, correlationID)
 	userID := uuid.New()
 
-	err := s.publisher.publishPasswordChanged(ctx, userID)
+	err := s.publisher.publish(ctx, newUserEvent(UserEventTypePasswordChanged, userID, 2, nil))
 	s.NoError(err)
 
 	consumedEvent, consumedCorrelationID, err := s.getUserEvent()
@@ -120,9 +135,27 @@ func (s eventPublisherTestSuite) TestPasswordChanged() {
 	s.Equal(correlationID, consumedCorrelationID)
 	s.Equal(UserEventTypePasswordChanged, consumedEvent.Type)
 	s.Equal(userID, consumedEvent.UserID)
+	s.EqualValues(2, consumedEvent.Version)
 }
 
-func (s eventPublisherTestSuite) getUserEvent() (*UserEvent, uuid.UUID, error) {
+func (s *eventPublisherTestSuite) TestPublishFieldChanged() {
+	correlationID := uuid.New()
+	ctx := context.WithValue(context.TODO(), common.CorrelationID, correlationID)
+	userID := uuid.New()
+
+	err := s.publisher.publish(ctx, newFieldChangeEvent(UserEventTypeNicknameChanged, userID, 2, FieldChange{Old: "johndoe", New: "jdoe"}))
+	s.NoError(err)
+
+	consumedEvent, consumedCorrelationID, err := s.getUserEvent()
+	s.NoError(err)
+	s.Equal(correlationID, consumedCorrelationID)
+	s.Equal(UserEventTypeNicknameChanged, consumedEvent.Type)
+	s.Equal(userID, consumedEvent.UserID)
+	s.EqualValues(2, consumedEvent.Version)
+	s.Equal(FieldChange{Old: "johndoe", New: "jdoe"}, *consumedEvent.Change)
+}
+
+func (s *eventPublisherTestSuite) getUserEvent() (*UserEvent, uuid.UUID, error) {
 	select {
 	case <-time.After(3 * time.Second):
 		return nil, uuid.Nil, context.DeadlineExceeded
@@ -138,6 +171,17 @@ func (s eventPublisherTestSuite) getUserEvent() (*UserEvent, uuid.UUID, error) {
 			if err := json.Unmarshal(msg.Body, &event); err != nil {
 				return nil, uuid.Nil, err
 			}
+			if err := s.validator.validate(*event); err != nil {
+				return nil, uuid.Nil, err
+			}
+			s.Equal(event.EventID.String(), msg.MessageId)
+			s.Equal(EventSchemaVersion, msg.Headers[HeaderSchemaVersion])
+			s.Equal(jsonType, msg.ContentType)
+			s.Equal(s.publisher.signer.sign(msg.MessageId, msg.Body), amqp091.Table{
+				HeaderSignature:          msg.Headers[HeaderSignature],
+				HeaderSignatureKeyID:     msg.Headers[HeaderSignatureKeyID],
+				HeaderSignatureAlgorithm: msg.Headers[HeaderSignatureAlgorithm],
+			})
 
 			return event, cID, nil
 		}
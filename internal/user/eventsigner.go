@@ -0,0 +1,214 @@
+package user
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"faceit/internal/common"
+	"fmt"
+	"sort"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// EventSigningAlgorithm is the algorithm of the user event signatures
+type EventSigningAlgorithm string
+
+const (
+	// EventSigningNone publishes the events without signature
+	EventSigningNone EventSigningAlgorithm = "none"
+	// EventSigningEd25519 signs the events with an Ed25519 private key, the public keys are published by the API
+	EventSigningEd25519 EventSigningAlgorithm = "ed25519"
+	// EventSigningHMAC signs the events with a HMAC-SHA256 secret what is shared with the consumers out of band
+	EventSigningHMAC EventSigningAlgorithm = "hmac-sha256"
+
+	// HeaderSignature is the AMQP header of the base64 encoded event signature
+	HeaderSignature = "x-signature"
+	// HeaderSignatureKeyID is the AMQP header of the signing key id
+	HeaderSignatureKeyID = "x-signature-key-id"
+	// HeaderSignatureAlgorithm is the AMQP header of the signing algorithm
+	HeaderSignatureAlgorithm = "x-signature-algorithm"
+)
+
+var ErrInvalidSigningConfig = errors.New("invalid event signing config")
+
+type (
+	// eventSigner signs the message id and the body of the published events
+	eventSigner interface {
+		sign(messageID string, body []byte) amqp.Table
+		verificationKeys() []VerificationKey
+	}
+
+	// VerificationKey is a public key what verifies the signatures of the user events
+	VerificationKey struct {
+		KeyID     string
+		Algorithm EventSigningAlgorithm
+		PublicKey ed25519.PublicKey
+	}
+
+	// EventSigningConfig defines the key of the event signatures.
+	// The Key is the base64 encoded Ed25519 seed or private key, or the HMAC secret.
+	// The PreviousKeys are the base64 encoded Ed25519 public keys of the rotated keys by their key id,
+	// they are only published to let the consumers verify the events what were signed before the rotation.
+	EventSigningConfig struct {
+		Algorithm    EventSigningAlgorithm
+		KeyID        string
+		Key          string
+		PreviousKeys map[string]string
+	}
+
+	noopSigner struct{}
+
+	ed25519Signer struct {
+		keyID    string
+		key      ed25519.PrivateKey
+		previous []VerificationKey
+	}
+
+	hmacSigner struct {
+		keyID  string
+		secret []byte
+	}
+)
+
+// NewEventSigningConfig reads the event signing configuration from the environment.
+// EVENT_SIGNING_PREVIOUS_KEYS holds the comma separated `key id=base64 public key` pairs of the rotated Ed25519 keys.
+func NewEventSigningConfig() (EventSigningConfig, error) {
+	previous, err := parsePreviousKeys(common.GetEnv("EVENT_SIGNING_PREVIOUS_KEYS", ""))
+	if err != nil {
+		return EventSigningConfig{}, err
+	}
+
+	return EventSigningConfig{
+		Algorithm:    EventSigningAlgorithm(common.GetEnv("EVENT_SIGNING_ALGORITHM", string(EventSigningNone))),
+		KeyID:        common.GetEnv("EVENT_SIGNING_KEY_ID", ""),
+		Key:          common.GetEnv("EVENT_SIGNING_KEY", ""),
+		PreviousKeys: previous,
+	}, nil
+}
+
+func parsePreviousKeys(keys string) (map[string]string, error) {
+	previous := map[string]string{}
+	for _, pair := range strings.Split(keys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kid, key, ok := strings.Cut(pair, "=")
+		if !ok || kid == "" || key == "" {
+			return nil, fmt.Errorf("%w: invalid previous key %q", ErrInvalidSigningConfig, pair)
+		}
+		previous[kid] = key
+	}
+	return previous, nil
+}
+
+func newEventSigner(config EventSigningConfig) (eventSigner, error) {
+	if config.Algorithm == EventSigningNone || config.Algorithm == "" {
+		return noopSigner{}, nil
+	}
+	if config.KeyID == "" {
+		return nil, fmt.Errorf("%w: key id is required", ErrInvalidSigningConfig)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(config.Key)
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("%w: key must be base64 encoded", ErrInvalidSigningConfig)
+	}
+
+	switch config.Algorithm {
+	case EventSigningEd25519:
+		return newEd25519Signer(config.KeyID, key, config.PreviousKeys)
+	case EventSigningHMAC:
+		return hmacSigner{keyID: config.KeyID, secret: key}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidSigningConfig, config.Algorithm)
+}
+
+func newEd25519Signer(keyID string, key []byte, previousKeys map[string]string) (eventSigner, error) {
+	var privateKey ed25519.PrivateKey
+	switch len(key) {
+	case ed25519.SeedSize:
+		privateKey = ed25519.NewKeyFromSeed(key)
+	case ed25519.PrivateKeySize:
+		privateKey = ed25519.PrivateKey(key)
+	default:
+		return nil, fmt.Errorf("%w: Ed25519 key must be a %d bytes seed or a %d bytes private key", ErrInvalidSigningConfig, ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+
+	previous := []VerificationKey{}
+	for kid, k := range previousKeys {
+		if kid == keyID {
+			return nil, fmt.Errorf("%w: previous key %s has the id of the current key", ErrInvalidSigningConfig, kid)
+		}
+		publicKey, err := base64.StdEncoding.DecodeString(k)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: previous key %s must be a base64 encoded Ed25519 public key", ErrInvalidSigningConfig, kid)
+		}
+		previous = append(previous, VerificationKey{KeyID: kid, Algorithm: EventSigningEd25519, PublicKey: publicKey})
+	}
+
+	sort.Slice(previous, func(i, j int) bool {
+		return previous[i].KeyID < previous[j].KeyID
+	})
+
+	return ed25519Signer{
+		keyID:    keyID,
+		key:      privateKey,
+		previous: previous,
+	}, nil
+}
+
+// signedContent is the message id and the body separated by a dot,
+// so the signature of an event couldn't be reused with another message id.
+func signedContent(messageID string, body []byte) []byte {
+	content := make([]byte, 0, len(messageID)+1+len(body))
+	content = append(content, messageID...)
+	content = append(content, '.')
+	return append(content, body...)
+}
+
+func signatureHeaders(algorithm EventSigningAlgorithm, keyID string, signature []byte) amqp.Table {
+	return amqp.Table{
+		HeaderSignature:          base64.StdEncoding.EncodeToString(signature),
+		HeaderSignatureKeyID:     keyID,
+		HeaderSignatureAlgorithm: string(algorithm),
+	}
+}
+
+func (noopSigner) sign(string, []byte) amqp.Table {
+	return nil
+}
+
+func (noopSigner) verificationKeys() []VerificationKey {
+	return []VerificationKey{}
+}
+
+func (s ed25519Signer) sign(messageID string, body []byte) amqp.Table {
+	signature := ed25519.Sign(s.key, signedContent(messageID, body))
+	return signatureHeaders(EventSigningEd25519, s.keyID, signature)
+}
+
+// verificationKeys returns the public key of the current key followed by the previous keys
+func (s ed25519Signer) verificationKeys() []VerificationKey {
+	current := VerificationKey{
+		KeyID:     s.keyID,
+		Algorithm: EventSigningEd25519,
+		PublicKey: s.key.Public().(ed25519.PublicKey),
+	}
+	return append([]VerificationKey{current}, s.previous...)
+}
+
+func (s hmacSigner) sign(messageID string, body []byte) amqp.Table {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(signedContent(messageID, body))
+	return signatureHeaders(EventSigningHMAC, s.keyID, mac.Sum(nil))
+}
+
+// verificationKeys doesn't return the HMAC secret, since it must not be published
+func (s hmacSigner) verificationKeys() []VerificationKey {
+	return []VerificationKey{}
+}
@@ -0,0 +1,103 @@
+package user
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	eventSignerTestSuite struct {
+		seed []byte
+		suite.Suite
+	}
+)
+
+func TestEventSignerTestSuite(t *testing.T) {
+	suite.Run(t, new(eventSignerTestSuite))
+}
+
+func (s *eventSignerTestSuite) SetupTest() {
+	s.seed = []byte("0123456789abcdef0123456789abcdef")
+}
+
+func (s *eventSignerTestSuite) TestNewEventSigner_ReturnsErrorOnInvalidConfig() {
+	key := base64.StdEncoding.EncodeToString(s.seed)
+	for _, config := range []EventSigningConfig{
+		{Algorithm: EventSigningEd25519, Key: key},
+		{Algorithm: EventSigningEd25519, KeyID: "key-1"},
+		{Algorithm: EventSigningEd25519, KeyID: "key-1", Key: "not base64"},
+		{Algorithm: EventSigningEd25519, KeyID: "key-1", Key: base64.StdEncoding.EncodeToString([]byte("short"))},
+		{Algorithm: EventSigningEd25519, KeyID: "key-1", Key: key, PreviousKeys: map[string]string{"key-0": key + "AA"}},
+		{Algorithm: EventSigningEd25519, KeyID: "key-1", Key: key, PreviousKeys: map[string]string{"key-1": key}},
+		{Algorithm: "rsa", KeyID: "key-1", Key: key},
+	} {
+		_, err := newEventSigner(config)
+		s.ErrorIs(err, ErrInvalidSigningConfig)
+	}
+}
+
+func (s *eventSignerTestSuite) TestNoopSigner() {
+	signer, err := newEventSigner(EventSigningConfig{Algorithm: EventSigningNone})
+	s.Require().NoError(err)
+
+	s.Nil(signer.sign("event-1", []byte("body")))
+	s.Empty(signer.verificationKeys())
+}
+
+func (s *eventSignerTestSuite) TestEd25519Signer() {
+	previous := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+	signer, err := newEventSigner(EventSigningConfig{
+		Algorithm:    EventSigningEd25519,
+		KeyID:        "key-2",
+		Key:          base64.StdEncoding.EncodeToString(s.seed),
+		PreviousKeys: map[string]string{"key-1": base64.StdEncoding.EncodeToString(previous)},
+	})
+	s.Require().NoError(err)
+
+	headers := signer.sign("event-1", []byte("body"))
+	s.Equal("key-2", headers[HeaderSignatureKeyID])
+	s.Equal(string(EventSigningEd25519), headers[HeaderSignatureAlgorithm])
+
+	keys := signer.verificationKeys()
+	s.Require().Len(keys, 2)
+	s.Equal("key-2", keys[0].KeyID)
+	s.Equal(ed25519.NewKeyFromSeed(s.seed).Public(), keys[0].PublicKey)
+	s.Equal(VerificationKey{KeyID: "key-1", Algorithm: EventSigningEd25519, PublicKey: previous}, keys[1])
+
+	signature, err := base64.StdEncoding.DecodeString(headers[HeaderSignature].(string))
+	s.NoError(err)
+	s.True(ed25519.Verify(keys[0].PublicKey, []byte("event-1.body"), signature))
+	s.False(ed25519.Verify(keys[0].PublicKey, []byte("event-2.body"), signature))
+}
+
+func (s *eventSignerTestSuite) TestHMACSigner() {
+	signer, err := newEventSigner(EventSigningConfig{
+		Algorithm: EventSigningHMAC,
+		KeyID:     "key-1",
+		Key:       base64.StdEncoding.EncodeToString([]byte("secret")),
+	})
+	s.Require().NoError(err)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("event-1.body"))
+
+	headers := signer.sign("event-1", []byte("body"))
+	s.Equal(base64.StdEncoding.EncodeToString(mac.Sum(nil)), headers[HeaderSignature])
+	s.Equal("key-1", headers[HeaderSignatureKeyID])
+	s.Equal(string(EventSigningHMAC), headers[HeaderSignatureAlgorithm])
+	s.Empty(signer.verificationKeys())
+}
+
+func (s *eventSignerTestSuite) TestParsePreviousKeys() {
+	keys, err := parsePreviousKeys(" key-1=a2V5MQ==, key-2=a2V5Mg==,")
+	s.NoError(err)
+	s.Equal(map[string]string{"key-1": "a2V5MQ==", "key-2": "a2V5Mg=="}, keys)
+
+	_, err = parsePreviousKeys("key-1")
+	s.ErrorIs(err, ErrInvalidSigningConfig)
+}
@@ -0,0 +1,144 @@
+package user
+
+import (
+	"context"
+	"faceit/internal/common"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// finalizedEvents matches the events of the transactions what are older than every running transaction, so no event
+// could be committed anymore before them in the (transaction id, id) order
+const finalizedEvents = "tx_id < pg_snapshot_xmin(pg_current_snapshot())"
+
+type (
+	// StoredEvent is a persisted user event with its unique id
+	StoredEvent struct {
+		ID            int64  `json:"id"`
+		CorrelationID string `json:"correlation_id"`
+		UserEvent
+	}
+
+	// EventFilter limits the events by type, user and time, empty fields match every event
+	EventFilter struct {
+		Types   []UserEventType
+		UserIDs []uuid.UUID
+		Since   time.Time
+	}
+
+	gormEventStore struct {
+		db     *gorm.DB
+		signal *eventSignal
+	}
+
+	// eventSignal wakes up the waiting readers when a new event was saved
+	eventSignal struct {
+		mu sync.Mutex
+		ch chan struct{}
+	}
+)
+
+func (StoredEvent) TableName() string {
+	return "user_events"
+}
+
+func (f EventFilter) Validate() error {
+	return ValidateEventTypes(f.Types)
+}
+
+func newEventStore(db *gorm.DB) *gormEventStore {
+	return &gormEventStore{
+		db:     db,
+		signal: &eventSignal{ch: make(chan struct{})},
+	}
+}
+
+// transaction runs the change in a database transaction what is passed to the repositories in the context,
+// so the events of the change are saved in the same transaction as the change itself. The waiting readers are
+// woken up when the transaction is committed.
+func (s *gormEventStore) transaction(ctx context.Context, change func(ctx context.Context) error) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return change(withTransaction(ctx, tx))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.signal.broadcast()
+	return nil
+}
+
+// save inserts the events in the transaction of the context. Every event gets the id of its transaction
+// by the database, what orders the events by their commit instead of by their insert.
+func (s *gormEventStore) save(ctx context.Context, events []UserEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	stored := make([]StoredEvent, len(events))
+	for i, event := range events {
+		stored[i] = StoredEvent{
+			CorrelationID: common.GetCorrelationID(ctx),
+			UserEvent:     event,
+		}
+	}
+	return conn(ctx, s.db).Create(&stored).Error
+}
+
+// listAfter returns the matching events after the event of afterID (or from the first event for 0) in the order of
+// their transactions. Only the events of the finished transactions are returned, so a reader what continues after
+// the last read event doesn't skip an event what is committed later, but a long-running transaction of the users
+// delays the events of the transactions what were started after it.
+func (s *gormEventStore) listAfter(ctx context.Context, afterID int64, filter EventFilter, limit int) ([]StoredEvent, error) {
+	query := s.db.WithContext(ctx).
+		Where(finalizedEvents).
+		Where("(tx_id, id) > (COALESCE((SELECT e.tx_id FROM user_events e WHERE e.id = ?), '0'::xid8), ?)", afterID, afterID)
+	if len(filter.Types) > 0 {
+		query = query.Where("type IN ?", filter.Types)
+	}
+	if len(filter.UserIDs) > 0 {
+		query = query.Where("user_id IN ?", filter.UserIDs)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("time >= ?", filter.Since)
+	}
+
+	var events []StoredEvent
+	err := query.Order("tx_id asc, id asc").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// lastID returns the id of the latest finalized event or 0 when there are no events yet.
+func (s *gormEventStore) lastID(ctx context.Context) (int64, error) {
+	var id int64
+	err := s.db.WithContext(ctx).
+		Model(&StoredEvent{}).
+		Select("id").
+		Where(finalizedEvents).
+		Order("tx_id desc, id desc").
+		Limit(1).
+		Scan(&id).
+		Error
+	return id, err
+}
+
+// changed returns a channel what is closed when the next transaction of this instance is committed.
+func (s *gormEventStore) changed() <-chan struct{} {
+	return s.signal.wait()
+}
+
+func (s *eventSignal) wait() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ch
+}
+
+func (s *eventSignal) broadcast() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.ch)
+	s.ch = make(chan struct{})
+}
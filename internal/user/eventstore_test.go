@@ -0,0 +1,103 @@
+//go:build integration
+// +build integration
+
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	eventStoreTestSuite struct {
+		store *gormEventStore
+		suite.Suite
+	}
+)
+
+func TestEventStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(eventStoreTestSuite))
+}
+
+func (s *eventStoreTestSuite) SetupSuite() {
+	r, err := NewRepository()
+	s.Require().NoError(err)
+	s.store = newEventStore(r.db)
+}
+
+func (s *eventStoreTestSuite) TestSaveAndListAfter() {
+	ctx := context.TODO()
+	last, err := s.store.lastID(ctx)
+	s.Require().NoError(err)
+
+	id := uuid.New()
+	changed := s.store.changed()
+	err = s.store.transaction(ctx, func(ctx context.Context) error {
+		return s.store.save(ctx, []UserEvent{
+			newUserEvent(UserEventTypeCreated, id, 1, &User{ID: id, Nickname: "johndoe"}),
+			newUserEvent(UserEventTypeDeleted, id, 1, nil),
+			newUserEvent(UserEventTypeDeleted, uuid.New(), 1, nil),
+		})
+	})
+	s.Require().NoError(err)
+
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		s.Fail("store didn't signal the change")
+	}
+
+	events, err := s.store.listAfter(ctx, last, EventFilter{UserIDs: []uuid.UUID{id}}, 10)
+	s.NoError(err)
+	s.Require().Len(events, 2)
+	s.Equal("johndoe", events[0].UserChanges.Nickname)
+	s.Equal(UserEventTypeDeleted, events[1].Type)
+
+	events, err = s.store.listAfter(ctx, events[0].ID, EventFilter{Types: []UserEventType{UserEventTypeDeleted}}, 1)
+	s.NoError(err)
+	s.Len(events, 1)
+	s.Equal(id, events[0].UserID)
+}
+
+func (s *eventStoreTestSuite) TestListAfter_WaitsForEarlierTransactions() {
+	ctx := context.TODO()
+	last, err := s.store.lastID(ctx)
+	s.Require().NoError(err)
+
+	id := uuid.New()
+	filter := EventFilter{UserIDs: []uuid.UUID{id}}
+	saved, commit, done := make(chan struct{}), make(chan struct{}), make(chan error)
+	go func() {
+		done <- s.store.transaction(ctx, func(ctx context.Context) error {
+			defer close(saved)
+			if err := s.store.save(ctx, []UserEvent{newUserEvent(UserEventTypeCreated, id, 1, nil)}); err != nil {
+				return err
+			}
+			saved <- struct{}{}
+			<-commit
+			return nil
+		})
+	}()
+	<-saved
+
+	err = s.store.transaction(ctx, func(ctx context.Context) error {
+		return s.store.save(ctx, []UserEvent{newUserEvent(UserEventTypeDeleted, id, 2, nil)})
+	})
+	s.Require().NoError(err)
+
+	events, err := s.store.listAfter(ctx, last, filter, 10)
+	s.NoError(err)
+	s.Empty(events, "the committed event waits until the earlier transaction is finished")
+
+	close(commit)
+	s.Require().NoError(<-done)
+	events, err = s.store.listAfter(ctx, last, filter, 10)
+	s.NoError(err)
+	s.Require().Len(events, 2)
+	s.Equal(UserEventTypeCreated, events[0].Type)
+	s.Equal(UserEventTypeDeleted, events[1].Type)
+}
@@ -0,0 +1,144 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	apispec "faceit/api"
+	"faceit/internal/common"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rs/zerolog/log"
+)
+
+// EventValidationMode defines what happens when a published event doesn't conform to its schema
+type EventValidationMode string
+
+const (
+	// EventValidationOff skips the schema validation
+	EventValidationOff EventValidationMode = "off"
+	// EventValidationLog logs the invalid events but publishes them
+	EventValidationLog EventValidationMode = "log"
+	// EventValidationStrict rejects the invalid events
+	EventValidationStrict EventValidationMode = "strict"
+)
+
+var (
+	ErrInvalidEvent = errors.New("event doesn't conform to its schema")
+
+	invalidEvents = expvar.NewInt("user_events_invalid")
+)
+
+func init() {
+	openapi3.DefineStringFormat("uuid", openapi3.FormatOfStringForUUIDOfRFC4122)
+	// the whole schema and value would be logged with every error otherwise
+	openapi3.SchemaErrorDetailsDisabled = true
+}
+
+// eventValidator validates the user events against the JSON Schemas of the AsyncAPI definition
+type eventValidator struct {
+	schemas map[UserEventType]*openapi3.Schema
+}
+
+// newEventValidator loads the schema of every known event type from the `schemas/<lower case event type>.json` files.
+func newEventValidator(schemas fs.FS) (*eventValidator, error) {
+	v := eventValidator{schemas: map[UserEventType]*openapi3.Schema{}}
+	for t := range knownEventTypes {
+		data, err := fs.ReadFile(schemas, fmt.Sprintf("schemas/%s.json", strings.ToLower(string(t))))
+		if err != nil {
+			return nil, err
+		}
+
+		schema := openapi3.NewSchema()
+		if err := schema.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("invalid %s schema: %w", t, err)
+		}
+		v.schemas[t] = schema
+	}
+	return &v, nil
+}
+
+func (v eventValidator) validate(event UserEvent) error {
+	data, err := json.Marshal(&event)
+	if err != nil {
+		return err
+	}
+	return v.validateJSON(event.Type, data)
+}
+
+// validateJSON validates the JSON encoded event against the schema of its type
+func (v eventValidator) validateJSON(eventType UserEventType, data []byte) error {
+	schema, ok := v.schemas[eventType]
+	if !ok {
+		return fmt.Errorf("%w: unknown event type %s", ErrInvalidEvent, eventType)
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+
+	if err := schema.VisitJSON(payload, openapi3.MultiErrors()); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidEvent, err.Error())
+	}
+	return nil
+}
+
+// eventSchemaCheck validates the published message bodies after the projection and the encoding of the events,
+// so the replayed and the PII-free events are checked as well. The protobuf bodies are checked by the JSON
+// of the same projected event. The invalid events are rejected in strict mode, otherwise they are only logged and counted.
+type eventSchemaCheck struct {
+	validator *eventValidator
+	strict    bool
+}
+
+func newEventSchemaCheck(mode EventValidationMode) (eventSchemaCheck, error) {
+	switch mode {
+	case EventValidationOff:
+		return eventSchemaCheck{}, nil
+	case EventValidationLog, EventValidationStrict:
+	default:
+		return eventSchemaCheck{}, fmt.Errorf("unknown event validation mode %s", mode)
+	}
+
+	v, err := newEventValidator(apispec.EventSchemas)
+	if err != nil {
+		return eventSchemaCheck{}, err
+	}
+	return eventSchemaCheck{
+		validator: v,
+		strict:    mode == EventValidationStrict,
+	}, nil
+}
+
+func (c eventSchemaCheck) check(ctx context.Context, event UserEvent, body []byte, contentType string) error {
+	if c.validator == nil {
+		return nil
+	}
+
+	data := body
+	if contentType != jsonType {
+		var err error
+		if data, err = json.Marshal(&event); err != nil {
+			return err
+		}
+	}
+
+	if err := c.validator.validateJSON(event.Type, data); err != nil {
+		invalidEvents.Add(1)
+		if c.strict {
+			return err
+		}
+		log.Warn().
+			Err(err).
+			Str(common.CorrelationID, common.GetCorrelationID(ctx)).
+			Stringer("ID", event.UserID).
+			Str("type", string(event.Type)).
+			Msg("publishing invalid user event")
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package user
+
+import (
+	"context"
+	apispec "faceit/api"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	eventValidatorTestSuite struct {
+		validator *eventValidator
+		suite.Suite
+	}
+)
+
+func TestEventValidatorTestSuite(t *testing.T) {
+	suite.Run(t, new(eventValidatorTestSuite))
+}
+
+func (s *eventValidatorTestSuite) SetupTest() {
+	v, err := newEventValidator(apispec.EventSchemas)
+	s.Require().NoError(err)
+	s.validator = v
+}
+
+func (s *eventValidatorTestSuite) TestValidate_AcceptsPublishedEvents() {
+	created := validUser
+	created.ID = uuid.New()
+	created.CreatedAt = time.Now()
+	updated := created
+	updated.UpdatedAt = &created.CreatedAt
+
+	for _, event := range []UserEvent{
+		newUserEvent(UserEventTypeCreated, created.ID, 1, &created),
+		newUserEvent(UserEventTypeUpdated, updated.ID, 1, &updated),
+		newUserEvent(UserEventTypePasswordChanged, created.ID, 1, nil),
+		newUserEvent(UserEventTypeDeleted, created.ID, 1, nil),
+		fieldChangedEvent(UserEventTypeEmailChanged, created.ID, "old@email.com", "new@email.com"),
+		fieldChangedEvent(UserEventTypeNicknameChanged, created.ID, "johndoe", "jdoe"),
+		fieldChangedEvent(UserEventTypeCountryChanged, created.ID, "US", "UK"),
+	} {
+		s.Run(string(event.Type), func() {
+			s.NoError(s.validator.validate(event))
+		})
+	}
+}
+
+func (s *eventValidatorTestSuite) TestValidate_RejectsInvalidEvents() {
+	id := uuid.New()
+	invalidUser := validUser
+	invalidUser.ID = id
+	invalidUser.Country = "USA"
+
+	for _, test := range []struct {
+		name  string
+		event UserEvent
+	}{
+		{name: "unknown type", event: newUserEvent("USER_LOGGED_IN", id, 1, nil)},
+		{name: "missing user changes", event: newUserEvent(UserEventTypeCreated, id, 1, nil)},
+		{name: "invalid user changes", event: newUserEvent(UserEventTypeUpdated, id, 1, &invalidUser)},
+		{name: "unexpected user changes", event: newUserEvent(UserEventTypeDeleted, id, 1, &validUser)},
+		{name: "missing field change", event: newUserEvent(UserEventTypeEmailChanged, id, 1, nil)},
+		{name: "invalid field change", event: fieldChangedEvent(UserEventTypeCountryChanged, id, "US", "USA")},
+	} {
+		s.Run(test.name, func() {
+			s.ErrorIs(s.validator.validate(test.event), ErrInvalidEvent)
+		})
+	}
+}
+
+func (s *eventValidatorTestSuite) TestSchemaCheck() {
+	invalid := newUserEvent(UserEventTypeCreated, uuid.New(), 1, nil)
+	body, err := jsonEncoder{}.encode(invalid)
+	s.Require().NoError(err)
+
+	strict := eventSchemaCheck{validator: s.validator, strict: true}
+	s.ErrorIs(strict.check(context.TODO(), invalid, body, jsonType), ErrInvalidEvent)
+	s.ErrorIs(strict.check(context.TODO(), invalid, nil, protobufType), ErrInvalidEvent, "protobuf is checked by the JSON of the event")
+
+	logOnly := eventSchemaCheck{validator: s.validator}
+	s.NoError(logOnly.check(context.TODO(), invalid, body, jsonType))
+
+	s.NoError(eventSchemaCheck{}.check(context.TODO(), invalid, body, jsonType), "validation is off")
+}
+
+func (s *eventValidatorTestSuite) TestSchemaCheck_ChecksProjectedBody() {
+	u := validUser
+	u.ID = uuid.New()
+	u.CreatedAt = time.Now()
+	projected, ok := piiFreeProjection.apply(newUserEvent(UserEventTypeCreated, u.ID, 1, &u))
+	s.Require().True(ok)
+	body, err := jsonEncoder{}.encode(projected)
+	s.Require().NoError(err)
+	s.NotContains(string(body), "first_name")
+
+	strict := eventSchemaCheck{validator: s.validator, strict: true}
+	s.NoError(strict.check(context.TODO(), projected, body, jsonType))
+	s.ErrorIs(strict.check(context.TODO(), projected, []byte(`{"type": "USER_CREATED"}`), jsonType), ErrInvalidEvent,
+		"the encoded body is checked")
+}
+
+func (s *eventValidatorTestSuite) TestNewEventSchemaCheck() {
+	c, err := newEventSchemaCheck(EventValidationOff)
+	s.NoError(err)
+	s.Nil(c.validator)
+
+	c, err = newEventSchemaCheck(EventValidationStrict)
+	s.NoError(err)
+	s.NotNil(c.validator)
+	s.True(c.strict)
+
+	_, err = newEventSchemaCheck("sometimes")
+	s.Error(err)
+}
+
+func fieldChangedEvent(eventType UserEventType, userID uuid.UUID, old string, new string) UserEvent {
+	event := newUserEvent(eventType, userID, 2, nil)
+	event.Change = &FieldChange{Old: old, New: new}
+	return event
+}
@@ -0,0 +1,74 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+)
+
+// userFields are the fields of the user responses by their JSON name with their column.
+// Only these columns could be selected by the sparse fieldsets.
+var userFields = map[string]string{
+	"id":         "id",
+	"first_name": "first_name",
+	"last_name":  "last_name",
+	"nickname":   "nickname",
+	"email":      "email",
+	"country":    "country",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// Fields is a sparse fieldset of the users, the id is always selected. Every field is selected when it's empty.
+type Fields []string
+
+// ParseFields parses the comma separated field names, i.e. `id,nickname`.
+func ParseFields(fields string) Fields {
+	var parsed Fields
+	for _, field := range strings.Split(fields, ",") {
+		parsed = append(parsed, strings.TrimSpace(field))
+	}
+	return parsed
+}
+
+// Validate checks that every field is known and selected only once.
+func (f Fields) Validate() error {
+	selected := map[string]bool{}
+	for _, field := range f {
+		if _, ok := userFields[field]; !ok {
+			return fmt.Errorf("unknown field %q", field)
+		}
+		if selected[field] {
+			return fmt.Errorf("duplicated field %s", field)
+		}
+		selected[field] = true
+	}
+	return nil
+}
+
+// Has is true when the field is selected
+func (f Fields) Has(field string) bool {
+	if len(f) == 0 || field == "id" {
+		return true
+	}
+	for _, selected := range f {
+		if selected == field {
+			return true
+		}
+	}
+	return false
+}
+
+// columns returns the selected columns of the validated fieldset, or nil when every column is selected
+func (f Fields) columns() []string {
+	if len(f) == 0 {
+		return nil
+	}
+
+	columns := []string{"id"}
+	for _, field := range f {
+		if field != "id" {
+			columns = append(columns, userFields[field])
+		}
+	}
+	return columns
+}
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	fieldsTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestFieldsTestSuite(t *testing.T) {
+	suite.Run(t, new(fieldsTestSuite))
+}
+
+func (s *fieldsTestSuite) TestParseFields() {
+	fields := ParseFields("nickname, country")
+	s.Equal(Fields{"nickname", "country"}, fields)
+	s.NoError(fields.Validate())
+	s.Equal([]string{"id", "nickname", "country"}, fields.columns())
+
+	s.True(fields.Has("id"))
+	s.True(fields.Has("nickname"))
+	s.False(fields.Has("email"))
+}
+
+func (s *fieldsTestSuite) TestEmptyFields() {
+	var fields Fields
+	s.NoError(fields.Validate())
+	s.Nil(fields.columns())
+	s.True(fields.Has("email"))
+}
+
+func (s *fieldsTestSuite) TestColumns_SelectsIDOnce() {
+	s.Equal([]string{"id", "nickname"}, ParseFields("nickname,id").columns())
+}
+
+func (s *fieldsTestSuite) TestValidate_ReturnsError() {
+	for _, fields := range []string{"", "password", "version", "id,", "nickname,nickname", "Nickname"} {
+		s.Run(fields, func() {
+			s.Error(ParseFields(fields).Validate())
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// idempotencyPurgeBatchSize is the number of the expired keys what are deleted by a reservation besides its own key
+const idempotencyPurgeBatchSize = 100
+
+type (
+	// IdempotencyRecord is the stored result of a create request with an idempotency key.
+	// The Response is empty while the request is in progress, then the record expires at the end of its lease,
+	// so the key of a request what was interrupted before it was completed or released could be reserved again.
+	IdempotencyRecord struct {
+		Key         string `gorm:"primaryKey"`
+		RequestHash string
+		Response    *User `gorm:"serializer:json"`
+		CreatedAt   time.Time
+		ExpiresAt   time.Time
+	}
+
+	gormIdempotencyStore struct {
+		db *gorm.DB
+	}
+)
+
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_keys"
+}
+
+func newIdempotencyStore(db *gorm.DB) *gormIdempotencyStore {
+	return &gormIdempotencyStore{db: db}
+}
+
+// reserve saves the key with the request hash if it doesn't exist or it's expired, and reports whether it was saved.
+// The existing record is returned otherwise. Every reservation deletes a batch of the expired keys as well,
+// so the keys what are never reused don't stay in the table after their expiry. The keys locked by the other
+// reservations are skipped, so the concurrent reservations don't wait for each other.
+func (s *gormIdempotencyStore) reserve(ctx context.Context, key string, requestHash string, expiresAt time.Time) (*IdempotencyRecord, bool, error) {
+	record := IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   expiresAt,
+	}
+
+	var reserved bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		expired := tx.Model(&IdempotencyRecord{}).
+			Select("key").
+			Where("expires_at < ?", now).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Limit(idempotencyPurgeBatchSize)
+		err := tx.Where("expires_at < ?", now).
+			Where("key = ? OR key IN (?)", key, expired).
+			Delete(&IdempotencyRecord{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			reserved = true
+			return nil
+		}
+
+		return tx.Take(&record, "key = ?", key).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &record, reserved, nil
+}
+
+// complete saves the response of the reserved key what is kept until expiresAt.
+func (s *gormIdempotencyStore) complete(ctx context.Context, key string, response *User, expiresAt time.Time) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).
+		Model(&IdempotencyRecord{}).
+		Where("key = ?", key).
+		Updates(map[string]interface{}{"response": string(data), "expires_at": expiresAt}).
+		Error
+}
+
+// release deletes the reserved key, so the request could be retried with the same key.
+func (s *gormIdempotencyStore) release(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Delete(&IdempotencyRecord{}, "key = ?", key).Error
+}
+
+// createRequestHash identifies the content of a create request without storing the password.
+func createRequestHash(user User, password string) string {
+	data, _ := json.Marshal(struct {
+		User     User   `json:"user"`
+		Password string `json:"password"`
+	}{user, encryptPass(password)})
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
@@ -0,0 +1,119 @@
+//go:build integration
+// +build integration
+
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	idempotencyStoreTestSuite struct {
+		store *gormIdempotencyStore
+		suite.Suite
+	}
+)
+
+func TestIdempotencyStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(idempotencyStoreTestSuite))
+}
+
+func (s *idempotencyStoreTestSuite) SetupSuite() {
+	r, err := NewRepository()
+	s.Require().NoError(err)
+	s.store = newIdempotencyStore(r.db)
+}
+
+func (s *idempotencyStoreTestSuite) TestReserveAndComplete() {
+	ctx := context.TODO()
+	key := uuid.NewString()
+
+	_, reserved, err := s.store.reserve(ctx, key, "hash", time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.True(reserved)
+
+	record, reserved, err := s.store.reserve(ctx, key, "other", time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.False(reserved)
+	s.Equal("hash", record.RequestHash)
+	s.Nil(record.Response)
+
+	u := &User{ID: uuid.New(), Nickname: "johndoe"}
+	s.Require().NoError(s.store.complete(ctx, key, u, time.Now().Add(time.Hour)))
+
+	record, reserved, err = s.store.reserve(ctx, key, "hash", time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.False(reserved)
+	s.Equal(u.ID, record.Response.ID)
+	s.Equal("johndoe", record.Response.Nickname)
+}
+
+func (s *idempotencyStoreTestSuite) TestReserve_ReplacesExpiredKey() {
+	ctx := context.TODO()
+	key := uuid.NewString()
+
+	_, reserved, err := s.store.reserve(ctx, key, "hash", time.Now().Add(-time.Second))
+	s.Require().NoError(err)
+	s.True(reserved)
+
+	record, reserved, err := s.store.reserve(ctx, key, "other", time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.True(reserved)
+	s.Equal("other", record.RequestHash)
+}
+
+func (s *idempotencyStoreTestSuite) TestReserve_PurgesExpiredKeys() {
+	ctx := context.TODO()
+	expired := []string{uuid.NewString(), uuid.NewString()}
+	for _, key := range expired {
+		_, _, err := s.store.reserve(ctx, key, "hash", time.Now().Add(-time.Second))
+		s.Require().NoError(err)
+	}
+
+	_, reserved, err := s.store.reserve(ctx, uuid.NewString(), "hash", time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.True(reserved)
+
+	var count int64
+	s.Require().NoError(s.store.db.Model(&IdempotencyRecord{}).Where("key IN ?", expired).Count(&count).Error)
+	s.Zero(count, "the expired keys are deleted without reusing them")
+}
+
+func (s *idempotencyStoreTestSuite) TestReserve_ReclaimsKeyAfterLease() {
+	ctx := context.TODO()
+	key := uuid.NewString()
+
+	// the first request was interrupted before it was completed or released
+	_, reserved, err := s.store.reserve(ctx, key, "hash", time.Now().Add(-time.Second))
+	s.Require().NoError(err)
+	s.True(reserved)
+
+	_, reserved, err = s.store.reserve(ctx, key, "hash", time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+	s.True(reserved)
+
+	// the completed key is kept until its expiry instead of the lease
+	s.Require().NoError(s.store.complete(ctx, key, &User{ID: uuid.New()}, time.Now().Add(time.Hour)))
+	record, reserved, err := s.store.reserve(ctx, key, "hash", time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+	s.False(reserved)
+	s.NotNil(record.Response)
+}
+
+func (s *idempotencyStoreTestSuite) TestRelease() {
+	ctx := context.TODO()
+	key := uuid.NewString()
+
+	_, _, err := s.store.reserve(ctx, key, "hash", time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.Require().NoError(s.store.release(ctx, key))
+
+	_, reserved, err := s.store.reserve(ctx, key, "other", time.Now().Add(time.Hour))
+	s.Require().NoError(err)
+	s.True(reserved)
+}
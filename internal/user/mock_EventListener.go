@@ -0,0 +1,34 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package user
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockEventListener is an autogenerated mock type for the EventListener type
+type MockEventListener struct {
+	mock.Mock
+}
+
+// OnUserEvent provides a mock function with given fields: ctx, event
+func (_m *MockEventListener) OnUserEvent(ctx context.Context, event UserEvent) {
+	_m.Called(ctx, event)
+}
+
+type mockConstructorTestingTNewMockEventListener interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockEventListener creates a new instance of MockEventListener. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockEventListener(t mockConstructorTestingTNewMockEventListener) *MockEventListener {
+	mock := &MockEventListener{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,62 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package user
+
+import mock "github.com/stretchr/testify/mock"
+
+// mockEventEncoder is an autogenerated mock type for the eventEncoder type
+type mockEventEncoder struct {
+	mock.Mock
+}
+
+// contentType provides a mock function with given fields:
+func (_m *mockEventEncoder) contentType() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// encode provides a mock function with given fields: event
+func (_m *mockEventEncoder) encode(event UserEvent) ([]byte, error) {
+	ret := _m.Called(event)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(UserEvent) []byte); ok {
+		r0 = rf(event)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(UserEvent) error); ok {
+		r1 = rf(event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTnewMockEventEncoder interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockEventEncoder creates a new instance of mockEventEncoder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockEventEncoder(t mockConstructorTestingTnewMockEventEncoder) *mockEventEncoder {
+	mock := &mockEventEncoder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
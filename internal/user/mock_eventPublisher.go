@@ -5,7 +5,6 @@ package user
 import (
 	context "context"
 
-	uuid "github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,13 +13,13 @@ type mockEventPublisher struct {
 	mock.Mock
 }
 
-// publishCreated provides a mock function with given fields: ctx, userID, userChanges
-func (_m *mockEventPublisher) publishCreated(ctx context.Context, userID uuid.UUID, userChanges *User) error {
-	ret := _m.Called(ctx, userID, userChanges)
+// close provides a mock function with given fields: ctx
+func (_m *mockEventPublisher) close(ctx context.Context) error {
+	ret := _m.Called(ctx)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *User) error); ok {
-		r0 = rf(ctx, userID, userChanges)
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -28,41 +27,13 @@ func (_m *mockEventPublisher) publishCreated(ctx context.Context, userID uuid.UU
 	return r0
 }
 
-// publishDeleted provides a mock function with given fields: ctx, userID
-func (_m *mockEventPublisher) publishDeleted(ctx context.Context, userID uuid.UUID) error {
-	ret := _m.Called(ctx, userID)
+// publish provides a mock function with given fields: ctx, event
+func (_m *mockEventPublisher) publish(ctx context.Context, event UserEvent) error {
+	ret := _m.Called(ctx, event)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, userID)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// publishPasswordChanged provides a mock function with given fields: ctx, userID
-func (_m *mockEventPublisher) publishPasswordChanged(ctx context.Context, userID uuid.UUID) error {
-	ret := _m.Called(ctx, userID)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, userID)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// publishUpdated provides a mock function with given fields: ctx, userID, userChanges
-func (_m *mockEventPublisher) publishUpdated(ctx context.Context, userID uuid.UUID, userChanges *User) error {
-	ret := _m.Called(ctx, userID, userChanges)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *User) error); ok {
-		r0 = rf(ctx, userID, userChanges)
+	if rf, ok := ret.Get(0).(func(context.Context, UserEvent) error); ok {
+		r0 = rf(ctx, event)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package user
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockEventSender is an autogenerated mock type for the eventSender type
+type mockEventSender struct {
+	mock.Mock
+}
+
+// close provides a mock function with given fields: ctx
+func (_m *mockEventSender) close(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// publish provides a mock function with given fields: ctx, event
+func (_m *mockEventSender) publish(ctx context.Context, event UserEvent) error {
+	ret := _m.Called(ctx, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, UserEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTnewMockEventSender interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockEventSender creates a new instance of mockEventSender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockEventSender(t mockConstructorTestingTnewMockEventSender) *mockEventSender {
+	mock := &mockEventSender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
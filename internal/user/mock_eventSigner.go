@@ -0,0 +1,60 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package user
+
+import (
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockEventSigner is an autogenerated mock type for the eventSigner type
+type mockEventSigner struct {
+	mock.Mock
+}
+
+// sign provides a mock function with given fields: messageID, body
+func (_m *mockEventSigner) sign(messageID string, body []byte) amqp091.Table {
+	ret := _m.Called(messageID, body)
+
+	var r0 amqp091.Table
+	if rf, ok := ret.Get(0).(func(string, []byte) amqp091.Table); ok {
+		r0 = rf(messageID, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(amqp091.Table)
+		}
+	}
+
+	return r0
+}
+
+// verificationKeys provides a mock function with given fields:
+func (_m *mockEventSigner) verificationKeys() []VerificationKey {
+	ret := _m.Called()
+
+	var r0 []VerificationKey
+	if rf, ok := ret.Get(0).(func() []VerificationKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]VerificationKey)
+		}
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTnewMockEventSigner interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockEventSigner creates a new instance of mockEventSigner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockEventSigner(t mockConstructorTestingTnewMockEventSigner) *mockEventSigner {
+	mock := &mockEventSigner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
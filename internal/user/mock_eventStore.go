@@ -0,0 +1,117 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package user
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockEventStore is an autogenerated mock type for the eventStore type
+type mockEventStore struct {
+	mock.Mock
+}
+
+// changed provides a mock function with given fields:
+func (_m *mockEventStore) changed() <-chan struct{} {
+	ret := _m.Called()
+
+	var r0 <-chan struct{}
+	if rf, ok := ret.Get(0).(func() <-chan struct{}); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	return r0
+}
+
+// lastID provides a mock function with given fields: ctx
+func (_m *mockEventStore) lastID(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// listAfter provides a mock function with given fields: ctx, afterID, filter, limit
+func (_m *mockEventStore) listAfter(ctx context.Context, afterID int64, filter EventFilter, limit int) ([]StoredEvent, error) {
+	ret := _m.Called(ctx, afterID, filter, limit)
+
+	var r0 []StoredEvent
+	if rf, ok := ret.Get(0).(func(context.Context, int64, EventFilter, int) []StoredEvent); ok {
+		r0 = rf(ctx, afterID, filter, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]StoredEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, EventFilter, int) error); ok {
+		r1 = rf(ctx, afterID, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// save provides a mock function with given fields: ctx, events
+func (_m *mockEventStore) save(ctx context.Context, events []UserEvent) error {
+	ret := _m.Called(ctx, events)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []UserEvent) error); ok {
+		r0 = rf(ctx, events)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// transaction provides a mock function with given fields: ctx, change
+func (_m *mockEventStore) transaction(ctx context.Context, change func(context.Context) error) error {
+	ret := _m.Called(ctx, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTnewMockEventStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockEventStore creates a new instance of mockEventStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockEventStore(t mockConstructorTestingTnewMockEventStore) *mockEventStore {
+	mock := &mockEventStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,88 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package user
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockIdempotencyStore is an autogenerated mock type for the idempotencyStore type
+type mockIdempotencyStore struct {
+	mock.Mock
+}
+
+// complete provides a mock function with given fields: ctx, key, response, expiresAt
+func (_m *mockIdempotencyStore) complete(ctx context.Context, key string, response *User, expiresAt time.Time) error {
+	ret := _m.Called(ctx, key, response, expiresAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *User, time.Time) error); ok {
+		r0 = rf(ctx, key, response, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// release provides a mock function with given fields: ctx, key
+func (_m *mockIdempotencyStore) release(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// reserve provides a mock function with given fields: ctx, key, requestHash, expiresAt
+func (_m *mockIdempotencyStore) reserve(ctx context.Context, key string, requestHash string, expiresAt time.Time) (*IdempotencyRecord, bool, error) {
+	ret := _m.Called(ctx, key, requestHash, expiresAt)
+
+	var r0 *IdempotencyRecord
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *IdempotencyRecord); ok {
+		r0 = rf(ctx, key, requestHash, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*IdempotencyRecord)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) bool); ok {
+		r1 = rf(ctx, key, requestHash, expiresAt)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, time.Time) error); ok {
+		r2 = rf(ctx, key, requestHash, expiresAt)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+type mockConstructorTestingTnewMockIdempotencyStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockIdempotencyStore creates a new instance of mockIdempotencyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockIdempotencyStore(t mockConstructorTestingTnewMockIdempotencyStore) *mockIdempotencyStore {
+	mock := &mockIdempotencyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
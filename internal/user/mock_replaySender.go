@@ -0,0 +1,73 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package user
+
+import (
+	context "context"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockReplaySender is an autogenerated mock type for the replaySender type
+type mockReplaySender struct {
+	mock.Mock
+}
+
+// checkExchange provides a mock function with given fields: exchange
+func (_m *mockReplaySender) checkExchange(exchange string) error {
+	ret := _m.Called(exchange)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(exchange)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// close provides a mock function with given fields: ctx
+func (_m *mockReplaySender) close(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// publishTo provides a mock function with given fields: ctx, target, event, headers
+func (_m *mockReplaySender) publishTo(ctx context.Context, target ReplayTarget, event UserEvent, headers amqp091.Table) error {
+	ret := _m.Called(ctx, target, event, headers)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ReplayTarget, UserEvent, amqp091.Table) error); ok {
+		r0 = rf(ctx, target, event, headers)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTnewMockReplaySender interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockReplaySender creates a new instance of mockReplaySender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockReplaySender(t mockConstructorTestingTnewMockReplaySender) *mockReplaySender {
+	mock := &mockReplaySender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
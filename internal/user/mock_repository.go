@@ -40,26 +40,33 @@ func (_m *mockRepository) create(ctx context.Context, user User, password string
 }
 
 // deleteByID provides a mock function with given fields: ctx, id
-func (_m *mockRepository) deleteByID(ctx context.Context, id uuid.UUID) error {
+func (_m *mockRepository) deleteByID(ctx context.Context, id uuid.UUID) (int64, error) {
 	ret := _m.Called(ctx, id)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
 		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// findByID provides a mock function with given fields: ctx, id
-func (_m *mockRepository) findByID(ctx context.Context, id uuid.UUID) (*User, error) {
-	ret := _m.Called(ctx, id)
+// findByID provides a mock function with given fields: ctx, id, fields
+func (_m *mockRepository) findByID(ctx context.Context, id uuid.UUID, fields Fields) (*User, error) {
+	ret := _m.Called(ctx, id, fields)
 
 	var r0 *User
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *User); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, Fields) *User); ok {
+		r0 = rf(ctx, id, fields)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*User)
@@ -67,8 +74,77 @@ func (_m *mockRepository) findByID(ctx context.Context, id uuid.UUID) (*User, er
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, Fields) error); ok {
+		r1 = rf(ctx, id, fields)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// findByIDs provides a mock function with given fields: ctx, ids, fields
+func (_m *mockRepository) findByIDs(ctx context.Context, ids []uuid.UUID, fields Fields) ([]User, error) {
+	ret := _m.Called(ctx, ids, fields)
+
+	var r0 []User
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID, Fields) []User); ok {
+		r0 = rf(ctx, ids, fields)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID, Fields) error); ok {
+		r1 = rf(ctx, ids, fields)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// list provides a mock function with given fields: ctx, pagination, filter, sort, fields
+func (_m *mockRepository) list(ctx context.Context, pagination common.Pagination, filter *User, sort Sort, fields Fields) ([]User, error) {
+	ret := _m.Called(ctx, pagination, filter, sort, fields)
+
+	var r0 []User
+	if rf, ok := ret.Get(0).(func(context.Context, common.Pagination, *User, Sort, Fields) []User); ok {
+		r0 = rf(ctx, pagination, filter, sort, fields)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, common.Pagination, *User, Sort, Fields) error); ok {
+		r1 = rf(ctx, pagination, filter, sort, fields)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// listAfterID provides a mock function with given fields: ctx, afterID, limit
+func (_m *mockRepository) listAfterID(ctx context.Context, afterID uuid.UUID, limit int) ([]User, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	var r0 []User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) []User); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -76,12 +152,44 @@ func (_m *mockRepository) findByID(ctx context.Context, id uuid.UUID) (*User, er
 	return r0, r1
 }
 
-// list provides a mock function with given fields: ctx, pagination, filter
-func (_m *mockRepository) list(ctx context.Context, pagination common.Pagination, filter *User) ([]User, error) {
+// replace provides a mock function with given fields: ctx, id, user, version
+func (_m *mockRepository) replace(ctx context.Context, id uuid.UUID, user User, version int64) (*User, *User, error) {
+	ret := _m.Called(ctx, id, user, version)
+
+	var r0 *User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, User, int64) *User); ok {
+		r0 = rf(ctx, id, user, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*User)
+		}
+	}
+
+	var r1 *User
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, User, int64) *User); ok {
+		r1 = rf(ctx, id, user, version)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*User)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, User, int64) error); ok {
+		r2 = rf(ctx, id, user, version)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// search provides a mock function with given fields: ctx, pagination, filter
+func (_m *mockRepository) search(ctx context.Context, pagination common.Pagination, filter Filter) ([]User, error) {
 	ret := _m.Called(ctx, pagination, filter)
 
 	var r0 []User
-	if rf, ok := ret.Get(0).(func(context.Context, common.Pagination, *User) []User); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, common.Pagination, Filter) []User); ok {
 		r0 = rf(ctx, pagination, filter)
 	} else {
 		if ret.Get(0) != nil {
@@ -90,7 +198,7 @@ func (_m *mockRepository) list(ctx context.Context, pagination common.Pagination
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, common.Pagination, *User) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, common.Pagination, Filter) error); ok {
 		r1 = rf(ctx, pagination, filter)
 	} else {
 		r1 = ret.Error(1)
@@ -100,7 +208,7 @@ func (_m *mockRepository) list(ctx context.Context, pagination common.Pagination
 }
 
 // update provides a mock function with given fields: ctx, id, user
-func (_m *mockRepository) update(ctx context.Context, id uuid.UUID, user User) (*User, error) {
+func (_m *mockRepository) update(ctx context.Context, id uuid.UUID, user User) (*User, *User, error) {
 	ret := _m.Called(ctx, id, user)
 
 	var r0 *User
@@ -112,28 +220,44 @@ func (_m *mockRepository) update(ctx context.Context, id uuid.UUID, user User) (
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, User) error); ok {
+	var r1 *User
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, User) *User); ok {
 		r1 = rf(ctx, id, user)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*User)
+		}
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, User) error); ok {
+		r2 = rf(ctx, id, user)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // updatePassword provides a mock function with given fields: ctx, id, password
-func (_m *mockRepository) updatePassword(ctx context.Context, id uuid.UUID, password string) error {
+func (_m *mockRepository) updatePassword(ctx context.Context, id uuid.UUID, password string) (int64, error) {
 	ret := _m.Called(ctx, id, password)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) int64); ok {
 		r0 = rf(ctx, id, password)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, id, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 type mockConstructorTestingTnewMockRepository interface {
@@ -0,0 +1,126 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package user
+
+import (
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockTopologyChannel is an autogenerated mock type for the topologyChannel type
+type mockTopologyChannel struct {
+	mock.Mock
+}
+
+// Close provides a mock function with given fields:
+func (_m *mockTopologyChannel) Close() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExchangeDeclare provides a mock function with given fields: name, kind, durable, autoDelete, internal, noWait, args
+func (_m *mockTopologyChannel) ExchangeDeclare(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
+	ret := _m.Called(name, kind, durable, autoDelete, internal, noWait, args)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, bool, bool, bool, bool, amqp091.Table) error); ok {
+		r0 = rf(name, kind, durable, autoDelete, internal, noWait, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExchangeDeclarePassive provides a mock function with given fields: name, kind, durable, autoDelete, internal, noWait, args
+func (_m *mockTopologyChannel) ExchangeDeclarePassive(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
+	ret := _m.Called(name, kind, durable, autoDelete, internal, noWait, args)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, bool, bool, bool, bool, amqp091.Table) error); ok {
+		r0 = rf(name, kind, durable, autoDelete, internal, noWait, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueueBind provides a mock function with given fields: name, key, exchange, noWait, args
+func (_m *mockTopologyChannel) QueueBind(name string, key string, exchange string, noWait bool, args amqp091.Table) error {
+	ret := _m.Called(name, key, exchange, noWait, args)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, bool, amqp091.Table) error); ok {
+		r0 = rf(name, key, exchange, noWait, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// QueueDeclare provides a mock function with given fields: name, durable, autoDelete, exclusive, noWait, args
+func (_m *mockTopologyChannel) QueueDeclare(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+	ret := _m.Called(name, durable, autoDelete, exclusive, noWait, args)
+
+	var r0 amqp091.Queue
+	if rf, ok := ret.Get(0).(func(string, bool, bool, bool, bool, amqp091.Table) amqp091.Queue); ok {
+		r0 = rf(name, durable, autoDelete, exclusive, noWait, args)
+	} else {
+		r0 = ret.Get(0).(amqp091.Queue)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool, bool, bool, bool, amqp091.Table) error); ok {
+		r1 = rf(name, durable, autoDelete, exclusive, noWait, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueueDeclarePassive provides a mock function with given fields: name, durable, autoDelete, exclusive, noWait, args
+func (_m *mockTopologyChannel) QueueDeclarePassive(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+	ret := _m.Called(name, durable, autoDelete, exclusive, noWait, args)
+
+	var r0 amqp091.Queue
+	if rf, ok := ret.Get(0).(func(string, bool, bool, bool, bool, amqp091.Table) amqp091.Queue); ok {
+		r0 = rf(name, durable, autoDelete, exclusive, noWait, args)
+	} else {
+		r0 = ret.Get(0).(amqp091.Queue)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool, bool, bool, bool, amqp091.Table) error); ok {
+		r1 = rf(name, durable, autoDelete, exclusive, noWait, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTnewMockTopologyChannel interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockTopologyChannel creates a new instance of mockTopologyChannel. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockTopologyChannel(t mockConstructorTestingTnewMockTopologyChannel) *mockTopologyChannel {
+	mock := &mockTopologyChannel{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,7 +2,8 @@ package user
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	"fmt"
 	"net/mail"
 	"time"
 
@@ -10,14 +11,34 @@ import (
 )
 
 var (
-	ErrUserNotFound         = errors.New("user not found")
-	ErrNilUUIDNotAllowed    = errors.New("nil UUID is not allowed")
-	ErrNewUserWithID        = errors.New("new user can't have a predefined ID")
-	ErrInvalidUserInputData = errors.New("input user data is invalid")
-	ErrInvalidPagination    = errors.New("invalid pagination")
-	ErrInvalidFilter        = errors.New("invalid filter")
+	ErrUserNotFound         = &Error{Kind: KindNotFound, Code: "user_not_found", Message: "user not found"}
+	ErrNilUUIDNotAllowed    = &Error{Kind: KindValidation, Code: "nil_id", Message: "nil UUID is not allowed"}
+	ErrNewUserWithID        = &Error{Kind: KindValidation, Code: "id_not_allowed", Message: "new user can't have a predefined ID"}
+	ErrInvalidUserInputData = &Error{Kind: KindValidation, Code: "invalid_user", Message: "input user data is invalid"}
+	ErrInvalidPagination    = &Error{Kind: KindValidation, Code: "invalid_pagination", Message: "invalid pagination"}
+	ErrInvalidFilter        = &Error{Kind: KindValidation, Code: "invalid_filter", Message: "invalid filter"}
+	ErrInvalidSort          = &Error{Kind: KindValidation, Code: "invalid_sort", Message: "invalid sort"}
+	ErrInvalidFields        = &Error{Kind: KindValidation, Code: "invalid_fields", Message: "invalid fields"}
+	ErrInvalidIDs           = &Error{Kind: KindValidation, Code: "invalid_ids", Message: "invalid ids"}
+	ErrEmailTaken           = &Error{Kind: KindConflict, Code: "email_taken", Message: "email is already taken"}
+	ErrUserModified         = &Error{Kind: KindConflict, Code: "user_modified", Message: "user was modified by another request"}
+	ErrInvalidPatch         = &Error{Kind: KindValidation, Code: "invalid_patch", Message: "invalid patch"}
+	ErrPatchTestFailed      = &Error{Kind: KindConflict, Code: "patch_test_failed", Message: "patch test failed"}
+
+	ErrIdempotencyKeyReused     = &Error{Kind: KindPrecondition, Code: "idempotency_key_reused", Message: "idempotency key was used with a different request"}
+	ErrIdempotencyKeyInProgress = &Error{Kind: KindConflict, Code: "idempotency_key_in_progress", Message: "request with the same idempotency key is in progress"}
 )
 
+var knownEventTypes = map[UserEventType]bool{
+	UserEventTypeCreated:         true,
+	UserEventTypeUpdated:         true,
+	UserEventTypePasswordChanged: true,
+	UserEventTypeDeleted:         true,
+	UserEventTypeEmailChanged:    true,
+	UserEventTypeNicknameChanged: true,
+	UserEventTypeCountryChanged:  true,
+}
+
 type User struct {
 	ID        uuid.UUID  `json:"id"`
 	FirstName string     `json:"first_name"`
@@ -27,47 +48,72 @@ type User struct {
 	Country   string     `json:"country"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
+	// Version is increased on every change of the user, it is sent on the events instead of the user data
+	Version int64 `json:"-"`
+	// omitted are the fields what were omitted by an event projection
+	omitted omittedFields
+}
+
+// MarshalJSON leaves out the fields what were omitted by an event projection, the other users are encoded as they are.
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+	data, err := json.Marshal(user(u))
+	if err != nil || u.omitted == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for i, field := range projectedFields {
+		if u.omitted.has(i) {
+			delete(fields, field)
+		}
+	}
+	return json.Marshal(fields)
 }
 
+// Validate checks every field of a new user, the returned *ValidationError lists all the invalid fields.
 func (u User) Validate() error {
-	_, emailErr := mail.ParseAddress(u.Email)
-	switch {
-	case len(u.FirstName) < 2:
-		return errors.New("first name is too short")
-	case len(u.LastName) < 2:
-		return errors.New("last name is too short")
-	case len(u.Nickname) < 3:
-		return errors.New("nickname is too short")
-	case len(u.Country) != 2:
-		return errors.New("country code must be 2 letters")
-	case emailErr != nil:
-		return errors.New("invalid email address")
+	var v ValidationError
+	if len(u.FirstName) < 2 {
+		v.add("first_name", RuleMinLength, "first name is too short")
 	}
-	return nil
+	if len(u.LastName) < 2 {
+		v.add("last_name", RuleMinLength, "last name is too short")
+	}
+	if len(u.Nickname) < 3 {
+		v.add("nickname", RuleMinLength, "nickname is too short")
+	}
+	if len(u.Country) != 2 {
+		v.add("country", RuleLength, "country code must be 2 letters")
+	}
+	if _, err := mail.ParseAddress(u.Email); err != nil {
+		v.add("email", RuleEmail, "invalid email address")
+	}
+	return v.errOrNil()
 }
 
+// ValidateIfNotEmpty checks the set fields of a user change, the returned *ValidationError lists all the invalid fields.
 func (u User) ValidateIfNotEmpty() error {
+	var v ValidationError
 	if u.FirstName != "" && len(u.FirstName) < 2 {
-		return errors.New("first_name must be at least 2 characters")
+		v.add("first_name", RuleMinLength, "first_name must be at least 2 characters")
 	}
-
 	if u.LastName != "" && len(u.LastName) < 2 {
-		return errors.New("last_name must be at least 2 characters")
+		v.add("last_name", RuleMinLength, "last_name must be at least 2 characters")
 	}
-
 	if u.Nickname != "" && len(u.Nickname) < 2 {
-		return errors.New("nickname must be at least 2 characters")
+		v.add("nickname", RuleMinLength, "nickname must be at least 2 characters")
 	}
-
 	if u.Email != "" && len(u.Email) < 2 {
-		return errors.New("email must be at least 2 characters")
+		v.add("email", RuleMinLength, "email must be at least 2 characters")
 	}
-
 	if u.Country != "" && len(u.Country) != 2 {
-		return errors.New("country must have exactly 2 characters")
+		v.add("country", RuleLength, "country must have exactly 2 characters")
 	}
-
-	return nil
+	return v.errOrNil()
 }
 
 type UserEventType string
@@ -77,12 +123,59 @@ var (
 	UserEventTypeUpdated         UserEventType = "USER_UPDATED"
 	UserEventTypePasswordChanged UserEventType = "USER_PASSWORD_CHANGED"
 	UserEventTypeDeleted         UserEventType = "USER_DELETED"
+	// UserEventTypeEmailChanged, UserEventTypeNicknameChanged and UserEventTypeCountryChanged are published
+	// besides UserEventTypeUpdated when the specific field was changed
+	UserEventTypeEmailChanged    UserEventType = "USER_EMAIL_CHANGED"
+	UserEventTypeNicknameChanged UserEventType = "USER_NICKNAME_CHANGED"
+	UserEventTypeCountryChanged  UserEventType = "USER_COUNTRY_CHANGED"
 )
 
+// UserEvent is a change of a user. EventID is unique for every event and Version is the version of the user after the change,
+// so the consumers could detect the duplicated and the out of order events.
 type UserEvent struct {
 	context     *context.Context `json:"-"`
+	EventID     uuid.UUID        `json:"event_id"`
 	Type        UserEventType    `json:"type"`
 	UserID      uuid.UUID        `json:"user_id"`
-	UserChanges *User            `json:"user_changes,omitempty"`
+	Version     int64            `json:"version"`
+	UserChanges *User            `json:"user_changes,omitempty" gorm:"serializer:json"`
+	Change      *FieldChange     `json:"change,omitempty" gorm:"serializer:json"`
 	Time        time.Time        `json:"time"`
 }
+
+// FieldChange is the old and the new value of the changed field of the specific change events
+type FieldChange struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// fieldChange is a specific change event type with the changed values
+type fieldChange struct {
+	eventType UserEventType
+	FieldChange
+}
+
+// fieldChanges returns the specific change events of the changed email, nickname and country.
+func fieldChanges(previous User, updated User) []fieldChange {
+	var changes []fieldChange
+	if previous.Email != updated.Email {
+		changes = append(changes, fieldChange{UserEventTypeEmailChanged, FieldChange{Old: previous.Email, New: updated.Email}})
+	}
+	if previous.Nickname != updated.Nickname {
+		changes = append(changes, fieldChange{UserEventTypeNicknameChanged, FieldChange{Old: previous.Nickname, New: updated.Nickname}})
+	}
+	if previous.Country != updated.Country {
+		changes = append(changes, fieldChange{UserEventTypeCountryChanged, FieldChange{Old: previous.Country, New: updated.Country}})
+	}
+	return changes
+}
+
+// ValidateEventTypes returns an error on the first unknown event type, i.e. of an event filter or a webhook subscription.
+func ValidateEventTypes(eventTypes []UserEventType) error {
+	for _, t := range eventTypes {
+		if !knownEventTypes[t] {
+			return fmt.Errorf("unknown event type %s", t)
+		}
+	}
+	return nil
+}
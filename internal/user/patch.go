@@ -0,0 +1,93 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/google/uuid"
+)
+
+// PatchType is the media type of a user patch
+type PatchType string
+
+const (
+	// PatchTypeMerge is a JSON Merge Patch (RFC 7396), the fields set to null are cleared
+	PatchTypeMerge PatchType = "application/merge-patch+json"
+	// PatchTypeJSON is a JSON Patch (RFC 6902), its `test` operations make the update conditional
+	PatchTypeJSON PatchType = "application/json-patch+json"
+)
+
+// patchDocument is the JSON document of the user what the patches are applied to.
+// The id and the version could be tested, but they can't be changed, the password could only be set.
+type patchDocument struct {
+	ID        uuid.UUID `json:"id"`
+	Version   int64     `json:"version"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Nickname  string    `json:"nickname"`
+	Email     string    `json:"email"`
+	Country   string    `json:"country"`
+	Password  *string   `json:"password,omitempty"`
+}
+
+// applyPatch applies the patch to the user and returns the patched user with the new password, what is empty
+// when the patch doesn't set it. The patched user isn't validated.
+func applyPatch(u User, patchType PatchType, patch []byte) (User, string, error) {
+	doc, err := json.Marshal(patchDocument{
+		ID:        u.ID,
+		Version:   u.Version,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Nickname:  u.Nickname,
+		Email:     u.Email,
+		Country:   u.Country,
+	})
+	if err != nil {
+		return User{}, "", err
+	}
+
+	var patched []byte
+	switch patchType {
+	case PatchTypeMerge:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case PatchTypeJSON:
+		var p jsonpatch.Patch
+		if p, err = jsonpatch.DecodePatch(patch); err == nil {
+			patched, err = p.Apply(doc)
+		}
+	default:
+		return User{}, "", fmt.Errorf("%w: unsupported patch type %s", ErrInvalidPatch, patchType)
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return User{}, "", fmt.Errorf("%w: %s", ErrPatchTestFailed, err.Error())
+	}
+	if err != nil {
+		return User{}, "", fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+
+	var res patchDocument
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&res); err != nil {
+		return User{}, "", fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+	if res.ID != u.ID || res.Version != u.Version {
+		return User{}, "", fmt.Errorf("%w: id and version can't be changed", ErrInvalidPatch)
+	}
+
+	patchedUser := User{
+		FirstName: res.FirstName,
+		LastName:  res.LastName,
+		Nickname:  res.Nickname,
+		Email:     res.Email,
+		Country:   res.Country,
+	}
+	password := ""
+	if res.Password != nil {
+		password = *res.Password
+	}
+	return patchedUser, password, nil
+}
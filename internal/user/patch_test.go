@@ -0,0 +1,71 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	patchTestSuite struct {
+		current User
+		suite.Suite
+	}
+)
+
+func TestPatchTestSuite(t *testing.T) {
+	suite.Run(t, new(patchTestSuite))
+}
+
+func (s *patchTestSuite) SetupTest() {
+	s.current = validUser
+	s.current.ID = uuid.New()
+	s.current.Version = 2
+}
+
+func (s *patchTestSuite) TestApplyPatch_Merge() {
+	patched, password, err := applyPatch(s.current, PatchTypeMerge, []byte(`{"nickname": "johnny", "country": null}`))
+
+	s.NoError(err)
+	s.Empty(password)
+	s.Equal("johnny", patched.Nickname)
+	s.Empty(patched.Country)
+	s.Equal(validUser.Email, patched.Email)
+}
+
+func (s *patchTestSuite) TestApplyPatch_JSON() {
+	patched, password, err := applyPatch(s.current, PatchTypeJSON, []byte(`[
+		{"op": "test", "path": "/version", "value": 2},
+		{"op": "replace", "path": "/email", "value": "johnny@email.com"},
+		{"op": "add", "path": "/password", "value": "testpwd"}
+	]`))
+
+	s.NoError(err)
+	s.Equal(testpwd, password)
+	s.Equal("johnny@email.com", patched.Email)
+	s.Equal(validUser.Nickname, patched.Nickname)
+}
+
+func (s *patchTestSuite) TestApplyPatch_ReturnsError() {
+	for _, test := range []struct {
+		name        string
+		patchType   PatchType
+		patch       string
+		expectedErr error
+	}{
+		{"failed test", PatchTypeJSON, `[{"op": "test", "path": "/version", "value": 1}]`, ErrPatchTestFailed},
+		{"invalid json patch", PatchTypeJSON, `{"nickname": "johnny"}`, ErrInvalidPatch},
+		{"invalid merge patch", PatchTypeMerge, `{"nickname": `, ErrInvalidPatch},
+		{"unknown field", PatchTypeMerge, `{"role": "admin"}`, ErrInvalidPatch},
+		{"changed id", PatchTypeJSON, `[{"op": "replace", "path": "/id", "value": "` + uuid.NewString() + `"}]`, ErrInvalidPatch},
+		{"changed version", PatchTypeMerge, `{"version": 3}`, ErrInvalidPatch},
+		{"invalid type", PatchTypeMerge, `{"nickname": 1}`, ErrInvalidPatch},
+		{"unsupported type", "application/json", `{}`, ErrInvalidPatch},
+	} {
+		s.Run(test.name, func() {
+			_, _, err := applyPatch(s.current, test.patchType, []byte(test.patch))
+			s.ErrorIs(err, test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"faceit/internal/common"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	replayBatchSize = 500
+
+	// HeaderReplay marks the re-emitted stored events in the AMQP message headers
+	HeaderReplay = "x-replay"
+	// HeaderSnapshot marks the synthetic snapshot events in the AMQP message headers
+	HeaderSnapshot = "x-snapshot"
+)
+
+var ErrInvalidReplayRequest = &Error{Kind: KindValidation, Code: "invalid_replay_request", Message: "invalid replay request"}
+
+type (
+	// ReplayTarget defines where the replayed events are published.
+	// The default user event exchange and the `#` routing key are used when they are empty.
+	ReplayTarget struct {
+		Exchange   string
+		RoutingKey string
+	}
+
+	// ReplayRequest selects the stored events to replay.
+	// Every stored event is replayed when the filter is empty.
+	ReplayRequest struct {
+		ReplayTarget
+		EventFilter
+	}
+
+	replaySender interface {
+		publishTo(ctx context.Context, target ReplayTarget, event UserEvent, headers amqp.Table) error
+		checkExchange(exchange string) error
+		close(ctx context.Context) error
+	}
+
+	// Replayer re-emits the stored user events and user snapshots, so the consumers could rebuild their state
+	// i.e. when a new consumer comes online or a queue was lost.
+	Replayer struct {
+		repository repository
+		eventStore eventStore
+		sender     replaySender
+	}
+)
+
+// NewReplayer creates a new Replayer with it's own DB and RabbitMQ connection, i.e. for the replay command.
+// The service shares its own connections with the replayer of Service.Replayer.
+func NewReplayer() (*Replayer, error) {
+	r, err := NewRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	rmq, err := NewEventPublisher()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		repository: r,
+		eventStore: newEventStore(r.db),
+		sender:     rmq,
+	}, nil
+}
+
+// Replay publishes the stored events matching the request in their original order and returns the number of replayed events.
+// The events keep their original correlation id (if it was set) and they are marked with the HeaderReplay header.
+func (r Replayer) Replay(ctx context.Context, req ReplayRequest) (int, error) {
+	if err := req.Validate(); err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidReplayRequest, err.Error())
+	}
+	if err := r.checkTarget(req.ReplayTarget); err != nil {
+		return 0, err
+	}
+
+	headers := amqp.Table{HeaderReplay: true}
+	var after int64
+	replayed := 0
+	for {
+		events, err := r.eventStore.listAfter(ctx, after, req.EventFilter, replayBatchSize)
+		if err != nil {
+			return replayed, err
+		}
+
+		for _, e := range events {
+			c := ctx
+			if e.CorrelationID != "" {
+				c = context.WithValue(ctx, common.CorrelationID, e.CorrelationID)
+			}
+			if err := r.sender.publishTo(c, req.ReplayTarget, e.UserEvent, headers); err != nil {
+				return replayed, err
+			}
+			after = e.ID
+			replayed++
+		}
+
+		if len(events) < replayBatchSize {
+			return replayed, nil
+		}
+	}
+}
+
+// Snapshot publishes a synthetic UserEventTypeCreated event with the current state of every existing user
+// and returns the number of published events. The events are marked with the HeaderSnapshot header.
+func (r Replayer) Snapshot(ctx context.Context, target ReplayTarget) (int, error) {
+	if err := r.checkTarget(target); err != nil {
+		return 0, err
+	}
+
+	headers := amqp.Table{HeaderSnapshot: true}
+	after := uuid.Nil
+	published := 0
+	for {
+		users, err := r.repository.listAfterID(ctx, after, replayBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		for i := range users {
+			u := users[i]
+			if err := r.sender.publishTo(ctx, target, newUserEvent(UserEventTypeCreated, u.ID, u.Version, &u), headers); err != nil {
+				return published, err
+			}
+			after = u.ID
+			published++
+		}
+
+		if len(users) < replayBatchSize {
+			return published, nil
+		}
+	}
+}
+
+// Close releases the RabbitMQ connection of the replayers created by NewReplayer.
+// The replayer of Service.Replayer is closed by Service.Close.
+func (r Replayer) Close(ctx context.Context) error {
+	return r.sender.close(ctx)
+}
+
+func (r Replayer) checkTarget(target ReplayTarget) error {
+	if target.Exchange == "" {
+		return nil
+	}
+	return r.sender.checkExchange(target.Exchange)
+}
+
+func (r ReplayRequest) Validate() error {
+	if r.Since.After(time.Now()) {
+		return errors.New("since can't be in the future")
+	}
+	return r.EventFilter.Validate()
+}
@@ -0,0 +1,145 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"faceit/internal/common"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	listAfterID   = "listAfterID"
+	publishTo     = "publishTo"
+	checkExchange = "checkExchange"
+)
+
+type (
+	replayerTestSuite struct {
+		repoMock   *mockRepository
+		storeMock  *mockEventStore
+		senderMock *mockReplaySender
+		replayer   Replayer
+		suite.Suite
+	}
+)
+
+func TestReplayerTestSuite(t *testing.T) {
+	suite.Run(t, new(replayerTestSuite))
+}
+
+func (s *replayerTestSuite) SetupTest() {
+	s.repoMock = newMockRepository(s.T())
+	s.storeMock = newMockEventStore(s.T())
+	s.senderMock = newMockReplaySender(s.T())
+	s.replayer = Replayer{
+		repository: s.repoMock,
+		eventStore: s.storeMock,
+		sender:     s.senderMock,
+	}
+}
+
+func (s *replayerTestSuite) TestReplay() {
+	userID := uuid.New()
+	req := ReplayRequest{
+		ReplayTarget: ReplayTarget{RoutingKey: "new-consumer"},
+		EventFilter:  EventFilter{UserIDs: []uuid.UUID{userID}, Since: time.Now().Add(-time.Hour)},
+	}
+	events := []StoredEvent{
+		{ID: 3, CorrelationID: "first", UserEvent: UserEvent{Type: UserEventTypeCreated, UserID: userID}},
+		{ID: 7, CorrelationID: "second", UserEvent: UserEvent{Type: UserEventTypeDeleted, UserID: userID}},
+	}
+	s.storeMock.On(listAfter, mock.Anything, int64(0), req.EventFilter, replayBatchSize).Return(events, nil).Once()
+	for _, e := range events {
+		correlationID := e.CorrelationID
+		s.senderMock.
+			On(publishTo, mock.MatchedBy(func(ctx context.Context) bool {
+				return common.GetCorrelationID(ctx) == correlationID
+			}), req.ReplayTarget, e.UserEvent, amqp.Table{HeaderReplay: true}).
+			Return(nil).
+			Once()
+	}
+
+	replayed, err := s.replayer.Replay(context.TODO(), req)
+	s.NoError(err)
+	s.Equal(2, replayed)
+	s.senderMock.AssertNotCalled(s.T(), checkExchange, mock.Anything)
+}
+
+func (s *replayerTestSuite) TestReplay_ReturnsError() {
+	for _, test := range []struct {
+		name          string
+		req           ReplayRequest
+		prepareMock   func()
+		expectedError error
+	}{
+		{
+			name:          "future since",
+			req:           ReplayRequest{EventFilter: EventFilter{Since: time.Now().Add(time.Hour)}},
+			prepareMock:   func() {},
+			expectedError: ErrInvalidReplayRequest,
+		},
+		{
+			name:          "unknown event type",
+			req:           ReplayRequest{EventFilter: EventFilter{Types: []UserEventType{"USER_LOGGED_IN"}}},
+			prepareMock:   func() {},
+			expectedError: ErrInvalidReplayRequest,
+		},
+		{
+			name: "missing exchange",
+			req:  ReplayRequest{ReplayTarget: ReplayTarget{Exchange: "missing"}},
+			prepareMock: func() {
+				s.senderMock.On(checkExchange, "missing").Return(ErrInvalidReplayRequest).Once()
+			},
+			expectedError: ErrInvalidReplayRequest,
+		},
+	} {
+		s.Run(test.name, func() {
+			test.prepareMock()
+
+			_, err := s.replayer.Replay(context.TODO(), test.req)
+			s.ErrorIs(err, test.expectedError)
+			s.storeMock.AssertNotCalled(s.T(), listAfter, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func (s *replayerTestSuite) TestReplay_StopsOnPublishError() {
+	publishErr := errors.New("channel closed")
+	s.storeMock.
+		On(listAfter, mock.Anything, int64(0), EventFilter{}, replayBatchSize).
+		Return([]StoredEvent{{ID: 1}, {ID: 2}}, nil).
+		Once()
+	s.senderMock.On(publishTo, mock.Anything, ReplayTarget{}, mock.Anything, mock.Anything).Return(publishErr).Once()
+
+	replayed, err := s.replayer.Replay(context.TODO(), ReplayRequest{})
+	s.ErrorIs(err, publishErr)
+	s.Zero(replayed)
+}
+
+func (s *replayerTestSuite) TestSnapshot() {
+	target := ReplayTarget{Exchange: "rebuild"}
+	users := make([]User, replayBatchSize)
+	for i := range users {
+		users[i] = User{ID: uuid.New()}
+	}
+	last := User{ID: uuid.New(), Nickname: "johndoe"}
+	s.senderMock.On(checkExchange, "rebuild").Return(nil).Once()
+	s.repoMock.On(listAfterID, mock.Anything, uuid.Nil, replayBatchSize).Return(users, nil).Once()
+	s.repoMock.On(listAfterID, mock.Anything, users[replayBatchSize-1].ID, replayBatchSize).Return([]User{last}, nil).Once()
+	s.senderMock.
+		On(publishTo, mock.Anything, target, mock.MatchedBy(func(e UserEvent) bool {
+			return e.Type == UserEventTypeCreated && e.UserChanges.ID == e.UserID
+		}), amqp.Table{HeaderSnapshot: true}).
+		Return(nil).
+		Times(replayBatchSize + 1)
+
+	published, err := s.replayer.Snapshot(context.TODO(), target)
+	s.NoError(err)
+	s.Equal(replayBatchSize+1, published)
+}
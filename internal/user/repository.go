@@ -9,25 +9,22 @@ import (
 
 	"github.com/google/uuid"
 
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
-type gormRepository struct {
-	db *gorm.DB
-}
+type (
+	gormRepository struct {
+		db *gorm.DB
+	}
+
+	// transactionKey is the context key of the transaction what the queries should run in
+	transactionKey struct{}
+)
 
 // NewRepository creates a new DB connection
 func NewRepository() (*gormRepository, error) {
-	pgHost := common.GetEnv("PG_HOST", "localhost")
-	pgPort := common.GetEnv("PG_PORT", "5432")
-	pgUser := common.GetEnv("PG_USER", "admin")
-	pgPass := common.GetEnv("PG_PASSWORD", "pass")
-	pgDb := common.GetEnv("PG_DATABASE", "users")
-
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s", pgHost, pgPort, pgUser, pgPass, pgDb)
-	db, err := gorm.Open(postgres.Open(dsn))
+	db, err := common.OpenPostgres()
 	if err != nil {
 		return nil, err
 	}
@@ -40,17 +37,52 @@ func (r gormRepository) GetDB() *gorm.DB {
 	return r.db
 }
 
-func (r gormRepository) findByID(ctx context.Context, id uuid.UUID) (*User, error) {
+// withTransaction returns a context what carries the transaction to the queries of the stores
+func withTransaction(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, transactionKey{}, tx)
+}
+
+// conn returns the transaction of the context, or the db with the context when the context has no transaction
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db.WithContext(ctx)
+}
+
+func (r gormRepository) findByID(ctx context.Context, id uuid.UUID, fields Fields) (*User, error) {
+	query := conn(ctx, r.db)
+	if columns := fields.columns(); columns != nil {
+		query = query.Select(columns)
+	}
+
 	var u *User
-	if err := r.db.Take(&u, id).Error; err != nil {
+	if err := query.Take(&u, id).Error; err != nil {
 		return nil, handleNotFoundError(err)
 	}
 
 	return u, nil
 }
 
-func (r gormRepository) list(ctx context.Context, pagination common.Pagination, filter *User) ([]User, error) {
-	query := r.db
+// findByIDs returns the found users of the ids in a single query, in no particular order
+func (r gormRepository) findByIDs(ctx context.Context, ids []uuid.UUID, fields Fields) ([]User, error) {
+	query := conn(ctx, r.db)
+	if columns := fields.columns(); columns != nil {
+		query = query.Select(columns)
+	}
+
+	var users []User
+	if err := query.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r gormRepository) list(ctx context.Context, pagination common.Pagination, filter *User, sort Sort, fields Fields) ([]User, error) {
+	query := conn(ctx, r.db)
+	if columns := fields.columns(); columns != nil {
+		query = query.Select(columns)
+	}
 
 	if filter != nil {
 		if filter.FirstName != "" {
@@ -73,22 +105,102 @@ func (r gormRepository) list(ctx context.Context, pagination common.Pagination,
 	query = query.Offset(pagination.GetOffset()).Limit(pagination.GetLimit())
 
 	var users []User
-	if err := query.Order("created_at desc").Order("email asc").Find(&users).Error; err != nil {
+	if err := query.Clauses(sort.orderBy()).Find(&users).Error; err != nil {
 		return nil, err
 	}
 
 	return users, nil
 }
 
+// search returns the users what match the validated filter, the filter is compiled to parameterized conditions
+// of the whitelisted columns.
+func (r gormRepository) search(ctx context.Context, pagination common.Pagination, filter Filter) ([]User, error) {
+	expr, err := filterExpression(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFilter, err.Error())
+	}
+
+	var users []User
+	err = conn(ctx, r.db).
+		Where(expr).
+		Offset(pagination.GetOffset()).
+		Limit(pagination.GetLimit()).
+		Clauses(defaultSort.orderBy()).
+		Find(&users).
+		Error
+	return users, err
+}
+
+func filterExpression(f Filter) (clause.Expression, error) {
+	switch {
+	case f.And != nil:
+		exprs, err := filterExpressions(f.And)
+		return clause.And(exprs...), err
+	case f.Or != nil:
+		exprs, err := filterExpressions(f.Or)
+		return clause.Or(exprs...), err
+	case f.Not != nil:
+		expr, err := filterExpression(*f.Not)
+		return clause.Not(expr), err
+	}
+
+	field, value, err := f.condition()
+	if err != nil {
+		return nil, err
+	}
+	column := clause.Column{Table: clause.CurrentTable, Name: field.column}
+
+	switch f.Op {
+	case FilterPrefix:
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []interface{}{column, escapeLike(value.(string)) + "%"}}, nil
+	case FilterContains:
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []interface{}{column, "%" + escapeLike(value.(string)) + "%"}}, nil
+	case FilterIn:
+		return clause.IN{Column: column, Values: value.([]interface{})}, nil
+	case FilterRange:
+		r := value.(TimeRange)
+		var exprs []clause.Expression
+		if r.From != nil {
+			exprs = append(exprs, clause.Gte{Column: column, Value: *r.From})
+		}
+		if r.To != nil {
+			exprs = append(exprs, clause.Lt{Column: column, Value: *r.To})
+		}
+		return clause.And(exprs...), nil
+	}
+	return clause.Eq{Column: column, Value: value}, nil
+}
+
+func filterExpressions(filters []Filter) ([]clause.Expression, error) {
+	exprs := make([]clause.Expression, 0, len(filters))
+	for _, f := range filters {
+		expr, err := filterExpression(f)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// escapeLike escapes the wildcards of the LIKE patterns, so they are matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func (r gormRepository) create(ctx context.Context, user User, password string) (*User, error) {
-	err := r.db.Transaction(func(tx *gorm.DB) error {
+	user.Version = 1
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(&user).Error; err != nil {
-			return nil
+			return err
 		}
 		return updatePassword(tx, ctx, user.ID, password)
 	})
+	if err != nil {
+		return nil, handleConflictError(err)
+	}
 
-	return &user, err
+	return &user, nil
 }
 
 func updatePassword(tx *gorm.DB, ctx context.Context, id uuid.UUID, password string) error {
@@ -99,22 +211,125 @@ func updatePassword(tx *gorm.DB, ctx context.Context, id uuid.UUID, password str
 	return handleNotFoundError(err)
 }
 
-func (r gormRepository) updatePassword(ctx context.Context, id uuid.UUID, password string) error {
-	return updatePassword(r.db, ctx, id, password)
+// updatePassword changes the password and returns the new version of the user.
+func (r gormRepository) updatePassword(ctx context.Context, id uuid.UUID, password string) (int64, error) {
+	var u User
+	result := conn(ctx, r.db).Model(&u).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "version"}}}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"password": password, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, ErrUserNotFound
+	}
+	return u.Version, nil
+}
+
+// update saves the set fields of the user and increases its version.
+// The user before the update is returned as well, so the changed fields could be published.
+// ErrUserNotFound is returned when the user doesn't exist.
+func (r gormRepository) update(ctx context.Context, id uuid.UUID, user User) (*User, *User, error) {
+	var updatedUser, previousUser User
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			Take(&previousUser).
+			Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(User{}).
+			Where("id = ?", id).
+			UpdateColumn("version", gorm.Expr("version + 1")).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&updatedUser).
+			Clauses(clause.Returning{}).
+			Where("id = ?", id).
+			Updates(user).
+			Error
+	})
+	if err != nil {
+		return nil, nil, handleConflictError(handleNotFoundError(err))
+	}
+	return &updatedUser, &previousUser, nil
 }
 
-func (r gormRepository) update(ctx context.Context, id uuid.UUID, user User) (*User, error) {
-	var updatedUser User
-	err := r.db.Model(&updatedUser).
-		Clauses(clause.Returning{}).
+// replace saves every field of the user and increases its version. The user is only saved when its version is
+// still the expected version, otherwise ErrUserModified is returned. Any version is accepted when it's 0.
+// The user before the update is returned as well, so the changed fields could be published. The user isn't saved
+// when none of its fields is changed, then the previous user is returned as the updated user with the same version.
+func (r gormRepository) replace(ctx context.Context, id uuid.UUID, user User, version int64) (*User, *User, error) {
+	var updatedUser, previousUser User
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			Take(&previousUser).
+			Error
+		if err != nil {
+			return err
+		}
+		if version != 0 && previousUser.Version != version {
+			return ErrUserModified
+		}
+		if previousUser.FirstName == user.FirstName && previousUser.LastName == user.LastName &&
+			previousUser.Nickname == user.Nickname && previousUser.Email == user.Email && previousUser.Country == user.Country {
+			// nothing is changed, so the version is kept
+			updatedUser = previousUser
+			return nil
+		}
+
+		return tx.Model(&updatedUser).
+			Clauses(clause.Returning{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"first_name": user.FirstName,
+				"last_name":  user.LastName,
+				"nickname":   user.Nickname,
+				"email":      user.Email,
+				"country":    user.Country,
+				"version":    gorm.Expr("version + 1"),
+			}).
+			Error
+	})
+	if err != nil {
+		return nil, nil, handleConflictError(handleNotFoundError(err))
+	}
+	return &updatedUser, &previousUser, nil
+}
+
+// deleteByID deletes the user and returns the version of the deletion, what is the next version of the user.
+func (r gormRepository) deleteByID(ctx context.Context, id uuid.UUID) (int64, error) {
+	var u User
+	result := conn(ctx, r.db).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "version"}}}).
 		Where("id = ?", id).
-		Updates(user).
-		Error
-	return &updatedUser, handleNotFoundError(err)
+		Delete(&u)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, ErrUserNotFound
+	}
+	return u.Version + 1, nil
 }
 
-func (r gormRepository) deleteByID(ctx context.Context, id uuid.UUID) error {
-	return r.db.Delete(&User{}, id).Error
+// listAfterID returns the users with greater id than afterID ordered by id, so every user could be iterated in batches.
+func (r gormRepository) listAfterID(ctx context.Context, afterID uuid.UUID, limit int) ([]User, error) {
+	var users []User
+	err := conn(ctx, r.db).
+		Where("id > ?", afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&users).
+		Error
+	return users, err
 }
 
 func handleNotFoundError(err error) error {
@@ -123,3 +338,11 @@ func handleNotFoundError(err error) error {
 	}
 	return err
 }
+
+// handleConflictError returns ErrEmailTaken when the unique email of the users is violated
+func handleConflictError(err error) error {
+	if isUniqueViolation(err) {
+		return ErrEmailTaken
+	}
+	return err
+}
@@ -4,6 +4,7 @@
 package user
 
 import (
+	"context"
 	"faceit/internal/common"
 	"os"
 	"testing"
@@ -31,10 +32,10 @@ func (s *repositoryTestSuite) SetupSuite() {
 	s.repo = *r
 }
 
-func (s repositoryTestSuite) TestFindByID() {
+func (s *repositoryTestSuite) TestFindByID() {
 	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
 
-	actualUser, err := s.repo.findByID(nil, id)
+	actualUser, err := s.repo.findByID(context.TODO(), id, nil)
 
 	s.NoError(err)
 	s.Equal("John", actualUser.FirstName)
@@ -44,31 +45,79 @@ func (s repositoryTestSuite) TestFindByID() {
 	s.Equal("US", actualUser.Country)
 }
 
-func (s repositoryTestSuite) TestFindByID_ReturnsNotFound() {
-	_, err := s.repo.findByID(nil, uuid.Nil)
+func (s *repositoryTestSuite) TestFindByID_SelectsFields() {
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+	actualUser, err := s.repo.findByID(context.TODO(), id, Fields{"nickname"})
+
+	s.NoError(err)
+	s.Equal(id, actualUser.ID)
+	s.Equal("johndoe", actualUser.Nickname)
+	s.Empty(actualUser.FirstName)
+	s.Empty(actualUser.Email)
+	s.True(actualUser.CreatedAt.IsZero())
+}
+
+func (s *repositoryTestSuite) TestFindByIDs() {
+	john := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	jane := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	users, err := s.repo.findByIDs(context.TODO(), []uuid.UUID{jane, uuid.New(), john}, Fields{"nickname"})
+
+	s.NoError(err)
+	nicknames := map[uuid.UUID]string{}
+	for _, u := range users {
+		nicknames[u.ID] = u.Nickname
+		s.Empty(u.Email)
+	}
+	s.Equal(map[uuid.UUID]string{john: "johndoe", jane: "janedoe"}, nicknames)
+}
+
+func (s *repositoryTestSuite) TestFindByID_ReturnsNotFound() {
+	_, err := s.repo.findByID(context.TODO(), uuid.Nil, nil)
 	s.ErrorIs(err, ErrUserNotFound)
 }
 
-func (s repositoryTestSuite) TestListPagination() {
+func (s *repositoryTestSuite) TestQueries_ReturnTimeoutOnExpiredContext() {
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	expired, cancel := context.WithTimeout(context.TODO(), -time.Second)
+	defer cancel()
+
+	for name, query := range map[string]func() error{
+		"findByID":       func() error { _, err := s.repo.findByID(expired, id, nil); return err },
+		"list":           func() error { _, err := s.repo.list(expired, common.Pagination{}, nil, nil, nil); return err },
+		"create":         func() error { _, err := s.repo.create(expired, User{ID: uuid.New()}, "pwd"); return err },
+		"update":         func() error { _, _, err := s.repo.update(expired, id, User{Nickname: "johnny"}); return err },
+		"replace":        func() error { _, _, err := s.repo.replace(expired, id, User{Nickname: "johnny"}, 0); return err },
+		"updatePassword": func() error { _, err := s.repo.updatePassword(expired, id, "pwd"); return err },
+		"deleteByID":     func() error { _, err := s.repo.deleteByID(expired, id); return err },
+	} {
+		s.Run(name, func() {
+			s.Equal(KindTimeout, AsError(query()).Kind)
+		})
+	}
+}
+
+func (s *repositoryTestSuite) TestListPagination() {
 	s.reinitDB()
 
-	res, err := s.repo.list(nil, common.Pagination{Page: 0, PageSize: 2}, nil)
+	res, err := s.repo.list(context.TODO(), common.Pagination{Page: 0, PageSize: 2}, nil, nil, nil)
 	s.NoError(err)
 	s.Len(res, 2)
 	s.Equal("dome@email.com", res[0].Email)
 	s.Equal("janedoe@email.com", res[1].Email)
 
-	res, err = s.repo.list(nil, common.Pagination{Page: 2, PageSize: 1}, nil)
+	res, err = s.repo.list(context.TODO(), common.Pagination{Page: 2, PageSize: 1}, nil, nil, nil)
 	s.NoError(err)
 	s.Len(res, 1)
 	s.Equal("johndoe@email.com", res[0].Email)
 
-	res, err = s.repo.list(nil, common.Pagination{}, nil)
+	res, err = s.repo.list(context.TODO(), common.Pagination{}, nil, nil, nil)
 	s.NoError(err)
 	s.Len(res, 3)
 }
 
-func (s repositoryTestSuite) TestListFilter() {
+func (s *repositoryTestSuite) TestListFilter() {
 	s.reinitDB()
 	p := common.Pagination{Page: 0, PageSize: 0}
 
@@ -114,7 +163,7 @@ func (s repositoryTestSuite) TestListFilter() {
 		},
 	} {
 		s.Run(test.name, func() {
-			res, err := s.repo.list(nil, p, &test.filter)
+			res, err := s.repo.list(context.TODO(), p, &test.filter, nil, nil)
 			s.NoError(err)
 			s.Len(res, len(test.expectedEmails))
 
@@ -127,7 +176,118 @@ func (s repositoryTestSuite) TestListFilter() {
 	}
 }
 
-func (s repositoryTestSuite) TestDeleteByID() {
+func (s *repositoryTestSuite) TestSearch() {
+	s.reinitDB()
+	p := common.Pagination{Page: 0, PageSize: 0}
+
+	for _, test := range []struct {
+		name           string
+		filter         Filter
+		expectedEmails []string
+	}{
+		{
+			name:           "eq",
+			filter:         Filter{Field: "nickname", Op: FilterEq, Value: "dome"},
+			expectedEmails: []string{"dome@email.com"},
+		},
+		{
+			name:           "case insensitive prefix",
+			filter:         Filter{Field: "first_name", Op: FilterPrefix, Value: "JA"},
+			expectedEmails: []string{"janedoe@email.com"},
+		},
+		{
+			name:           "wildcard is not a pattern",
+			filter:         Filter{Field: "email", Op: FilterContains, Value: "%"},
+			expectedEmails: []string{},
+		},
+		{
+			name: "or and not",
+			filter: Filter{Or: []Filter{
+				{Field: "country", Op: FilterIn, Value: []interface{}{"us"}},
+				{And: []Filter{
+					{Field: "last_name", Op: FilterContains, Value: "doe"},
+					{Not: &Filter{Field: "first_name", Op: FilterEq, Value: "John"}},
+				}},
+			}},
+			expectedEmails: []string{"janedoe@email.com", "johndoe@email.com"},
+		},
+		{
+			name:           "range",
+			filter:         Filter{Field: "created_at", Op: FilterRange, Value: map[string]interface{}{"to": "2000-01-01T00:00:00Z"}},
+			expectedEmails: []string{},
+		},
+	} {
+		s.Run(test.name, func() {
+			res, err := s.repo.search(context.TODO(), p, test.filter)
+			s.NoError(err)
+
+			actualEmails := []string{}
+			for _, r := range res {
+				actualEmails = append(actualEmails, r.Email)
+			}
+			s.Equal(test.expectedEmails, actualEmails)
+		})
+	}
+}
+
+func (s *repositoryTestSuite) TestListFields() {
+	s.reinitDB()
+
+	res, err := s.repo.list(context.TODO(), common.Pagination{}, nil, nil, Fields{"nickname", "country"})
+	s.NoError(err)
+	s.Len(res, 3)
+	for _, u := range res {
+		s.NotEqual(uuid.Nil, u.ID)
+		s.NotEmpty(u.Nickname)
+		s.NotEmpty(u.Country)
+		s.Empty(u.Email)
+		s.Empty(u.LastName)
+	}
+}
+
+func (s *repositoryTestSuite) TestListSort() {
+	s.reinitDB()
+	p := common.Pagination{Page: 0, PageSize: 0}
+
+	for _, test := range []struct {
+		name           string
+		sort           Sort
+		expectedEmails []string
+	}{
+		{
+			name:           "default",
+			expectedEmails: []string{"dome@email.com", "janedoe@email.com", "johndoe@email.com"},
+		},
+		{
+			name:           "descending nickname",
+			sort:           Sort{{Field: "nickname", Desc: true}},
+			expectedEmails: []string{"johndoe@email.com", "janedoe@email.com", "dome@email.com"},
+		},
+		{
+			name:           "country and id tiebreaker",
+			sort:           Sort{{Field: "country"}},
+			expectedEmails: []string{"janedoe@email.com", "dome@email.com", "johndoe@email.com"},
+		},
+		{
+			name:           "last name and descending email",
+			sort:           Sort{{Field: "last_name"}, {Field: "email", Desc: true}},
+			expectedEmails: []string{"johndoe@email.com", "janedoe@email.com", "dome@email.com"},
+		},
+	} {
+		s.Run(test.name, func() {
+			res, err := s.repo.list(context.TODO(), p, nil, test.sort, nil)
+			s.NoError(err)
+
+			actualEmails := []string{}
+			for _, r := range res {
+				actualEmails = append(actualEmails, r.Email)
+			}
+			s.Equal(test.expectedEmails, actualEmails)
+		})
+	}
+}
+
+func (s *repositoryTestSuite) TestDeleteByID() {
 	id := uuid.MustParse("00000000-0000-0000-0000-000000000042")
 	s.NoError(s.repo.db.Create(&User{
 		ID:        id,
@@ -136,14 +296,20 @@ func (s repositoryTestSuite) TestDeleteByID() {
 		Nickname:  "nn",
 		Email:     "email",
 		Country:   "US",
+		Version:   3,
 	}).Error)
 
-	s.NoError(s.repo.deleteByID(nil, id))
+	version, err := s.repo.deleteByID(context.TODO(), id)
+	s.NoError(err)
+	s.EqualValues(4, version)
 
 	s.ErrorIs(s.repo.db.Take(&User{}, id).Error, gorm.ErrRecordNotFound)
+
+	_, err = s.repo.deleteByID(context.TODO(), id)
+	s.ErrorIs(err, ErrUserNotFound)
 }
 
-func (s repositoryTestSuite) TestCreate() {
+func (s *repositoryTestSuite) TestCreate() {
 	user := User{
 		FirstName: "create-fn",
 		LastName:  "create-ln",
@@ -152,12 +318,13 @@ func (s repositoryTestSuite) TestCreate() {
 		Country:   "US",
 	}
 
-	newUser, err := s.repo.create(nil, user, "testpwd")
+	newUser, err := s.repo.create(context.TODO(), user, "testpwd")
 	s.NoError(err)
 	s.NotNil(newUser.ID)
 	s.True(newUser.ID != uuid.Nil)
 	s.True(time.Now().Sub(newUser.CreatedAt) < time.Second)
 	s.True(time.Now().Sub(*newUser.UpdatedAt) < time.Second)
+	s.EqualValues(1, newUser.Version)
 
 	var savedPwds []string
 	s.NoError(s.repo.db.Model(User{}).Where("id = ?", newUser.ID).Pluck("password", &savedPwds).Error)
@@ -166,18 +333,23 @@ func (s repositoryTestSuite) TestCreate() {
 	s.repo.db.Delete(&User{}, newUser.ID)
 }
 
-func (s repositoryTestSuite) TestUpdatePassword() {
+func (s *repositoryTestSuite) TestUpdatePassword() {
 	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
 	pwd := uuid.New().String()[0:4]
 
-	s.NoError(s.repo.updatePassword(nil, id, pwd))
+	var before User
+	s.Require().NoError(s.repo.db.Take(&before, id).Error)
+
+	version, err := s.repo.updatePassword(context.TODO(), id, pwd)
+	s.NoError(err)
+	s.Equal(before.Version+1, version)
 
 	var savedPwds []string
 	s.Require().NoError(s.repo.db.Model(User{}).Where("id = ?", id).Pluck("password", &savedPwds).Error)
 	s.Equal(pwd, savedPwds[0])
 }
 
-func (s repositoryTestSuite) TestUpdate() {
+func (s *repositoryTestSuite) TestUpdate() {
 	id := uuid.MustParse("00000000-0000-0000-0000-000000000003")
 	originalUser := User{
 		FirstName: "Zoltan",
@@ -204,18 +376,81 @@ func (s repositoryTestSuite) TestUpdate() {
 	} {
 		s.reinitDB()
 		change, expected := getChangeAndExpected(change)
-		updatedUser, err := s.repo.update(nil, id, change)
+		updatedUser, previousUser, err := s.repo.update(context.TODO(), id, change)
 		s.NoError(err)
+		s.Equal(originalUser.Email, previousUser.Email)
+		s.EqualValues(1, previousUser.Version)
 		s.Equal(expected.FirstName, updatedUser.FirstName)
 		s.Equal(expected.LastName, updatedUser.LastName)
 		s.Equal(expected.Nickname, updatedUser.Nickname)
 		s.Equal(expected.Email, updatedUser.Email)
 		s.Equal(expected.Country, updatedUser.Country)
+		s.EqualValues(2, updatedUser.Version)
+	}
+}
+
+func (s *repositoryTestSuite) TestUpdate_ReturnsNotFound() {
+	_, _, err := s.repo.update(context.TODO(), uuid.New(), User{Nickname: "missing"})
+	s.ErrorIs(err, ErrUserNotFound)
+
+	_, err = s.repo.updatePassword(context.TODO(), uuid.New(), "testpwd")
+	s.ErrorIs(err, ErrUserNotFound)
+}
+
+func (s *repositoryTestSuite) TestCreateAndUpdate_ReturnEmailTaken() {
+	s.reinitDB()
+
+	_, err := s.repo.create(context.TODO(), User{FirstName: "fn", LastName: "ln", Nickname: "nn", Email: "dome@email.com", Country: "US"}, "testpwd")
+	s.ErrorIs(err, ErrEmailTaken)
+
+	_, _, err = s.repo.update(context.TODO(), uuid.MustParse("00000000-0000-0000-0000-000000000001"), User{Email: "dome@email.com"})
+	s.ErrorIs(err, ErrEmailTaken)
+}
+
+func (s *repositoryTestSuite) TestReplace() {
+	s.reinitDB()
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	current, err := s.repo.findByID(context.TODO(), id, nil)
+	s.Require().NoError(err)
+
+	updatedUser, previousUser, err := s.repo.replace(context.TODO(), id,
+		User{FirstName: "Johnny", LastName: "Doe", Nickname: "johnny", Email: "johnny@email.com", Country: "UK"}, current.Version)
+
+	s.NoError(err)
+	s.Equal("johndoe", previousUser.Nickname)
+	s.Equal("johnny", updatedUser.Nickname)
+	s.Equal("UK", updatedUser.Country)
+	s.Equal(current.Version+1, updatedUser.Version)
 
+	// the version of the read user is outdated after the replace
+	_, _, err = s.repo.replace(context.TODO(), id, *updatedUser, current.Version)
+	s.ErrorIs(err, ErrUserModified)
+
+	// the unchanged user isn't saved
+	unchangedUser, _, err := s.repo.replace(context.TODO(), id, *updatedUser, updatedUser.Version)
+	s.NoError(err)
+	s.Equal(updatedUser.Version, unchangedUser.Version)
+
+	_, _, err = s.repo.replace(context.TODO(), uuid.New(), *updatedUser, 0)
+	s.ErrorIs(err, ErrUserNotFound)
+}
+
+func (s *repositoryTestSuite) TestListAfterID() {
+	s.reinitDB()
+
+	first, err := s.repo.listAfterID(context.TODO(), uuid.Nil, 2)
+	s.NoError(err)
+	s.Len(first, 2)
+	s.Less(first[0].ID.String(), first[1].ID.String())
+
+	next, err := s.repo.listAfterID(context.TODO(), first[1].ID, 100)
+	s.NoError(err)
+	for _, u := range next {
+		s.Greater(u.ID.String(), first[1].ID.String())
 	}
 }
 
-func (s repositoryTestSuite) reinitDB() {
+func (s *repositoryTestSuite) reinitDB() {
 	s.NoError(s.repo.db.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&User{}).Error)
 
 	sql, err := os.ReadFile("../../scripts/initdb.sql")
@@ -0,0 +1,208 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FilterOperator is the comparison of a filter condition
+type FilterOperator string
+
+const (
+	FilterEq       FilterOperator = "eq"
+	FilterPrefix   FilterOperator = "prefix"
+	FilterContains FilterOperator = "contains"
+	FilterIn       FilterOperator = "in"
+	FilterRange    FilterOperator = "range"
+
+	maxFilterDepth      = 5
+	maxFilterConditions = 50
+	maxFilterInValues   = 100
+)
+
+// searchFields are the searchable fields by their JSON name with their column and allowed operators.
+// Only these columns could be used in the compiled queries.
+var searchFields = map[string]searchField{
+	"id":         {column: "id", operators: []FilterOperator{FilterEq, FilterIn}, uuid: true},
+	"first_name": {column: "first_name", operators: []FilterOperator{FilterEq, FilterPrefix, FilterContains, FilterIn}},
+	"last_name":  {column: "last_name", operators: []FilterOperator{FilterEq, FilterPrefix, FilterContains, FilterIn}},
+	"nickname":   {column: "nickname", operators: []FilterOperator{FilterEq, FilterPrefix, FilterContains, FilterIn}},
+	"email":      {column: "email", operators: []FilterOperator{FilterEq, FilterPrefix, FilterContains, FilterIn}},
+	"country":    {column: "country", operators: []FilterOperator{FilterEq, FilterIn}, upper: true},
+	"created_at": {column: "created_at", operators: []FilterOperator{FilterRange}},
+	"updated_at": {column: "updated_at", operators: []FilterOperator{FilterRange}},
+}
+
+type (
+	// Filter is a node of a search filter tree. It's either a condition with Field, Op and Value,
+	// or a combination of filters with exactly one of And, Or and Not.
+	// The Value is a string, a slice of strings for FilterIn, or a map with the `from` and `to`
+	// RFC3339 timestamps for FilterRange, as it's decoded from JSON.
+	Filter struct {
+		And   []Filter
+		Or    []Filter
+		Not   *Filter
+		Field string
+		Op    FilterOperator
+		Value interface{}
+	}
+
+	// TimeRange is the value of a FilterRange condition, From is inclusive and To is exclusive
+	TimeRange struct {
+		From *time.Time
+		To   *time.Time
+	}
+
+	searchField struct {
+		column    string
+		operators []FilterOperator
+		uuid      bool
+		upper     bool
+	}
+)
+
+// Validate checks the structure of the filter tree and the fields, the operators and the values of the conditions.
+func (f Filter) Validate() error {
+	conditions := 0
+	return f.validate(1, &conditions)
+}
+
+func (f Filter) validate(depth int, conditions *int) error {
+	if depth > maxFilterDepth {
+		return fmt.Errorf("filter is nested deeper than %d levels", maxFilterDepth)
+	}
+
+	kinds := 0
+	for _, set := range []bool{f.And != nil, f.Or != nil, f.Not != nil, f.Field != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return errors.New("filter must have exactly one of and, or, not and field")
+	}
+
+	switch {
+	case f.And != nil:
+		return validateFilters("and", f.And, depth, conditions)
+	case f.Or != nil:
+		return validateFilters("or", f.Or, depth, conditions)
+	case f.Not != nil:
+		return f.Not.validate(depth+1, conditions)
+	}
+
+	*conditions++
+	if *conditions > maxFilterConditions {
+		return fmt.Errorf("filter has more than %d conditions", maxFilterConditions)
+	}
+	_, _, err := f.condition()
+	return err
+}
+
+func validateFilters(op string, filters []Filter, depth int, conditions *int) error {
+	if len(filters) == 0 {
+		return fmt.Errorf("%s must have at least one filter", op)
+	}
+	for _, filter := range filters {
+		if err := filter.validate(depth+1, conditions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// condition returns the searched field and the typed value of the condition
+func (f Filter) condition() (searchField, interface{}, error) {
+	field, ok := searchFields[f.Field]
+	if !ok {
+		return field, nil, fmt.Errorf("unknown field %s", f.Field)
+	}
+	if !field.allows(f.Op) {
+		return field, nil, fmt.Errorf("operator %s is not allowed on %s", f.Op, f.Field)
+	}
+
+	switch f.Op {
+	case FilterIn:
+		values, ok := f.Value.([]interface{})
+		if !ok || len(values) == 0 || len(values) > maxFilterInValues {
+			return field, nil, fmt.Errorf("value of %s in must be an array of 1-%d strings", f.Field, maxFilterInValues)
+		}
+		normalized := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			s, err := field.value(f.Field, v)
+			if err != nil {
+				return field, nil, err
+			}
+			normalized = append(normalized, s)
+		}
+		return field, normalized, nil
+	case FilterRange:
+		r, err := timeRange(f.Field, f.Value)
+		return field, r, err
+	}
+
+	v, err := field.value(f.Field, f.Value)
+	return field, v, err
+}
+
+func (f searchField) allows(op FilterOperator) bool {
+	for _, o := range f.operators {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the non-empty string value, the uppercase country code or the parsed uuid
+func (f searchField) value(name string, value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return nil, fmt.Errorf("value of %s must be a non-empty string", name)
+	}
+	if f.uuid {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("value of %s must be a uuid", name)
+		}
+		return id, nil
+	}
+	if f.upper {
+		return strings.ToUpper(s), nil
+	}
+	return s, nil
+}
+
+func timeRange(name string, value interface{}) (TimeRange, error) {
+	var r TimeRange
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return r, fmt.Errorf("value of %s range must be an object with from and to", name)
+	}
+	for k, v := range m {
+		s, ok := v.(string)
+		if !ok {
+			return r, fmt.Errorf("%s of %s range must be a date-time", k, name)
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return r, fmt.Errorf("%s of %s range must be a date-time", k, name)
+		}
+		switch k {
+		case "from":
+			r.From = &t
+		case "to":
+			r.To = &t
+		default:
+			return r, fmt.Errorf("unknown %s of %s range", k, name)
+		}
+	}
+	if r.From == nil && r.To == nil {
+		return r, fmt.Errorf("%s range must have from or to", name)
+	}
+	return r, nil
+}
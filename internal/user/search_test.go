@@ -0,0 +1,152 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type (
+	searchTestSuite struct {
+		db *gorm.DB
+		suite.Suite
+	}
+)
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(searchTestSuite))
+}
+
+func (s *searchTestSuite) SetupSuite() {
+	// the queries are only built, so the database is not connected
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	s.Require().NoError(err)
+	s.db = db
+}
+
+func (s *searchTestSuite) TestValidate() {
+	s.NoError(Filter{
+		And: []Filter{
+			{Field: "country", Op: FilterIn, Value: []interface{}{"hu", "DE"}},
+			{Or: []Filter{
+				{Field: "email", Op: FilterPrefix, Value: "john"},
+				{Not: &Filter{Field: "nickname", Op: FilterContains, Value: "bot"}},
+			}},
+			{Field: "created_at", Op: FilterRange, Value: map[string]interface{}{"from": "2023-01-01T00:00:00Z"}},
+			{Field: "id", Op: FilterEq, Value: "9b0e5e7f-6a4b-4bd5-9c55-1d9c5b0b1b0e"},
+		},
+	}.Validate())
+}
+
+func (s *searchTestSuite) TestValidate_ReturnsError() {
+	deep := Filter{Field: "country", Op: FilterEq, Value: "HU"}
+	for i := 0; i < maxFilterDepth; i++ {
+		deep = Filter{Not: &deep}
+	}
+	many := Filter{Or: []Filter{}}
+	for i := 0; i <= maxFilterConditions; i++ {
+		many.Or = append(many.Or, Filter{Field: "country", Op: FilterEq, Value: "HU"})
+	}
+
+	for _, test := range []struct {
+		name   string
+		filter Filter
+	}{
+		{"empty filter", Filter{}},
+		{"field and combination", Filter{Field: "country", Op: FilterEq, Value: "HU", Not: &Filter{}}},
+		{"empty and", Filter{And: []Filter{}}},
+		{"unknown field", Filter{Field: "password", Op: FilterEq, Value: "secret"}},
+		{"column name as field", Filter{Field: "country; DROP TABLE users", Op: FilterEq, Value: "HU"}},
+		{"not allowed operator", Filter{Field: "country", Op: FilterContains, Value: "H"}},
+		{"unknown operator", Filter{Field: "email", Op: "like", Value: "%"}},
+		{"empty value", Filter{Field: "email", Op: FilterEq, Value: ""}},
+		{"number value", Filter{Field: "email", Op: FilterEq, Value: 42.0}},
+		{"invalid id", Filter{Field: "id", Op: FilterEq, Value: "42"}},
+		{"in without array", Filter{Field: "country", Op: FilterIn, Value: "HU"}},
+		{"empty in", Filter{Field: "country", Op: FilterIn, Value: []interface{}{}}},
+		{"in with number", Filter{Field: "country", Op: FilterIn, Value: []interface{}{"HU", 1.0}}},
+		{"range without object", Filter{Field: "created_at", Op: FilterRange, Value: "2023-01-01T00:00:00Z"}},
+		{"empty range", Filter{Field: "created_at", Op: FilterRange, Value: map[string]interface{}{}}},
+		{"invalid range", Filter{Field: "created_at", Op: FilterRange, Value: map[string]interface{}{"from": "yesterday"}}},
+		{"unknown range key", Filter{Field: "created_at", Op: FilterRange, Value: map[string]interface{}{"since": "2023-01-01T00:00:00Z"}}},
+		{"nested error", Filter{And: []Filter{{Field: "email", Op: FilterEq, Value: "a"}, {Field: "age"}}}},
+		{"too deep", deep},
+		{"too many conditions", many},
+	} {
+		s.Run(test.name, func() {
+			s.Error(test.filter.Validate())
+		})
+	}
+}
+
+func (s *searchTestSuite) TestFilterExpression() {
+	for _, test := range []struct {
+		name         string
+		filter       Filter
+		expectedSQL  string
+		expectedVars []interface{}
+	}{
+		{
+			name:         "eq",
+			filter:       Filter{Field: "email", Op: FilterEq, Value: "john@doe.com"},
+			expectedSQL:  `"users"."email" = $1`,
+			expectedVars: []interface{}{"john@doe.com"},
+		},
+		{
+			name:         "prefix with escaped wildcards",
+			filter:       Filter{Field: "nickname", Op: FilterPrefix, Value: `50%_off\`},
+			expectedSQL:  `"users"."nickname" ILIKE $1`,
+			expectedVars: []interface{}{`50\%\_off\\%`},
+		},
+		{
+			name:         "contains",
+			filter:       Filter{Field: "last_name", Op: FilterContains, Value: "doe"},
+			expectedSQL:  `"users"."last_name" ILIKE $1`,
+			expectedVars: []interface{}{"%doe%"},
+		},
+		{
+			name:         "in with uppercase countries",
+			filter:       Filter{Field: "country", Op: FilterIn, Value: []interface{}{"hu", "de"}},
+			expectedSQL:  `"users"."country" IN ($1,$2)`,
+			expectedVars: []interface{}{"HU", "DE"},
+		},
+		{
+			name: "and, or and not",
+			filter: Filter{And: []Filter{
+				{Field: "country", Op: FilterEq, Value: "UK"},
+				{Or: []Filter{
+					{Field: "first_name", Op: FilterEq, Value: "Jane"},
+					{Not: &Filter{Field: "first_name", Op: FilterEq, Value: "John"}},
+				}},
+			}},
+			expectedSQL:  `("users"."country" = $1 AND ("users"."first_name" = $2 OR "users"."first_name" <> $3))`,
+			expectedVars: []interface{}{"UK", "Jane", "John"},
+		},
+	} {
+		s.Run(test.name, func() {
+			sql, vars := s.where(test.filter)
+			s.Equal(`SELECT * FROM "users" WHERE `+test.expectedSQL, sql)
+			s.Equal(test.expectedVars, vars)
+		})
+	}
+}
+
+func (s *searchTestSuite) TestFilterExpression_Range() {
+	sql, vars := s.where(Filter{Field: "updated_at", Op: FilterRange, Value: map[string]interface{}{
+		"from": "2023-01-01T00:00:00Z",
+		"to":   "2023-02-01T00:00:00Z",
+	}})
+	s.Equal(`SELECT * FROM "users" WHERE ("users"."updated_at" >= $1 AND "users"."updated_at" < $2)`, sql)
+	s.Len(vars, 2)
+}
+
+func (s *searchTestSuite) where(filter Filter) (string, []interface{}) {
+	s.Require().NoError(filter.Validate())
+	expr, err := filterExpression(filter)
+	s.Require().NoError(err)
+
+	stmt := s.db.Where(expr).Find(&[]User{}).Statement
+	return stmt.SQL.String(), stmt.Vars
+}
@@ -6,50 +6,121 @@ import (
 	"faceit/internal/common"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	streamBatchSize = 100
+	// idempotencyWriteTimeout limits the completion and the release of an idempotency key, what are done
+	// even if the request was canceled
+	idempotencyWriteTimeout = 5 * time.Second
+	maxBatchGetSize         = 100
+	maxPageLimit            = 100
 )
 
 type (
 	repository interface {
-		findByID(ctx context.Context, id uuid.UUID) (*User, error)
-		list(ctx context.Context, pagination common.Pagination, filter *User) ([]User, error)
+		findByID(ctx context.Context, id uuid.UUID, fields Fields) (*User, error)
+		findByIDs(ctx context.Context, ids []uuid.UUID, fields Fields) ([]User, error)
+		list(ctx context.Context, pagination common.Pagination, filter *User, sort Sort, fields Fields) ([]User, error)
+		search(ctx context.Context, pagination common.Pagination, filter Filter) ([]User, error)
 		create(ctx context.Context, user User, password string) (*User, error)
-		update(ctx context.Context, id uuid.UUID, user User) (*User, error)
-		updatePassword(ctx context.Context, id uuid.UUID, password string) error
-		deleteByID(ctx context.Context, id uuid.UUID) error
+		update(ctx context.Context, id uuid.UUID, user User) (*User, *User, error)
+		replace(ctx context.Context, id uuid.UUID, user User, version int64) (*User, *User, error)
+		updatePassword(ctx context.Context, id uuid.UUID, password string) (int64, error)
+		deleteByID(ctx context.Context, id uuid.UUID) (int64, error)
+		listAfterID(ctx context.Context, afterID uuid.UUID, limit int) ([]User, error)
 	}
 
 	eventPublisher interface {
-		publishCreated(ctx context.Context, userID uuid.UUID, userChanges *User) error
-		publishDeleted(ctx context.Context, userID uuid.UUID) error
-		publishUpdated(ctx context.Context, userID uuid.UUID, userChanges *User) error
-		publishPasswordChanged(ctx context.Context, userID uuid.UUID) error
+		publish(ctx context.Context, event UserEvent) error
+		close(ctx context.Context) error
+	}
+
+	eventStore interface {
+		transaction(ctx context.Context, change func(ctx context.Context) error) error
+		save(ctx context.Context, events []UserEvent) error
+		listAfter(ctx context.Context, afterID int64, filter EventFilter, limit int) ([]StoredEvent, error)
+		lastID(ctx context.Context) (int64, error)
+		changed() <-chan struct{}
+	}
+
+	idempotencyStore interface {
+		reserve(ctx context.Context, key string, requestHash string, expiresAt time.Time) (*IdempotencyRecord, bool, error)
+		complete(ctx context.Context, key string, response *User, expiresAt time.Time) error
+		release(ctx context.Context, key string) error
+	}
+
+	// EventListener receives every published user event, i.e. to forward them to other channels than RabbitMQ.
+	// It is called from the event publisher workers so it should not block for long.
+	EventListener interface {
+		OnUserEvent(ctx context.Context, event UserEvent)
 	}
 
 	// Service manages the users
 	Service struct {
-		repository     repository
-		eventPublisher eventPublisher
+		repository         repository
+		eventPublisher     eventPublisher
+		eventStore         eventStore
+		streamPollInterval time.Duration
+		idempotency        idempotencyStore
+		idempotencyTTL     time.Duration
+		idempotencyLease   time.Duration
+		fieldChangeEvents  bool
+		verificationKeys   []VerificationKey
+		replaySender       replaySender
+		// rmq and db are the connections of the service what are shared with the health check
+		rmq *RmqEventPublisher
+		db  *gorm.DB
+		// projection is applied to the streamed events the same way as to the published ones
+		projection eventProjection
+		// streams is canceled by CloseStreams to end the running event streams
+		streams      context.Context
+		closeStreams context.CancelFunc
 	}
 )
 
-// NewService creates a new Service with it's all required dependencies
-func NewService() (*Service, error) {
+// NewService creates a new Service with it's all required dependencies.
+// The listeners are notified about every user event besides publishing it to RabbitMQ.
+func NewService(listeners ...EventListener) (*Service, error) {
 	r, err := NewRepository()
 	if err != nil {
 		return nil, err
 	}
 
-	p, err := NewEventPublisher()
+	rmq, err := NewEventPublisher()
 	if err != nil {
 		return nil, err
 	}
 
+	store := newEventStore(r.db)
+	p, err := NewAsyncEventPublisher(rmq, NewAsyncEventPublisherConfig())
+	if err != nil {
+		return nil, err
+	}
+	p.notifier = eventNotifier{projection: rmq.projection, listeners: listeners}
+
+	streams, closeStreams := context.WithCancel(context.Background())
 	return &Service{
-		repository:     r,
-		eventPublisher: p,
+		repository:         r,
+		eventPublisher:     p,
+		eventStore:         store,
+		streamPollInterval: common.GetEnvDuration("EVENT_STREAM_POLL_INTERVAL", time.Second),
+		idempotency:        newIdempotencyStore(r.db),
+		idempotencyTTL:     common.GetEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		idempotencyLease:   common.GetEnvDuration("IDEMPOTENCY_KEY_LEASE", time.Minute),
+		fieldChangeEvents:  common.GetEnv("FIELD_CHANGE_EVENTS", "true") == "true",
+		verificationKeys:   rmq.VerificationKeys(),
+		replaySender:       rmq,
+		rmq:                rmq,
+		db:                 r.db,
+		projection:         rmq.projection,
+		streams:            streams,
+		closeStreams:       closeStreams,
 	}, nil
 }
 
@@ -62,28 +133,80 @@ func (s Service) Create(ctx context.Context, user User, password string) (*User,
 	}
 
 	if err := user.Validate(); err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrInvalidUserInputData, err.Error())
+		return nil, err
 	}
 
 	user.Country = strings.ToUpper(user.Country)
-	newUser, err := s.repository.create(ctx, user, encryptPass(password))
-	if err == nil {
-		if err := s.eventPublisher.publishCreated(ctx, newUser.ID, newUser); err != nil {
+	var newUser *User
+	err := s.write(ctx, func(ctx context.Context) ([]UserEvent, error) {
+		var err error
+		if newUser, err = s.repository.create(ctx, user, encryptPass(password)); err != nil {
+			return nil, err
+		}
+		return []UserEvent{newUserEvent(UserEventTypeCreated, newUser.ID, newUser.Version, newUser)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newUser, nil
+}
+
+// CreateIdempotent creates the user only once for the same idempotency key.
+// The user created by the first request is returned with true when the key was already used with the same request.
+// ErrIdempotencyKeyReused is returned when the key was used with a different request,
+// and ErrIdempotencyKeyInProgress when the first request is not finished yet.
+// The key is released when the user creation fails, so the request could be retried with the same key.
+// The key is reserved only for the idempotency lease until it's completed, so the key of a request what was
+// interrupted before it could be completed or released could be used again after the lease.
+func (s Service) CreateIdempotent(ctx context.Context, key string, user User, password string) (*User, bool, error) {
+	hash := createRequestHash(user, password)
+	record, reserved, err := s.idempotency.reserve(ctx, key, hash, time.Now().Add(s.idempotencyLease))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !reserved {
+		switch {
+		case record.RequestHash != hash:
+			return nil, false, ErrIdempotencyKeyReused
+		case record.Response == nil:
+			return nil, false, ErrIdempotencyKeyInProgress
+		}
+		return record.Response, true, nil
+	}
+
+	newUser, err := s.Create(ctx, user, password)
+
+	// the request could be canceled or timed out by now, but the key must not stay in progress
+	c, cancel := common.DetachedContext(ctx, idempotencyWriteTimeout)
+	defer cancel()
+	if err != nil {
+		if err := s.idempotency.release(c, key); err != nil {
 			log.Err(err).
 				Str(common.CorrelationID, common.GetCorrelationID(ctx)).
-				Stringer("ID", newUser.ID).
-				Msg("failed to publish create event")
+				Msg("failed to release idempotency key")
 		}
+		return nil, false, err
+	}
+
+	if err := s.idempotency.complete(c, key, newUser, time.Now().Add(s.idempotencyTTL)); err != nil {
+		log.Err(err).
+			Str(common.CorrelationID, common.GetCorrelationID(ctx)).
+			Stringer("ID", newUser.ID).
+			Msg("failed to save idempotent response")
 	}
-	return newUser, err
+	return newUser, false, nil
 }
 
-// Get retrieves a single user.
-func (s Service) Get(ctx context.Context, id uuid.UUID) (*User, error) {
+// Get retrieves a single user, only the selected fields are read when the fields are set.
+func (s Service) Get(ctx context.Context, id uuid.UUID, fields Fields) (*User, error) {
 	if id == uuid.Nil {
 		return nil, ErrNilUUIDNotAllowed
 	}
-	return s.repository.findByID(ctx, id)
+	if err := fields.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFields, err.Error())
+	}
+	return s.repository.findByID(ctx, id, fields)
 }
 
 // Update validates and saves changes on an existing user.
@@ -95,7 +218,7 @@ func (s Service) Update(ctx context.Context, id uuid.UUID, user User, password s
 	}
 
 	if err := user.ValidateIfNotEmpty(); err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrInvalidUserInputData, err.Error())
+		return nil, err
 	}
 
 	if user.Country != "" {
@@ -103,37 +226,146 @@ func (s Service) Update(ctx context.Context, id uuid.UUID, user User, password s
 	}
 
 	var updatedUser *User = nil
-	var err error
 	emptyUser := User{}
 	if user != emptyUser {
-		updatedUser, err = s.repository.update(ctx, id, user)
-		if err == nil {
-			if err := s.eventPublisher.publishUpdated(ctx, id, updatedUser); err != nil {
-				log.Err(err).
-					Str(common.CorrelationID, common.GetCorrelationID(ctx)).
-					Stringer("ID", id).
-					Msg("failed to publish update event")
+		err := s.write(ctx, func(ctx context.Context) ([]UserEvent, error) {
+			var previousUser *User
+			var err error
+			if updatedUser, previousUser, err = s.repository.update(ctx, id, user); err != nil {
+				return nil, err
 			}
+			return s.updateEvents(id, *previousUser, *updatedUser), nil
+		})
+		if err != nil {
+			return nil, err
 		}
+	}
 
+	if password == "" {
+		return updatedUser, nil
+	}
+
+	return updatedUser, s.updatePassword(ctx, id, password)
+}
+
+// Replace saves every field of an existing user, the password is only changed when it's set.
+// The same events are published as by Update.
+func (s Service) Replace(ctx context.Context, id uuid.UUID, user User, password string) (*User, error) {
+	if id == uuid.Nil {
+		return nil, ErrNilUUIDNotAllowed
+	}
+
+	return s.replace(ctx, id, user, password, 0)
+}
+
+// Patch applies the JSON Merge Patch or the JSON Patch to the current user and saves the patched user,
+// what is validated as a whole. ErrPatchTestFailed is returned when a `test` operation of the JSON Patch fails,
+// and ErrUserModified when the user was changed since it was read, so the patch could be retried.
+// The same events are published as by Update.
+func (s Service) Patch(ctx context.Context, id uuid.UUID, patchType PatchType, patch []byte) (*User, error) {
+	if id == uuid.Nil {
+		return nil, ErrNilUUIDNotAllowed
+	}
+
+	current, err := s.repository.findByID(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, password, err := applyPatch(*current, patchType, patch)
+	if err != nil {
+		return nil, err
+	}
+	return s.replace(ctx, id, patched, password, current.Version)
+}
+
+// replace validates and saves the user when its version is still the expected version (or any version for 0),
+// then changes the password when it's set
+func (s Service) replace(ctx context.Context, id uuid.UUID, user User, password string, version int64) (*User, error) {
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+	user.Country = strings.ToUpper(user.Country)
+
+	var updatedUser *User
+	err := s.write(ctx, func(ctx context.Context) ([]UserEvent, error) {
+		var previousUser *User
+		var err error
+		if updatedUser, previousUser, err = s.repository.replace(ctx, id, user, version); err != nil {
+			return nil, err
+		}
+		// the unchanged user keeps its version, so there is no update to publish, i.e. on a password only patch
+		if updatedUser.Version == previousUser.Version {
+			return nil, nil
+		}
+		return s.updateEvents(id, *previousUser, *updatedUser), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	if password == "" {
-		return updatedUser, err
+		return updatedUser, nil
+	}
+	return updatedUser, s.updatePassword(ctx, id, password)
+}
+
+// updateEvents returns the UserEventTypeUpdated event of the updated user and its field change events
+func (s Service) updateEvents(id uuid.UUID, previous User, updated User) []UserEvent {
+	events := []UserEvent{newUserEvent(UserEventTypeUpdated, id, updated.Version, &updated)}
+	if s.fieldChangeEvents {
+		events = append(events, fieldChangeEvents(id, previous, updated)...)
+	}
+	return events
+}
+
+// updatePassword saves the encrypted password with the UserEventTypePasswordChanged event
+func (s Service) updatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	return s.write(ctx, func(ctx context.Context) ([]UserEvent, error) {
+		version, err := s.repository.updatePassword(ctx, id, encryptPass(password))
+		if err != nil {
+			return nil, err
+		}
+		return []UserEvent{newUserEvent(UserEventTypePasswordChanged, id, version, nil)}, nil
+	})
+}
+
+// fieldChangeEvents returns the specific change events of the email, nickname and country
+// with the same version as the UserEventTypeUpdated event of the change.
+func fieldChangeEvents(id uuid.UUID, previous User, updated User) []UserEvent {
+	var events []UserEvent
+	for _, c := range fieldChanges(previous, updated) {
+		events = append(events, newFieldChangeEvent(c.eventType, id, updated.Version, c.FieldChange))
+	}
+	return events
+}
+
+// write runs the change in a transaction what saves the events of the change as well, so an event is stored only
+// when its change is committed, and in the order of the commits. The stored events are queued for publishing after
+// the commit, a failed publish is only logged, as the event could still be streamed and replayed from the store.
+func (s Service) write(ctx context.Context, change func(ctx context.Context) ([]UserEvent, error)) error {
+	var events []UserEvent
+	err := s.eventStore.transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if events, err = change(ctx); err != nil {
+			return err
+		}
+		return s.eventStore.save(ctx, events)
+	})
+	if err != nil {
+		return err
 	}
 
-	pass := encryptPass(password)
-	err = s.repository.updatePassword(ctx, id, pass)
-	if err == nil {
-		if err := s.eventPublisher.publishPasswordChanged(ctx, id); err != nil {
+	for _, event := range events {
+		if err := s.eventPublisher.publish(ctx, event); err != nil {
 			log.Err(err).
 				Str(common.CorrelationID, common.GetCorrelationID(ctx)).
-				Stringer("ID", id).
-				Msg("failed to publish update password event")
+				Stringer("ID", event.UserID).
+				Str("type", string(event.Type)).
+				Msg("failed to publish user event")
 		}
 	}
-
-	return updatedUser, err
+	return nil
 }
 
 // Delete removes an existing user.
@@ -143,21 +375,62 @@ func (s Service) Delete(ctx context.Context, id uuid.UUID) error {
 		return ErrNilUUIDNotAllowed
 	}
 
-	err := s.repository.deleteByID(ctx, id)
-	if err == nil {
-		if err := s.eventPublisher.publishDeleted(ctx, id); err != nil {
-			log.Err(err).
-				Str(common.CorrelationID, common.GetCorrelationID(ctx)).
-				Stringer("ID", id).
-				Msg("failed to publish delete event")
+	return s.write(ctx, func(ctx context.Context) ([]UserEvent, error) {
+		version, err := s.repository.deleteByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return []UserEvent{newUserEvent(UserEventTypeDeleted, id, version, nil)}, nil
+	})
+}
+
+// BatchGet retrieves the users of the ids with a single query. The found users are returned in the order of the ids
+// (a duplicated id is returned once) with the ids of the missing users.
+func (s Service) BatchGet(ctx context.Context, ids []uuid.UUID, fields Fields) ([]User, []uuid.UUID, error) {
+	if len(ids) == 0 || len(ids) > maxBatchGetSize {
+		return nil, nil, fmt.Errorf("%w: 1-%d ids are required", ErrInvalidIDs, maxBatchGetSize)
+	}
+	if err := fields.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidFields, err.Error())
+	}
+
+	unique := make([]uuid.UUID, 0, len(ids))
+	requested := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		if id == uuid.Nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidIDs, ErrNilUUIDNotAllowed.Error())
+		}
+		if !requested[id] {
+			requested[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found, err := s.repository.findByIDs(ctx, unique, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[uuid.UUID]User, len(found))
+	for _, u := range found {
+		byID[u.ID] = u
+	}
+
+	users := make([]User, 0, len(found))
+	missing := []uuid.UUID{}
+	for _, id := range unique {
+		if u, ok := byID[id]; ok {
+			users = append(users, u)
+		} else {
+			missing = append(missing, id)
 		}
 	}
-	return err
+	return users, missing, nil
 }
 
 // List returns an ordered slice of users.
-// The result is paged what could be parameterized and filtered.
-func (s Service) List(ctx context.Context, pagination common.Pagination, filter *User) ([]User, error) {
+// The result is paged what could be parameterized, filtered and sorted. The default order is used when the sort is empty.
+// Only the selected fields are read when the fields are set.
+func (s Service) List(ctx context.Context, pagination common.Pagination, filter *User, sort Sort, fields Fields) ([]User, error) {
 	if err := pagination.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrInvalidPagination, err.Error())
 	}
@@ -166,7 +439,151 @@ func (s Service) List(ctx context.Context, pagination common.Pagination, filter
 		return nil, fmt.Errorf("%w: %s", ErrInvalidFilter, err.Error())
 	}
 
-	return s.repository.list(ctx, pagination, filter)
+	if err := sort.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSort, err.Error())
+	}
+
+	if err := fields.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFields, err.Error())
+	}
+
+	return s.repository.list(ctx, pagination, filter, sort, fields)
+}
+
+// ListAfter returns at most limit users with greater id than afterID ordered by id, so the users could be paged
+// by the id of the last user of the previous page. The first page is returned for uuid.Nil.
+// The returned flag is true when there are more users after the page.
+func (s Service) ListAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]User, bool, error) {
+	if limit < 1 || limit > maxPageLimit {
+		return nil, false, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPagination, maxPageLimit)
+	}
+
+	// one more user is read to know whether there is a next page
+	users, err := s.repository.listAfterID(ctx, afterID, limit+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(users) > limit {
+		return users[:limit], true, nil
+	}
+	return users, false, nil
+}
+
+// Search returns an ordered and paged slice of the users what match the filter tree.
+func (s Service) Search(ctx context.Context, pagination common.Pagination, filter Filter) ([]User, error) {
+	if err := pagination.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPagination, err.Error())
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFilter, err.Error())
+	}
+
+	return s.repository.search(ctx, pagination, filter)
+}
+
+// StreamEvents sends the stored user events matching the filter to the send function in commit order until the context is done.
+// The events are projected by the EVENT_FIELD_PROJECTION rules like the published ones.
+// The events are sent after lastEventID when it's set, otherwise only the new events are sent.
+// The send function is called with nil on every poll without new events, so the caller could detect a closed connection.
+func (s Service) StreamEvents(ctx context.Context, lastEventID *int64, filter EventFilter, send func(*StoredEvent) error) error {
+	if err := filter.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidFilter, err.Error())
+	}
+
+	var after int64
+	if lastEventID != nil {
+		after = *lastEventID
+	} else {
+		var err error
+		if after, err = s.eventStore.lastID(ctx); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(s.streamPollInterval)
+	defer ticker.Stop()
+	for {
+		changed := s.eventStore.changed()
+		events, err := s.eventStore.listAfter(ctx, after, filter, streamBatchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for i := range events {
+			after = events[i].ID
+			projected, ok := s.projection.apply(events[i].UserEvent)
+			if !ok {
+				continue
+			}
+			events[i].UserEvent = projected
+			if err := send(&events[i]); err != nil {
+				return err
+			}
+		}
+		if len(events) == streamBatchSize {
+			continue
+		}
+		if len(events) == 0 {
+			if err := send(nil); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.streamsClosed():
+			return nil
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Replayer returns an event replayer what shares the database and the RabbitMQ connection of the service,
+// so it's closed by Close of the service.
+func (s Service) Replayer() *Replayer {
+	return &Replayer{
+		repository: s.repository,
+		eventStore: s.eventStore,
+		sender:     s.replaySender,
+	}
+}
+
+// Connections returns the RabbitMQ publisher and the database connection of the service, i.e. for the health check,
+// so they are not opened again.
+func (s Service) Connections() (*RmqEventPublisher, *gorm.DB) {
+	return s.rmq, s.db
+}
+
+// EventVerificationKeys returns the public keys what verify the signatures of the published user events.
+func (s Service) EventVerificationKeys() []VerificationKey {
+	return s.verificationKeys
+}
+
+// CloseStreams ends the running event streams, so a graceful shutdown doesn't wait for them until its timeout.
+// The streams started afterwards end at their first wait for new events.
+func (s Service) CloseStreams() {
+	if s.closeStreams != nil {
+		s.closeStreams()
+	}
+}
+
+// streamsClosed is closed by CloseStreams, it's nil (so it never closes) without streams context
+func (s Service) streamsClosed() <-chan struct{} {
+	if s.streams == nil {
+		return nil
+	}
+	return s.streams.Done()
+}
+
+// Close flushes the pending user events and releases the event publisher connection.
+func (s Service) Close(ctx context.Context) error {
+	return s.eventPublisher.close(ctx)
 }
 
 func encryptPass(pass string) string {
@@ -1,27 +1,38 @@
 package user
 
 import (
+	"context"
 	"errors"
 	"faceit/internal/common"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 const (
-	findByID               = "findByID"
-	create                 = "create"
-	list                   = "list"
-	deleteByID             = "deleteByID"
-	update                 = "update"
-	updatePass             = "updatePassword"
-	publishDeleted         = "publishDeleted"
-	publishCreated         = "publishCreated"
-	publishUpdated         = "publishUpdated"
-	publishPasswordChanged = "publishPasswordChanged"
+	findByID    = "findByID"
+	findByIDs   = "findByIDs"
+	create      = "create"
+	list        = "list"
+	deleteByID  = "deleteByID"
+	update      = "update"
+	replace     = "replace"
+	updatePass  = "updatePassword"
+	transaction = "transaction"
+	save        = "save"
+	lastID      = "lastID"
+	listAfter   = "listAfter"
+	search      = "search"
+	changed     = "changed"
+	reserve     = "reserve"
+	complete    = "complete"
+	release     = "release"
 
 	testpwd     = "testpwd"
 	testpwdHash = "a85b6a20813c31a8b1b3f3618da796271c9aa293b3f809873053b21aec501087"
@@ -39,6 +50,8 @@ type (
 	serviceTestSuite struct {
 		repoMock      *mockRepository
 		publisherMock *mockEventPublisher
+		storeMock     *mockEventStore
+		idemMock      *mockIdempotencyStore
 		service       Service
 		suite.Suite
 	}
@@ -51,67 +64,102 @@ func TestServiceTestSuite(t *testing.T) {
 func (s *serviceTestSuite) SetupSuite() {
 	s.repoMock = newMockRepository(s.T())
 	s.publisherMock = newMockEventPublisher(s.T())
+	s.storeMock = newMockEventStore(s.T())
+	s.idemMock = newMockIdempotencyStore(s.T())
 	s.service = Service{
-		repository:     s.repoMock,
-		eventPublisher: s.publisherMock,
+		repository:         s.repoMock,
+		eventPublisher:     s.publisherMock,
+		eventStore:         s.storeMock,
+		streamPollInterval: time.Millisecond,
+		idempotency:        s.idemMock,
+		idempotencyTTL:     time.Hour,
+		idempotencyLease:   time.Minute,
+		fieldChangeEvents:  true,
 	}
+	s.storeMock.
+		On(transaction, mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, change func(context.Context) error) error { return change(ctx) }).
+		Maybe()
+	s.storeMock.On(save, mock.Anything, mock.Anything).Return(nil).Maybe()
 }
 
-func (s serviceTestSuite) TestGet() {
+func (s *serviceTestSuite) TestReplayer_SharesDependencies() {
+	sender := newMockReplaySender(s.T())
+	svc := s.service
+	svc.replaySender = sender
+
+	r := svc.Replayer()
+	s.Equal(s.repoMock, r.repository)
+	s.Equal(s.storeMock, r.eventStore)
+	s.Equal(sender, r.sender)
+}
+
+func (s *serviceTestSuite) TestConnections_SharesConnections() {
+	svc := s.service
+	svc.rmq = &RmqEventPublisher{}
+	svc.db = &gorm.DB{}
+
+	publisher, db := svc.Connections()
+	s.Same(svc.rmq, publisher)
+	s.Same(svc.db, db)
+}
+
+func (s *serviceTestSuite) TestGet() {
 	id := uuid.New()
 	u := User{
 		ID:        id,
 		FirstName: "test",
 	}
 	s.repoMock.
-		On(findByID, mock.Anything, id).
+		On(findByID, mock.Anything, id, Fields(nil)).
 		Return(&u, nil).
 		Once()
 
-	actualUser, err := s.service.Get(nil, id)
+	actualUser, err := s.service.Get(nil, id, nil)
 	s.NoError(err)
 	s.Equal(id, actualUser.ID)
 	s.Equal("test", actualUser.FirstName)
 }
 
-func (s serviceTestSuite) TestGet_ReturnsError() {
+func (s *serviceTestSuite) TestGet_ReturnsError() {
 	id := uuid.New()
 	s.repoMock.
-		On(findByID, mock.Anything, id).
+		On(findByID, mock.Anything, id, Fields(nil)).
 		Return(nil, ErrUserNotFound).
 		Once()
 
-	actualUser, err := s.service.Get(nil, id)
+	actualUser, err := s.service.Get(nil, id, nil)
 	s.Nil(actualUser)
 	s.ErrorIs(err, ErrUserNotFound)
 }
 
-func (s serviceTestSuite) TestGet_ReturnsErrorOnNilUUID() {
-	_, err := s.service.Get(nil, uuid.Nil)
+func (s *serviceTestSuite) TestGet_ReturnsErrorOnNilUUID() {
+	_, err := s.service.Get(nil, uuid.Nil, nil)
 	s.ErrorIs(err, ErrNilUUIDNotAllowed)
 	s.repoMock.AssertNotCalled(s.T(), findByID)
 }
 
-func (s serviceTestSuite) TestList() {
+func (s *serviceTestSuite) TestList() {
 	pag := common.Pagination{Page: 1, PageSize: 2}
 	filter := User{FirstName: "test"}
+	sort := Sort{{Field: "last_name"}, {Field: "created_at", Desc: true}}
 	expectedUsers := []User{
 		{FirstName: "Test", LastName: "LastName"},
 	}
 
 	s.repoMock.
-		On(list, mock.Anything, pag, &filter).
+		On(list, mock.Anything, pag, &filter, sort, Fields(nil)).
 		Return(expectedUsers, nil).
 		Once()
 
-	results, err := s.service.List(nil, pag, &filter)
+	results, err := s.service.List(nil, pag, &filter, sort, nil)
 	s.NoError(err)
 	s.Len(results, 1)
 	s.Equal("Test", results[0].FirstName)
 	s.Equal("LastName", results[0].LastName)
 }
 
-func (s serviceTestSuite) TestList_ReturnsError() {
+func (s *serviceTestSuite) TestList_ReturnsError() {
 	validPagination := common.Pagination{Page: 1, PageSize: 2}
 	changeValidPagination := func(change func(*common.Pagination)) common.Pagination {
 		p := validPagination
@@ -135,6 +183,8 @@ func (s serviceTestSuite) TestList_ReturnsError() {
 		name          string
 		p             common.Pagination
 		filter        User
+		sort          Sort
+		fields        Fields
 		expectedError error
 	}{
 		{
@@ -179,47 +229,109 @@ func (s serviceTestSuite) TestList_ReturnsError() {
 			filter:        changeValidFilter(func(u *User) { u.Country = "x" }),
 			expectedError: ErrInvalidFilter,
 		},
+		{
+			name:          "unknown sort field",
+			p:             validPagination,
+			filter:        validFilter,
+			sort:          Sort{{Field: "password"}},
+			expectedError: ErrInvalidSort,
+		},
+		{
+			name:          "duplicated sort field",
+			p:             validPagination,
+			filter:        validFilter,
+			sort:          Sort{{Field: "email"}, {Field: "email", Desc: true}},
+			expectedError: ErrInvalidSort,
+		},
+		{
+			name:          "unknown field",
+			p:             validPagination,
+			filter:        validFilter,
+			fields:        Fields{"id", "password"},
+			expectedError: ErrInvalidFields,
+		},
 	} {
 		s.Run(test.name, func() {
-			_, err := s.service.List(nil, test.p, &test.filter)
+			_, err := s.service.List(nil, test.p, &test.filter, test.sort, test.fields)
 			s.ErrorIs(err, test.expectedError)
 			s.repoMock.AssertNotCalled(s.T(), list)
 		})
 	}
 }
 
-func (s serviceTestSuite) TestDelete() {
+func (s *serviceTestSuite) TestListAfter() {
+	afterID := uuid.New()
+	users := []User{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}}
+	s.repoMock.
+		On(listAfterID, mock.Anything, afterID, 3).
+		Return(users, nil).
+		Once()
+	s.repoMock.
+		On(listAfterID, mock.Anything, afterID, 4).
+		Return(users, nil).
+		Once()
+
+	results, more, err := s.service.ListAfter(nil, afterID, 2)
+	s.NoError(err)
+	s.True(more)
+	s.Equal(users[:2], results)
+
+	results, more, err = s.service.ListAfter(nil, afterID, 3)
+	s.NoError(err)
+	s.False(more)
+	s.Equal(users, results)
+}
+
+func (s *serviceTestSuite) TestListAfter_ReturnsErrorOnInvalidLimit() {
+	for _, limit := range []int{0, 101} {
+		_, _, err := s.service.ListAfter(nil, uuid.Nil, limit)
+		s.ErrorIs(err, ErrInvalidPagination)
+	}
+}
+
+func (s *serviceTestSuite) TestDelete() {
 	id := uuid.New()
 	s.repoMock.
 		On(deleteByID, mock.Anything, id).
-		Return(nil).
+		Return(int64(3), nil).
 		Once()
 	s.publisherMock.
-		On(publishDeleted, mock.Anything, id).
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypeDeleted, id, 3, nil))).
 		Return(nil).
 		Once()
 
 	s.NoError(s.service.Delete(nil, id))
 }
 
-func (s serviceTestSuite) TestDelete_ReturnsError() {
+func (s *serviceTestSuite) TestDelete_ReturnsError() {
 	id := uuid.New()
 	s.repoMock.
 		On(deleteByID, mock.Anything, id).
-		Return(errors.New("any error")).
+		Return(int64(0), errors.New("any error")).
 		Once()
 
 	s.Error(s.service.Delete(nil, id))
-	s.publisherMock.AssertNotCalled(s.T(), publishDeleted)
+	s.publisherMock.AssertNotCalled(s.T(), publish, mock.Anything, anyEventOf(UserEventTypeDeleted, id))
 }
 
-func (s serviceTestSuite) TestDelete_ReturnsErrorOnNilUUID() {
+func (s *serviceTestSuite) TestDelete_ReturnsErrorOnMissingUser() {
+	id := uuid.New()
+	s.repoMock.
+		On(deleteByID, mock.Anything, id).
+		Return(int64(0), ErrUserNotFound).
+		Once()
+
+	s.ErrorIs(s.service.Delete(nil, id), ErrUserNotFound)
+	s.publisherMock.AssertNotCalled(s.T(), publish, mock.Anything, anyEventOf(UserEventTypeDeleted, id))
+}
+
+func (s *serviceTestSuite) TestDelete_ReturnsErrorOnNilUUID() {
 	err := s.service.Delete(nil, uuid.Nil)
 	s.ErrorIs(err, ErrNilUUIDNotAllowed)
 	s.repoMock.AssertNotCalled(s.T(), deleteByID)
 }
 
-func (s serviceTestSuite) TestCreate() {
+func (s *serviceTestSuite) TestCreate() {
 	userIn := validUser
 	userIn.Country = "us"
 	createdUser := &User{ID: uuid.New(), Nickname: "johndoe", Country: "US"}
@@ -228,7 +340,7 @@ func (s serviceTestSuite) TestCreate() {
 		Return(createdUser, nil).
 		Once()
 	s.publisherMock.
-		On(publishCreated, mock.Anything, createdUser.ID, createdUser).
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypeCreated, createdUser.ID, createdUser.Version, createdUser))).
 		Return(nil).
 		Once()
 
@@ -237,7 +349,7 @@ func (s serviceTestSuite) TestCreate() {
 	s.Equal(createdUser.ID, newUser.ID)
 }
 
-func (s serviceTestSuite) TestCreate_ReturnsError() {
+func (s *serviceTestSuite) TestCreate_ReturnsError() {
 	s.repoMock.
 		On(create, mock.Anything, validUser, mock.Anything).
 		Return(nil, errors.New("any error")).
@@ -245,10 +357,76 @@ func (s serviceTestSuite) TestCreate_ReturnsError() {
 
 	_, err := s.service.Create(nil, validUser, "")
 	s.Error(err)
-	s.publisherMock.AssertNotCalled(s.T(), publishCreated)
+	s.publisherMock.AssertNotCalled(s.T(), publish)
+}
+
+func (s *serviceTestSuite) TestCreate_StoresEventInTransaction() {
+	repoMock := newMockRepository(s.T())
+	publisherMock := newMockEventPublisher(s.T())
+	storeMock := newMockEventStore(s.T())
+	service := Service{repository: repoMock, eventPublisher: publisherMock, eventStore: storeMock}
+
+	createdUser := &User{ID: uuid.New(), Nickname: "johndoe", Version: 1}
+	tx := context.WithValue(context.TODO(), transactionKey{}, "tx")
+	committed := false
+	storeMock.
+		On(transaction, mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, change func(context.Context) error) error {
+			err := change(tx)
+			committed = err == nil
+			return err
+		}).
+		Once()
+	repoMock.
+		On(create, tx, validUser, testpwdHash).
+		Return(createdUser, nil).
+		Once()
+	var stored []UserEvent
+	storeMock.
+		On(save, tx, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).([]UserEvent) }).
+		Return(nil).
+		Once()
+	publisherMock.
+		On(publish, mock.Anything, mock.MatchedBy(func(e UserEvent) bool {
+			// the stored event is published after the commit
+			return committed && len(stored) == 1 && e.EventID == stored[0].EventID
+		})).
+		Return(nil).
+		Once()
+
+	_, err := service.Create(context.TODO(), validUser, testpwd)
+	s.NoError(err)
+	s.Equal(UserEventTypeCreated, stored[0].Type)
+	s.Equal(createdUser.ID, stored[0].UserID)
+}
+
+func (s *serviceTestSuite) TestCreate_ReturnsErrorWhenEventIsntStored() {
+	repoMock := newMockRepository(s.T())
+	publisherMock := newMockEventPublisher(s.T())
+	storeMock := newMockEventStore(s.T())
+	service := Service{repository: repoMock, eventPublisher: publisherMock, eventStore: storeMock}
+
+	storeMock.
+		On(transaction, mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, change func(context.Context) error) error { return change(ctx) }).
+		Once()
+	repoMock.
+		On(create, mock.Anything, validUser, testpwdHash).
+		Return(&User{ID: uuid.New()}, nil).
+		Once()
+	storeMock.
+		On(save, mock.Anything, mock.Anything).
+		Return(errors.New("insert failed")).
+		Once()
+
+	// the user change is rolled back with its event, so there is nothing to publish
+	_, err := service.Create(context.TODO(), validUser, testpwd)
+	s.ErrorContains(err, "insert failed")
+	publisherMock.AssertNotCalled(s.T(), publish, mock.Anything, mock.Anything)
 }
 
-func (s serviceTestSuite) TestCreate_ReturnsErrorOnUserValidation() {
+func (s *serviceTestSuite) TestCreate_ReturnsErrorOnUserValidation() {
 	makeInvalidUser := func(change func(*User)) User {
 		changeUser := validUser
 		change(&changeUser)
@@ -271,19 +449,114 @@ func (s serviceTestSuite) TestCreate_ReturnsErrorOnUserValidation() {
 			userIn := makeInvalidUser(change)
 			_, err := s.service.Create(nil, userIn, "")
 			s.ErrorIs(err, expectedError)
-			s.repoMock.AssertNotCalled(s.T(), publishCreated)
+			s.publisherMock.AssertNotCalled(s.T(), publish)
 		})
 	}
 }
 
-func (s serviceTestSuite) TestUpdate_OnlyUser() {
+func (s *serviceTestSuite) TestCreateIdempotent() {
+	key := uuid.NewString()
+	hash := createRequestHash(validUser, testpwd)
+	createdUser := &User{ID: uuid.New(), Nickname: "johndoe", Country: "US"}
+	s.idemMock.
+		On(reserve, mock.Anything, key, hash, mock.Anything).
+		Return(&IdempotencyRecord{Key: key, RequestHash: hash}, true, nil).
+		Once()
+	s.repoMock.
+		On(create, mock.Anything, validUser, testpwdHash).
+		Return(createdUser, nil).
+		Once()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypeCreated, createdUser.ID, createdUser.Version, createdUser))).
+		Return(nil).
+		Once()
+	s.idemMock.
+		On(complete, mock.Anything, key, createdUser, mock.Anything).
+		Return(nil).
+		Once()
+
+	newUser, replayed, err := s.service.CreateIdempotent(nil, key, validUser, testpwd)
+	s.NoError(err)
+	s.False(replayed)
+	s.Equal(createdUser.ID, newUser.ID)
+}
+
+func (s *serviceTestSuite) TestCreateIdempotent_ReplaysResponse() {
+	key := uuid.NewString()
+	hash := createRequestHash(validUser, testpwd)
+	createdUser := &User{ID: uuid.New(), Nickname: "johndoe", Country: "US"}
+	s.idemMock.
+		On(reserve, mock.Anything, key, hash, mock.Anything).
+		Return(&IdempotencyRecord{Key: key, RequestHash: hash, Response: createdUser}, false, nil).
+		Once()
+
+	newUser, replayed, err := s.service.CreateIdempotent(nil, key, validUser, testpwd)
+	s.NoError(err)
+	s.True(replayed)
+	s.Equal(createdUser.ID, newUser.ID)
+}
+
+func (s *serviceTestSuite) TestCreateIdempotent_ReturnsErrorOnUsedKey() {
+	hash := createRequestHash(validUser, testpwd)
+	for _, test := range []struct {
+		name          string
+		record        IdempotencyRecord
+		expectedError error
+	}{
+		{
+			name:          "different request",
+			record:        IdempotencyRecord{RequestHash: "other", Response: &User{ID: uuid.New()}},
+			expectedError: ErrIdempotencyKeyReused,
+		},
+		{
+			name:          "request in progress",
+			record:        IdempotencyRecord{RequestHash: hash},
+			expectedError: ErrIdempotencyKeyInProgress,
+		},
+	} {
+		s.Run(test.name, func() {
+			key := uuid.NewString()
+			s.idemMock.
+				On(reserve, mock.Anything, key, hash, mock.Anything).
+				Return(&test.record, false, nil).
+				Once()
+
+			_, _, err := s.service.CreateIdempotent(nil, key, validUser, testpwd)
+			s.ErrorIs(err, test.expectedError)
+		})
+	}
+}
+
+func (s *serviceTestSuite) TestCreateIdempotent_ReleasesKeyOnError() {
+	key := uuid.NewString()
+	userIn := validUser
+	userIn.Nickname = "x"
+	s.idemMock.
+		On(reserve, mock.Anything, key, mock.Anything, mock.Anything).
+		Return(&IdempotencyRecord{Key: key}, true, nil).
+		Once()
+	s.idemMock.
+		On(release, mock.MatchedBy(func(c context.Context) bool {
+			// the key is released even if the request was canceled
+			return c.Err() == nil
+		}), key).
+		Return(nil).
+		Once()
+
+	canceled, cancel := context.WithCancel(context.TODO())
+	cancel()
+	_, _, err := s.service.CreateIdempotent(canceled, key, userIn, testpwd)
+	s.ErrorIs(err, ErrInvalidUserInputData)
+}
+
+func (s *serviceTestSuite) TestUpdate_OnlyUser() {
 	id := uuid.New()
 	s.repoMock.
 		On(update, mock.Anything, id, validUser).
-		Return(&validUser, nil).
+		Return(&validUser, &validUser, nil).
 		Once()
 	s.publisherMock.
-		On(publishUpdated, mock.Anything, id, &validUser).
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypeUpdated, id, validUser.Version, &validUser))).
 		Return(nil).
 		Once()
 
@@ -291,17 +564,17 @@ func (s serviceTestSuite) TestUpdate_OnlyUser() {
 	s.NoError(err)
 	s.Equal(validUser.Email, updatedUser.Email)
 	s.repoMock.AssertNotCalled(s.T(), updatePass)
-	s.publisherMock.AssertNotCalled(s.T(), publishPasswordChanged)
+	s.publisherMock.AssertNotCalled(s.T(), publish, mock.Anything, anyEventOf(UserEventTypePasswordChanged, id))
 }
 
-func (s serviceTestSuite) TestUpdate_OnlyPassword() {
+func (s *serviceTestSuite) TestUpdate_OnlyPassword() {
 	id := uuid.New()
 	s.repoMock.
 		On(updatePass, mock.Anything, id, testpwdHash).
-		Return(nil).
+		Return(int64(2), nil).
 		Once()
 	s.publisherMock.
-		On(publishPasswordChanged, mock.Anything, id).
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypePasswordChanged, id, 2, nil))).
 		Return(nil).
 		Once()
 
@@ -309,25 +582,25 @@ func (s serviceTestSuite) TestUpdate_OnlyPassword() {
 	s.NoError(err)
 	s.Nil(updatedUser)
 	s.repoMock.AssertNotCalled(s.T(), update)
-	s.publisherMock.AssertNotCalled(s.T(), publishUpdated)
+	s.publisherMock.AssertNotCalled(s.T(), publish, mock.Anything, anyEventOf(UserEventTypeUpdated, id))
 }
 
-func (s serviceTestSuite) TestUpdate_UserAndPassword() {
+func (s *serviceTestSuite) TestUpdate_UserAndPassword() {
 	id := uuid.New()
 	s.repoMock.
 		On(update, mock.Anything, id, validUser).
-		Return(&validUser, nil).
+		Return(&validUser, &validUser, nil).
 		Once()
 	s.publisherMock.
-		On(publishUpdated, mock.Anything, id, &validUser).
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypeUpdated, id, validUser.Version, &validUser))).
 		Return(nil).
 		Once()
 	s.repoMock.
 		On(updatePass, mock.Anything, id, testpwdHash).
-		Return(nil).
+		Return(int64(2), nil).
 		Once()
 	s.publisherMock.
-		On(publishPasswordChanged, mock.Anything, id).
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypePasswordChanged, id, 2, nil))).
 		Return(nil).
 		Once()
 
@@ -336,11 +609,48 @@ func (s serviceTestSuite) TestUpdate_UserAndPassword() {
 	s.Equal(validUser.Email, newUser.Email)
 }
 
-func (s serviceTestSuite) TestUpdate_RetrurnError_WhenChangesUser() {
+func (s *serviceTestSuite) TestUpdate_PublishesFieldChanges() {
+	id := uuid.New()
+	previousUser := validUser
+	previousUser.Version = 1
+	updatedUser := validUser
+	updatedUser.Email = "new@email.com"
+	updatedUser.Country = "UK"
+	updatedUser.Version = 2
+	change := User{Email: "new@email.com", Country: "uk"}
+
+	s.repoMock.
+		On(update, mock.Anything, id, User{Email: "new@email.com", Country: "UK"}).
+		Return(&updatedUser, &previousUser, nil).
+		Twice()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypeUpdated, id, updatedUser.Version, &updatedUser))).
+		Return(nil).
+		Twice()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newFieldChangeEvent(UserEventTypeEmailChanged, id, 2, FieldChange{Old: "johndoe@email.com", New: "new@email.com"}))).
+		Return(nil).
+		Once()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newFieldChangeEvent(UserEventTypeCountryChanged, id, 2, FieldChange{Old: "US", New: "UK"}))).
+		Return(nil).
+		Once()
+
+	_, err := s.service.Update(nil, id, change, "")
+	s.NoError(err)
+
+	// the specific events are not published when they are turned off
+	disabled := s.service
+	disabled.fieldChangeEvents = false
+	_, err = disabled.Update(nil, id, change, "")
+	s.NoError(err)
+}
+
+func (s *serviceTestSuite) TestUpdate_RetrurnError_WhenChangesUser() {
 	id := uuid.New()
 	s.repoMock.
 		On(update, mock.Anything, id, validUser).
-		Return(nil, errors.New("user change error")).
+		Return(nil, nil, errors.New("user change error")).
 		Once()
 
 	_, err := s.service.Update(nil, id, validUser, "")
@@ -348,11 +658,11 @@ func (s serviceTestSuite) TestUpdate_RetrurnError_WhenChangesUser() {
 	s.repoMock.AssertNotCalled(s.T(), updatePass)
 }
 
-func (s serviceTestSuite) TestUpdate_RetrurnError_WhenChangesPassword() {
+func (s *serviceTestSuite) TestUpdate_RetrurnError_WhenChangesPassword() {
 	id := uuid.New()
 	s.repoMock.
 		On(updatePass, mock.Anything, id, testpwdHash).
-		Return(errors.New("password change error")).
+		Return(int64(0), errors.New("password change error")).
 		Once()
 
 	_, err := s.service.Update(nil, id, User{}, testpwd)
@@ -360,7 +670,7 @@ func (s serviceTestSuite) TestUpdate_RetrurnError_WhenChangesPassword() {
 	s.repoMock.AssertNotCalled(s.T(), update)
 }
 
-func (s serviceTestSuite) TestUpdate_RetrurnErrorOnUserValidation() {
+func (s *serviceTestSuite) TestUpdate_RetrurnErrorOnUserValidation() {
 	makeInvalidUser := func(change func(*User)) User {
 		changeUser := validUser
 		change(&changeUser)
@@ -384,9 +694,323 @@ func (s serviceTestSuite) TestUpdate_RetrurnErrorOnUserValidation() {
 	}
 }
 
-func (s serviceTestSuite) TestUpdate_RetrurnErrorOnNilUUID() {
+func (s *serviceTestSuite) TestUpdate_RetrurnErrorOnNilUUID() {
 	_, err := s.service.Update(nil, uuid.Nil, User{}, "")
 	s.ErrorIs(err, ErrNilUUIDNotAllowed)
 	s.repoMock.AssertNotCalled(s.T(), update)
 	s.repoMock.AssertNotCalled(s.T(), updatePass)
 }
+
+func (s *serviceTestSuite) TestReplace() {
+	id := uuid.New()
+	replacement := validUser
+	replacement.Country = "uk"
+	previousUser := validUser
+	previousUser.Version = 1
+	savedUser := validUser
+	savedUser.Country = "UK"
+	savedUser.Version = 2
+	s.repoMock.
+		On(replace, mock.Anything, id, User{FirstName: "john", LastName: "doe", Nickname: "johndoe", Email: "johndoe@email.com", Country: "UK"}, int64(0)).
+		Return(&savedUser, &previousUser, nil).
+		Once()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypeUpdated, id, savedUser.Version, &savedUser))).
+		Return(nil).
+		Once()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newFieldChangeEvent(UserEventTypeCountryChanged, id, 2, FieldChange{Old: "US", New: "UK"}))).
+		Return(nil).
+		Once()
+
+	updatedUser, err := s.service.Replace(nil, id, replacement, "")
+	s.NoError(err)
+	s.Equal("UK", updatedUser.Country)
+}
+
+func (s *serviceTestSuite) TestReplace_ReturnsErrorOnUserValidation() {
+	// every field is required by a replace
+	_, err := s.service.Replace(nil, uuid.New(), User{Nickname: "johndoe"}, "")
+	s.ErrorIs(err, ErrInvalidUserInputData)
+
+	_, err = s.service.Replace(nil, uuid.Nil, validUser, "")
+	s.ErrorIs(err, ErrNilUUIDNotAllowed)
+}
+
+func (s *serviceTestSuite) TestPatch() {
+	id := uuid.New()
+	current := validUser
+	current.ID = id
+	current.Version = 3
+	patchedUser := validUser
+	patchedUser.Nickname = "johnny"
+	savedUser := patchedUser
+	savedUser.Version = 4
+	s.repoMock.
+		On(findByID, mock.Anything, id, Fields(nil)).
+		Return(&current, nil).
+		Once()
+	s.repoMock.
+		On(replace, mock.Anything, id, patchedUser, int64(3)).
+		Return(&savedUser, &current, nil).
+		Once()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypeUpdated, id, savedUser.Version, &savedUser))).
+		Return(nil).
+		Once()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newFieldChangeEvent(UserEventTypeNicknameChanged, id, 4, FieldChange{Old: "johndoe", New: "johnny"}))).
+		Return(nil).
+		Once()
+	s.repoMock.
+		On(updatePass, mock.Anything, id, testpwdHash).
+		Return(int64(5), nil).
+		Once()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypePasswordChanged, id, 5, nil))).
+		Return(nil).
+		Once()
+
+	updatedUser, err := s.service.Patch(nil, id, PatchTypeJSON, []byte(`[
+		{"op": "test", "path": "/nickname", "value": "johndoe"},
+		{"op": "replace", "path": "/nickname", "value": "johnny"},
+		{"op": "add", "path": "/password", "value": "testpwd"}
+	]`))
+	s.NoError(err)
+	s.Equal("johnny", updatedUser.Nickname)
+}
+
+func (s *serviceTestSuite) TestPatch_SkipsUpdateOfUnchangedUser() {
+	id := uuid.New()
+	current := validUser
+	current.ID = id
+	current.Version = 3
+	s.repoMock.
+		On(findByID, mock.Anything, id, Fields(nil)).
+		Return(&current, nil).
+		Once()
+	s.repoMock.
+		On(replace, mock.Anything, id, validUser, int64(3)).
+		Return(&current, &current, nil).
+		Once()
+	s.repoMock.
+		On(updatePass, mock.Anything, id, testpwdHash).
+		Return(int64(4), nil).
+		Once()
+	s.publisherMock.
+		On(publish, mock.Anything, eventLike(newUserEvent(UserEventTypePasswordChanged, id, 4, nil))).
+		Return(nil).
+		Once()
+
+	updatedUser, err := s.service.Patch(nil, id, PatchTypeMerge, []byte(`{"password": "testpwd"}`))
+	s.NoError(err)
+	s.Equal(int64(3), updatedUser.Version)
+	s.publisherMock.AssertNotCalled(s.T(), publish, mock.Anything, anyEventOf(UserEventTypeUpdated, id))
+}
+
+func (s *serviceTestSuite) TestPatch_ReturnsError() {
+	id := uuid.New()
+	current := validUser
+	current.ID = id
+	s.repoMock.
+		On(findByID, mock.Anything, id, Fields(nil)).
+		Return(&current, nil).
+		Twice()
+
+	_, err := s.service.Patch(nil, id, PatchTypeJSON, []byte(`[{"op": "test", "path": "/nickname", "value": "janedoe"}]`))
+	s.ErrorIs(err, ErrPatchTestFailed)
+
+	// the patched user is validated as a whole
+	_, err = s.service.Patch(nil, id, PatchTypeMerge, []byte(`{"email": null}`))
+	s.ErrorIs(err, ErrInvalidUserInputData)
+	s.repoMock.AssertNotCalled(s.T(), replace)
+
+	missingID := uuid.New()
+	s.repoMock.
+		On(findByID, mock.Anything, missingID, Fields(nil)).
+		Return(nil, ErrUserNotFound).
+		Once()
+	_, err = s.service.Patch(nil, missingID, PatchTypeMerge, []byte(`{}`))
+	s.ErrorIs(err, ErrUserNotFound)
+}
+
+func (s *serviceTestSuite) TestStreamEvents_FromLastEvent() {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	filter := EventFilter{Types: []UserEventType{UserEventTypeCreated}}
+	events := []StoredEvent{
+		{ID: 6, UserEvent: UserEvent{Type: UserEventTypeCreated}},
+		{ID: 8, UserEvent: UserEvent{Type: UserEventTypeCreated}},
+	}
+	s.storeMock.On(lastID, ctx).Return(int64(5), nil).Once()
+	s.storeMock.On(changed).Return(nil).Twice()
+	s.storeMock.On(listAfter, ctx, int64(5), filter, streamBatchSize).Return(events, nil).Once()
+	s.storeMock.On(listAfter, ctx, int64(8), filter, streamBatchSize).Return(nil, nil).Once()
+
+	var received []*StoredEvent
+	err := s.service.StreamEvents(ctx, nil, filter, func(event *StoredEvent) error {
+		received = append(received, event)
+		if event == nil {
+			cancel()
+		}
+		return nil
+	})
+	s.NoError(err)
+	s.Equal([]*StoredEvent{&events[0], &events[1], nil}, received)
+}
+
+func (s *serviceTestSuite) TestStreamEvents_ResumesAfterLastEventID() {
+	errSend := errors.New("connection closed")
+	event := StoredEvent{ID: 11}
+	s.storeMock.On(changed).Return(nil).Once()
+	s.storeMock.On(listAfter, mock.Anything, int64(10), EventFilter{}, streamBatchSize).Return([]StoredEvent{event}, nil).Once()
+
+	err := s.service.StreamEvents(context.TODO(), common.Ptr(int64(10)), EventFilter{}, func(*StoredEvent) error {
+		return errSend
+	})
+	s.ErrorIs(err, errSend)
+}
+
+func (s *serviceTestSuite) TestStreamEvents_EndsOnCloseStreams() {
+	streams, closeStreams := context.WithCancel(context.TODO())
+	svc := s.service
+	svc.streams = streams
+	svc.closeStreams = closeStreams
+	s.storeMock.On(changed).Return(nil).Once()
+	s.storeMock.On(listAfter, mock.Anything, int64(10), EventFilter{}, streamBatchSize).Return(nil, nil).Once()
+
+	// the request context is never canceled, like a connected client
+	err := svc.StreamEvents(context.TODO(), common.Ptr(int64(10)), EventFilter{}, func(*StoredEvent) error {
+		svc.CloseStreams()
+		return nil
+	})
+	s.NoError(err)
+}
+
+func (s *serviceTestSuite) TestStreamEvents_ProjectsEvents() {
+	u := validUser
+	emailChanged := StoredEvent{ID: 11, UserEvent: newUserEvent(UserEventTypeEmailChanged, uuid.New(), 2, nil)}
+	emailChanged.Change = &FieldChange{Old: "old@email.com", New: "new@email.com"}
+	created := StoredEvent{ID: 12, UserEvent: newUserEvent(UserEventTypeCreated, emailChanged.UserID, 1, &u)}
+	svc := s.service
+	svc.projection = piiFreeProjection
+	s.storeMock.On(changed).Return(nil).Once()
+	s.storeMock.On(listAfter, mock.Anything, int64(10), EventFilter{}, streamBatchSize).Return([]StoredEvent{emailChanged, created}, nil).Once()
+
+	var received []StoredEvent
+	err := svc.StreamEvents(context.TODO(), common.Ptr(int64(10)), EventFilter{}, func(event *StoredEvent) error {
+		received = append(received, *event)
+		return errors.New("connection closed")
+	})
+	s.Error(err)
+	s.Require().Len(received, 1, "the change events of the omitted fields are skipped")
+	s.Empty(received[0].UserChanges.Email)
+	s.Equal(u.Country, received[0].UserChanges.Country)
+	s.Equal(validUser.Email, created.UserChanges.Email, "the stored event must not be changed")
+}
+
+func (s *serviceTestSuite) TestStreamEvents_ReturnsErrorOnInvalidFilter() {
+	err := s.service.StreamEvents(context.TODO(), nil, EventFilter{Types: []UserEventType{"USER_LOGGED_IN"}}, nil)
+	s.ErrorIs(err, ErrInvalidFilter)
+}
+
+func (s *serviceTestSuite) TestSearch() {
+	pag := common.Pagination{Page: 1, PageSize: 2}
+	filter := Filter{Or: []Filter{
+		{Field: "country", Op: FilterIn, Value: []interface{}{"UK", "US"}},
+		{Field: "email", Op: FilterContains, Value: "doe"},
+	}}
+	expectedUsers := []User{{Email: "janedoe@email.com"}}
+
+	s.repoMock.
+		On(search, mock.Anything, pag, filter).
+		Return(expectedUsers, nil).
+		Once()
+
+	results, err := s.service.Search(nil, pag, filter)
+	s.NoError(err)
+	s.Equal(expectedUsers, results)
+}
+
+func (s *serviceTestSuite) TestSearch_ReturnsError() {
+	validFilter := Filter{Field: "country", Op: FilterEq, Value: "UK"}
+
+	for _, test := range []struct {
+		name          string
+		p             common.Pagination
+		filter        Filter
+		expectedError error
+	}{
+		{
+			name:          "invalid pagination",
+			p:             common.Pagination{Page: -1},
+			filter:        validFilter,
+			expectedError: ErrInvalidPagination,
+		},
+		{
+			name:          "invalid filter",
+			p:             common.Pagination{Page: 1, PageSize: 2},
+			filter:        Filter{Field: "password", Op: FilterEq, Value: "x"},
+			expectedError: ErrInvalidFilter,
+		},
+	} {
+		s.Run(test.name, func() {
+			_, err := s.service.Search(nil, test.p, test.filter)
+			s.ErrorIs(err, test.expectedError)
+			s.repoMock.AssertNotCalled(s.T(), search)
+		})
+	}
+}
+
+func (s *serviceTestSuite) TestBatchGet() {
+	first, second, missing := uuid.New(), uuid.New(), uuid.New()
+	s.repoMock.
+		On(findByIDs, mock.Anything, []uuid.UUID{second, missing, first}, Fields{"nickname"}).
+		Return([]User{{ID: first, Nickname: "first"}, {ID: second, Nickname: "second"}}, nil).
+		Once()
+
+	users, missingIDs, err := s.service.BatchGet(nil, []uuid.UUID{second, missing, first, second}, Fields{"nickname"})
+	s.NoError(err)
+	s.Equal([]User{{ID: second, Nickname: "second"}, {ID: first, Nickname: "first"}}, users)
+	s.Equal([]uuid.UUID{missing}, missingIDs)
+}
+
+func (s *serviceTestSuite) TestBatchGet_ReturnsError() {
+	tooMany := make([]uuid.UUID, maxBatchGetSize+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	for _, test := range []struct {
+		name          string
+		ids           []uuid.UUID
+		fields        Fields
+		expectedError error
+	}{
+		{name: "no ids", expectedError: ErrInvalidIDs},
+		{name: "too many ids", ids: tooMany, expectedError: ErrInvalidIDs},
+		{name: "nil id", ids: []uuid.UUID{uuid.New(), uuid.Nil}, expectedError: ErrInvalidIDs},
+		{name: "unknown field", ids: []uuid.UUID{uuid.New()}, fields: Fields{"password"}, expectedError: ErrInvalidFields},
+	} {
+		s.Run(test.name, func() {
+			_, _, err := s.service.BatchGet(nil, test.ids, test.fields)
+			s.ErrorIs(err, test.expectedError)
+			s.repoMock.AssertNotCalled(s.T(), findByIDs)
+		})
+	}
+}
+
+// eventLike matches the user event what equals to the expected event apart from its id and time
+func eventLike(expected UserEvent) interface{} {
+	return mock.MatchedBy(func(e UserEvent) bool {
+		e.EventID, e.Time = expected.EventID, expected.Time
+		return reflect.DeepEqual(expected, e)
+	})
+}
+
+// anyEventOf matches every event of the type of the user
+func anyEventOf(eventType UserEventType, userID uuid.UUID) interface{} {
+	return mock.MatchedBy(func(e UserEvent) bool {
+		return e.Type == eventType && e.UserID == userID
+	})
+}
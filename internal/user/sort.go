@@ -0,0 +1,91 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// sortColumns are the sortable fields by their JSON name with their column. Only indexed columns are sortable,
+// so a sorted page doesn't need a full table scan.
+var sortColumns = map[string]string{
+	"id":         "id",
+	"last_name":  "last_name",
+	"nickname":   "nickname",
+	"email":      "email",
+	"country":    "country",
+	"created_at": "created_at",
+}
+
+// defaultSort is the order of the listings when no sort is requested
+var defaultSort = Sort{{Field: "created_at", Desc: true}, {Field: "email"}}
+
+type (
+	// SortField is a sorted field of the user listings, it's sorted ascending unless Desc is set
+	SortField struct {
+		Field string
+		Desc  bool
+	}
+
+	// Sort is the order of the user listings, the first field is the primary order
+	Sort []SortField
+)
+
+// ParseSort parses the comma separated field names, the fields prefixed by `-` are sorted descending,
+// i.e. `last_name,-created_at`.
+func ParseSort(sort string) Sort {
+	var parsed Sort
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		switch {
+		case strings.HasPrefix(field, "-"):
+			parsed = append(parsed, SortField{Field: field[1:], Desc: true})
+		case strings.HasPrefix(field, "+"):
+			parsed = append(parsed, SortField{Field: field[1:]})
+		default:
+			parsed = append(parsed, SortField{Field: field})
+		}
+	}
+	return parsed
+}
+
+// Validate checks that every field is sortable and sorted only once.
+func (s Sort) Validate() error {
+	sorted := map[string]bool{}
+	for _, f := range s {
+		if _, ok := sortColumns[f.Field]; !ok {
+			return fmt.Errorf("unknown sort field %q", f.Field)
+		}
+		if sorted[f.Field] {
+			return fmt.Errorf("duplicated sort field %s", f.Field)
+		}
+		sorted[f.Field] = true
+	}
+	return nil
+}
+
+// orderBy returns the order of the validated sort, or the default order when it's empty.
+// The id is added as the last order, so the users with the same sorted values are always paged in the same order.
+func (s Sort) orderBy() clause.OrderBy {
+	if len(s) == 0 {
+		s = defaultSort
+	}
+
+	var columns []clause.OrderByColumn
+	tiebreaker := true
+	for _, f := range s {
+		column := sortColumns[f.Field]
+		columns = append(columns, clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: column},
+			Desc:   f.Desc,
+		})
+		if column == "id" {
+			tiebreaker = false
+		}
+	}
+	if tiebreaker {
+		columns = append(columns, clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: "id"}})
+	}
+	return clause.OrderBy{Columns: columns}
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type (
+	sortTestSuite struct {
+		db *gorm.DB
+		suite.Suite
+	}
+)
+
+func TestSortTestSuite(t *testing.T) {
+	suite.Run(t, new(sortTestSuite))
+}
+
+func (s *sortTestSuite) SetupSuite() {
+	// the queries are only built, so the database is not connected
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	s.Require().NoError(err)
+	s.db = db
+}
+
+func (s *sortTestSuite) TestParseSort() {
+	s.Equal(Sort{{Field: "last_name"}, {Field: "created_at", Desc: true}, {Field: "email"}}, ParseSort("last_name, -created_at,+email"))
+	s.NoError(ParseSort("last_name,-created_at").Validate())
+}
+
+func (s *sortTestSuite) TestValidate_ReturnsError() {
+	for _, sort := range []string{"", "password", "first_name", "email,", "-", "email,-email", "LAST_NAME"} {
+		s.Run(sort, func() {
+			s.Error(ParseSort(sort).Validate())
+		})
+	}
+}
+
+func (s *sortTestSuite) TestOrderBy() {
+	for _, test := range []struct {
+		name        string
+		sort        Sort
+		expectedSQL string
+	}{
+		{
+			name:        "default",
+			expectedSQL: `"users"."created_at" DESC,"users"."email","users"."id"`,
+		},
+		{
+			name:        "id tiebreaker",
+			sort:        Sort{{Field: "last_name"}, {Field: "created_at", Desc: true}},
+			expectedSQL: `"users"."last_name","users"."created_at" DESC,"users"."id"`,
+		},
+		{
+			name:        "sorted by id",
+			sort:        Sort{{Field: "id", Desc: true}},
+			expectedSQL: `"users"."id" DESC`,
+		},
+	} {
+		s.Run(test.name, func() {
+			stmt := s.db.Clauses(test.sort.orderBy()).Find(&[]User{}).Statement
+			s.Equal(`SELECT * FROM "users" ORDER BY `+test.expectedSQL, stmt.SQL.String())
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package user
+
+import (
+	"errors"
+	"faceit/internal/common"
+	"fmt"
+	"os"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidTopology = errors.New("invalid RabbitMQ topology")
+
+var exchangeTypes = map[string]bool{
+	amqp.ExchangeDirect:  true,
+	amqp.ExchangeFanout:  true,
+	amqp.ExchangeTopic:   true,
+	amqp.ExchangeHeaders: true,
+}
+
+type (
+	// Topology is the set of the RabbitMQ exchanges what the user service publishes to.
+	// Every exchange is durable, and the main and the event type exchanges route the unroutable messages
+	// to the alternate exchange when it's set. The dead-letter exchange is declared for the consumer queues,
+	// what could set it as their `x-dead-letter-exchange`.
+	Topology struct {
+		// Exchange receives the user events (default `events.user` topic exchange)
+		Exchange ExchangeConfig `yaml:"exchange"`
+		// AlternateExchange receives the messages what were not routed to any queue
+		AlternateExchange *ExchangeConfig `yaml:"alternate_exchange"`
+		// DeadLetterExchange receives the messages what were rejected by the consumers
+		DeadLetterExchange *ExchangeConfig `yaml:"dead_letter_exchange"`
+		// PIIFreeExchange receives the user events without personal data
+		PIIFreeExchange *ExchangeConfig `yaml:"pii_free_exchange"`
+		// EventExchanges receive the events of their type instead of the main exchange
+		EventExchanges map[UserEventType]ExchangeConfig `yaml:"event_exchanges"`
+	}
+
+	// ExchangeConfig is a durable exchange with an optional queue bound to it with `#` binding key
+	ExchangeConfig struct {
+		Name  string `yaml:"name"`
+		Type  string `yaml:"type"`
+		Queue string `yaml:"queue"`
+	}
+
+	// topologyChannel declares and verifies the exchanges and queues of the topology
+	topologyChannel interface {
+		ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+		ExchangeDeclarePassive(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+		QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+		QueueDeclarePassive(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+		QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+		Close() error
+	}
+
+	// topologyCheck is a passive declare of an exchange or a queue
+	topologyCheck struct {
+		missing string
+		check   func(ch topologyChannel) error
+	}
+)
+
+// NewTopology loads the topology from the RMQ_TOPOLOGY_FILE YAML file. When it's not set, the topology
+// has only the USER_EVENT_EXCHANGE exchange and the EVENT_PII_FREE_EXCHANGE exchange when it's set.
+func NewTopology() (Topology, error) {
+	if path := common.GetEnv("RMQ_TOPOLOGY_FILE", ""); path != "" {
+		return LoadTopology(path)
+	}
+
+	t := Topology{Exchange: ExchangeConfig{Name: common.GetEnv("USER_EVENT_EXCHANGE", "events.user")}}
+	if name := common.GetEnv("EVENT_PII_FREE_EXCHANGE", ""); name != "" {
+		t.PIIFreeExchange = &ExchangeConfig{Name: name}
+	}
+	return t, t.validate()
+}
+
+// LoadTopology reads the topology from a YAML file, the exchanges are topic exchanges unless their type is set.
+func LoadTopology(path string) (Topology, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Topology{}, err
+	}
+
+	var t Topology
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return Topology{}, fmt.Errorf("%w: %s", ErrInvalidTopology, err.Error())
+	}
+	return t, t.validate()
+}
+
+func (t Topology) validate() error {
+	if t.Exchange.Name == "" {
+		return fmt.Errorf("%w: exchange name is required", ErrInvalidTopology)
+	}
+	for eventType := range t.EventExchanges {
+		if !knownEventTypes[eventType] {
+			return fmt.Errorf("%w: unknown event type %s", ErrInvalidTopology, eventType)
+		}
+	}
+
+	names := map[string]bool{}
+	for _, e := range t.exchanges() {
+		switch {
+		case e.Name == "":
+			return fmt.Errorf("%w: exchange name is required", ErrInvalidTopology)
+		case !exchangeTypes[e.kind()]:
+			return fmt.Errorf("%w: unknown type %s of exchange %s", ErrInvalidTopology, e.Type, e.Name)
+		case names[e.Name]:
+			return fmt.Errorf("%w: duplicated exchange %s", ErrInvalidTopology, e.Name)
+		}
+		names[e.Name] = true
+	}
+	return nil
+}
+
+// exchanges returns the alternate exchange first, so it exists when the other exchanges refer to it
+func (t Topology) exchanges() []ExchangeConfig {
+	var exchanges []ExchangeConfig
+	for _, e := range []*ExchangeConfig{t.AlternateExchange, t.DeadLetterExchange, &t.Exchange, t.PIIFreeExchange} {
+		if e != nil {
+			exchanges = append(exchanges, *e)
+		}
+	}
+	for _, eventType := range sortedEventTypes(t.EventExchanges) {
+		exchanges = append(exchanges, t.EventExchanges[eventType])
+	}
+	return exchanges
+}
+
+func sortedEventTypes(exchanges map[UserEventType]ExchangeConfig) []UserEventType {
+	var types []UserEventType
+	for _, eventType := range []UserEventType{
+		UserEventTypeCreated,
+		UserEventTypeUpdated,
+		UserEventTypePasswordChanged,
+		UserEventTypeDeleted,
+		UserEventTypeEmailChanged,
+		UserEventTypeNicknameChanged,
+		UserEventTypeCountryChanged,
+	} {
+		if _, ok := exchanges[eventType]; ok {
+			types = append(types, eventType)
+		}
+	}
+	return types
+}
+
+// exchangeFor returns the exchange of the event type, or the main exchange when the type has no own exchange
+func (t Topology) exchangeFor(eventType UserEventType) string {
+	if e, ok := t.EventExchanges[eventType]; ok {
+		return e.Name
+	}
+	return t.Exchange.Name
+}
+
+// arguments sets the alternate exchange of the exchanges what receive the events
+func (t Topology) arguments(e ExchangeConfig) amqp.Table {
+	if t.AlternateExchange == nil || e.Name == t.AlternateExchange.Name ||
+		(t.DeadLetterExchange != nil && e.Name == t.DeadLetterExchange.Name) {
+		return nil
+	}
+	return amqp.Table{"alternate-exchange": t.AlternateExchange.Name}
+}
+
+// declare creates the exchanges and the queues of the topology
+func (t Topology) declare(ch topologyChannel) error {
+	for _, e := range t.exchanges() {
+		if err := ch.ExchangeDeclare(e.Name, e.kind(), true, false, false, false, t.arguments(e)); err != nil {
+			return fmt.Errorf("failed to declare exchange %s: %w", e.Name, err)
+		}
+		if e.Queue == "" {
+			continue
+		}
+		if _, err := ch.QueueDeclare(e.Queue, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", e.Queue, err)
+		}
+		if err := ch.QueueBind(e.Queue, "#", e.Name, false, nil); err != nil {
+			return fmt.Errorf("failed to bind queue %s: %w", e.Queue, err)
+		}
+	}
+	return nil
+}
+
+// verify checks the exchanges and the queues of the topology with passive declares, and returns the missing ones.
+// The broker doesn't compare the type and the arguments on a passive declare, so their drift isn't detected.
+// The broker closes the channel when a passive declare fails, so a new channel is opened after every missing item.
+func (t Topology) verify(open func() (topologyChannel, error)) ([]string, error) {
+	ch, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	drift := []string{}
+	for _, c := range t.checks() {
+		err := c.check(ch)
+		if err == nil {
+			continue
+		}
+		var amqpErr *amqp.Error
+		if !errors.As(err, &amqpErr) || amqpErr.Code != amqp.NotFound {
+			_ = ch.Close()
+			return nil, err
+		}
+
+		drift = append(drift, c.missing)
+		if ch, err = open(); err != nil {
+			return nil, err
+		}
+	}
+	return drift, ch.Close()
+}
+
+func (t Topology) checks() []topologyCheck {
+	var checks []topologyCheck
+	for _, e := range t.exchanges() {
+		e := e
+		checks = append(checks, topologyCheck{
+			missing: fmt.Sprintf("exchange %s is missing", e.Name),
+			check: func(ch topologyChannel) error {
+				return ch.ExchangeDeclarePassive(e.Name, e.kind(), true, false, false, false, nil)
+			},
+		})
+		if e.Queue != "" {
+			checks = append(checks, topologyCheck{
+				missing: fmt.Sprintf("queue %s is missing", e.Queue),
+				check: func(ch topologyChannel) error {
+					_, err := ch.QueueDeclarePassive(e.Queue, true, false, false, false, nil)
+					return err
+				},
+			})
+		}
+	}
+	return checks
+}
+
+func (e ExchangeConfig) kind() string {
+	if e.Type == "" {
+		return amqp.ExchangeTopic
+	}
+	return e.Type
+}
@@ -0,0 +1,136 @@
+package user
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	exchangeDeclare        = "ExchangeDeclare"
+	exchangeDeclarePassive = "ExchangeDeclarePassive"
+	queueDeclare           = "QueueDeclare"
+	queueDeclarePassive    = "QueueDeclarePassive"
+	queueBind              = "QueueBind"
+	closeChannel           = "Close"
+)
+
+type (
+	topologyTestSuite struct {
+		channelMock *mockTopologyChannel
+		suite.Suite
+	}
+)
+
+func TestTopologyTestSuite(t *testing.T) {
+	suite.Run(t, new(topologyTestSuite))
+}
+
+func (s *topologyTestSuite) SetupTest() {
+	s.channelMock = newMockTopologyChannel(s.T())
+}
+
+func (s *topologyTestSuite) TestLoadTopology() {
+	t, err := LoadTopology("../../scripts/topology.yaml")
+	s.Require().NoError(err)
+
+	s.Equal(ExchangeConfig{Name: "events.user"}, t.Exchange)
+	s.Equal(&ExchangeConfig{Name: "events.user.unroutable", Type: "fanout", Queue: "events.user.unroutable"}, t.AlternateExchange)
+	s.Equal(&ExchangeConfig{Name: "events.user.dlx", Type: "fanout", Queue: "events.user.dlq"}, t.DeadLetterExchange)
+	s.Equal(&ExchangeConfig{Name: "events.user.anonymous"}, t.PIIFreeExchange)
+	s.Equal("events.user.security", t.exchangeFor(UserEventTypePasswordChanged))
+	s.Equal("events.user", t.exchangeFor(UserEventTypeCreated))
+}
+
+func (s *topologyTestSuite) TestLoadTopology_ReturnsErrorOnInvalidTopology() {
+	for _, topology := range []string{
+		"exchange: [",
+		"alternate_exchange:\n  name: events.user.unroutable",
+		"exchange:\n  name: events.user\n  type: round-robin",
+		"exchange:\n  name: events.user\npii_free_exchange:\n  name: events.user",
+		"exchange:\n  name: events.user\nevent_exchanges:\n  USER_LOGGED_IN:\n    name: events.user.login",
+		"exchange:\n  name: events.user\ndead_letter_exchange:\n  type: fanout",
+	} {
+		path := filepath.Join(s.T().TempDir(), "topology.yaml")
+		s.Require().NoError(os.WriteFile(path, []byte(topology), 0o600))
+
+		_, err := LoadTopology(path)
+		s.ErrorIs(err, ErrInvalidTopology, topology)
+	}
+}
+
+func (s *topologyTestSuite) TestDeclare() {
+	t := s.topology()
+	s.channelMock.On(exchangeDeclare, "events.user.unroutable", "fanout", true, false, false, false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.On(queueDeclare, "events.user.unroutable", true, false, false, false, amqp.Table(nil)).Return(amqp.Queue{}, nil).Once()
+	s.channelMock.On(queueBind, "events.user.unroutable", "#", "events.user.unroutable", false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.
+		On(exchangeDeclare, "events.user", "topic", true, false, false, false, amqp.Table{"alternate-exchange": "events.user.unroutable"}).
+		Return(nil).
+		Once()
+	s.channelMock.
+		On(exchangeDeclare, "events.user.security", "topic", true, false, false, false, amqp.Table{"alternate-exchange": "events.user.unroutable"}).
+		Return(nil).
+		Once()
+
+	s.NoError(t.declare(s.channelMock))
+}
+
+func (s *topologyTestSuite) TestDeclare_ReturnsError() {
+	t := s.topology()
+	s.channelMock.On(exchangeDeclare, mock.Anything, mock.Anything, true, false, false, false, mock.Anything).Return(errors.New("access refused")).Once()
+
+	s.Error(t.declare(s.channelMock))
+	s.channelMock.AssertNotCalled(s.T(), queueDeclare, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *topologyTestSuite) TestVerify_ReturnsDrift() {
+	t := s.topology()
+	notFound := &amqp.Error{Code: amqp.NotFound, Reason: "NOT_FOUND"}
+	s.channelMock.On(exchangeDeclarePassive, "events.user.unroutable", "fanout", true, false, false, false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.On(queueDeclarePassive, "events.user.unroutable", true, false, false, false, amqp.Table(nil)).Return(amqp.Queue{}, notFound).Once()
+	s.channelMock.On(exchangeDeclarePassive, "events.user", "topic", true, false, false, false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.On(exchangeDeclarePassive, "events.user.security", "topic", true, false, false, false, amqp.Table(nil)).Return(notFound).Once()
+	s.channelMock.On(closeChannel).Return(nil).Once()
+
+	opened := 0
+	drift, err := t.verify(func() (topologyChannel, error) {
+		opened++
+		return s.channelMock, nil
+	})
+	s.NoError(err)
+	s.Equal([]string{"queue events.user.unroutable is missing", "exchange events.user.security is missing"}, drift)
+	s.Equal(3, opened, "a new channel must be opened after every failed passive declare")
+}
+
+func (s *topologyTestSuite) TestVerify_ReturnsErrorWhenBrokerIsDown() {
+	t := s.topology()
+	s.channelMock.On(exchangeDeclarePassive, "events.user.unroutable", "fanout", true, false, false, false, amqp.Table(nil)).Return(amqp.ErrClosed).Once()
+	s.channelMock.On(closeChannel).Return(nil).Once()
+
+	drift, err := t.verify(func() (topologyChannel, error) {
+		return s.channelMock, nil
+	})
+	s.ErrorIs(err, amqp.ErrClosed)
+	s.Nil(drift)
+
+	_, err = t.verify(func() (topologyChannel, error) {
+		return nil, amqp.ErrClosed
+	})
+	s.ErrorIs(err, amqp.ErrClosed)
+}
+
+func (s *topologyTestSuite) topology() Topology {
+	return Topology{
+		Exchange:          ExchangeConfig{Name: "events.user"},
+		AlternateExchange: &ExchangeConfig{Name: "events.user.unroutable", Type: "fanout", Queue: "events.user.unroutable"},
+		EventExchanges: map[UserEventType]ExchangeConfig{
+			UserEventTypePasswordChanged: {Name: "events.user.security"},
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package user
+
+import (
+	"strings"
+)
+
+// validation rules of the user fields
+const (
+	RuleMinLength = "min_length"
+	RuleLength    = "length"
+	RuleEmail     = "email"
+)
+
+type (
+	// FieldError is an invalid field of the user with the rule what it failed
+	FieldError struct {
+		Field   string
+		Rule    string
+		Message string
+	}
+
+	// ValidationError lists every invalid field of the user, it wraps ErrInvalidUserInputData
+	ValidationError struct {
+		Fields []FieldError
+	}
+)
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Message)
+	}
+	return ErrInvalidUserInputData.Error() + ": " + strings.Join(messages, ", ")
+}
+
+// Unwrap returns ErrInvalidUserInputData, so the validation error is classified as its kind
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidUserInputData
+}
+
+func (e *ValidationError) add(field string, rule string, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Rule: rule, Message: message})
+}
+
+// errOrNil returns the validation error when a field is invalid, or nil
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	validationTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(validationTestSuite))
+}
+
+func (s *validationTestSuite) TestValidate() {
+	s.NoError(validUser.Validate())
+}
+
+func (s *validationTestSuite) TestValidate_ListsEveryInvalidField() {
+	err := User{FirstName: "j", Nickname: "jd", Email: "john", Country: "USA"}.Validate()
+
+	s.ErrorIs(err, ErrInvalidUserInputData)
+	s.Equal(&ValidationError{Fields: []FieldError{
+		{Field: "first_name", Rule: RuleMinLength, Message: "first name is too short"},
+		{Field: "last_name", Rule: RuleMinLength, Message: "last name is too short"},
+		{Field: "nickname", Rule: RuleMinLength, Message: "nickname is too short"},
+		{Field: "country", Rule: RuleLength, Message: "country code must be 2 letters"},
+		{Field: "email", Rule: RuleEmail, Message: "invalid email address"},
+	}}, err)
+	s.Equal("input user data is invalid: first name is too short, last name is too short, nickname is too short, "+
+		"country code must be 2 letters, invalid email address", err.Error())
+}
+
+func (s *validationTestSuite) TestValidateIfNotEmpty() {
+	s.NoError(User{}.ValidateIfNotEmpty())
+	s.NoError(User{Nickname: "jd", Country: "US"}.ValidateIfNotEmpty())
+
+	err := User{FirstName: "j", Country: "USA"}.ValidateIfNotEmpty()
+	s.ErrorIs(err, ErrInvalidUserInputData)
+	s.Equal(&ValidationError{Fields: []FieldError{
+		{Field: "first_name", Rule: RuleMinLength, Message: "first_name must be at least 2 characters"},
+		{Field: "country", Rule: RuleLength, Message: "country must have exactly 2 characters"},
+	}}, err)
+}
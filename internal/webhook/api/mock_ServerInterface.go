@@ -0,0 +1,114 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package api
+
+import (
+	echo "github.com/labstack/echo/v4"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockServerInterface is an autogenerated mock type for the ServerInterface type
+type MockServerInterface struct {
+	mock.Mock
+}
+
+// CreateWebhook provides a mock function with given fields: ctx
+func (_m *MockServerInterface) CreateWebhook(ctx echo.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhookByID provides a mock function with given fields: ctx, id
+func (_m *MockServerInterface) DeleteWebhookByID(ctx echo.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetWebhookByID provides a mock function with given fields: ctx, id
+func (_m *MockServerInterface) GetWebhookByID(ctx echo.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListWebhookDeliveries provides a mock function with given fields: ctx, id, params
+func (_m *MockServerInterface) ListWebhookDeliveries(ctx echo.Context, id uuid.UUID, params ListWebhookDeliveriesParams) error {
+	ret := _m.Called(ctx, id, params)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, ListWebhookDeliveriesParams) error); ok {
+		r0 = rf(ctx, id, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListWebhooks provides a mock function with given fields: ctx, params
+func (_m *MockServerInterface) ListWebhooks(ctx echo.Context, params ListWebhooksParams) error {
+	ret := _m.Called(ctx, params)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, ListWebhooksParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWebhookByID provides a mock function with given fields: ctx, id
+func (_m *MockServerInterface) UpdateWebhookByID(ctx echo.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMockServerInterface interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockServerInterface creates a new instance of MockServerInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockServerInterface(t mockConstructorTestingTNewMockServerInterface) *MockServerInterface {
+	mock := &MockServerInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,133 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.12.3 DO NOT EDIT.
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Defines values for EventType.
+const (
+	USERCOUNTRYCHANGED  EventType = "USER_COUNTRY_CHANGED"
+	USERCREATED         EventType = "USER_CREATED"
+	USERDELETED         EventType = "USER_DELETED"
+	USEREMAILCHANGED    EventType = "USER_EMAIL_CHANGED"
+	USERNICKNAMECHANGED EventType = "USER_NICKNAME_CHANGED"
+	USERPASSWORDCHANGED EventType = "USER_PASSWORD_CHANGED"
+	USERUPDATED         EventType = "USER_UPDATED"
+)
+
+// Delivery defines model for Delivery.
+type Delivery struct {
+	Attempt    int       `json:"attempt"`
+	CreatedAt  time.Time `json:"created_at"`
+	DeliveryId uuid.UUID `json:"delivery_id"`
+	DurationMs *int      `json:"duration_ms,omitempty"`
+	Error      *string   `json:"error,omitempty"`
+	EventType  EventType `json:"event_type"`
+	Id         uuid.UUID `json:"id"`
+	StatusCode *int      `json:"status_code,omitempty"`
+	Success    bool      `json:"success"`
+	UserId     uuid.UUID `json:"user_id"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	CorrelationId uuid.UUID `json:"correlation_id"`
+	Message       string    `json:"message"`
+	Status        int       `json:"status"`
+	Time          time.Time `json:"time"`
+}
+
+// EventType defines model for EventType.
+type EventType string
+
+// NewSubscription defines model for NewSubscription.
+type NewSubscription struct {
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// EventTypes delivered event types, all events are delivered when it is empty
+	EventTypes *[]EventType `json:"event_types,omitempty"`
+
+	// Url HTTPS callback URL
+	Url string `json:"url"`
+}
+
+// Subscription defines model for Subscription.
+type Subscription struct {
+	// ConsecutiveFailures number of failed deliveries since the last successful one
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	CreatedAt           time.Time `json:"created_at"`
+
+	// DisabledAt set when the subscription was disabled automatically because of failing deliveries
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	Enabled    bool       `json:"enabled"`
+
+	// EventTypes delivered event types, all events are delivered when it is empty
+	EventTypes []EventType `json:"event_types"`
+	Id         uuid.UUID   `json:"id"`
+	UpdatedAt  *time.Time  `json:"updated_at,omitempty"`
+
+	// Url HTTPS callback URL
+	Url string `json:"url"`
+}
+
+// SubscriptionWithSecret defines model for SubscriptionWithSecret.
+type SubscriptionWithSecret struct {
+	// ConsecutiveFailures number of failed deliveries since the last successful one
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	CreatedAt           time.Time `json:"created_at"`
+
+	// DisabledAt set when the subscription was disabled automatically because of failing deliveries
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	Enabled    bool       `json:"enabled"`
+
+	// EventTypes delivered event types, all events are delivered when it is empty
+	EventTypes []EventType `json:"event_types"`
+	Id         uuid.UUID   `json:"id"`
+
+	// Secret key of the HMAC-SHA256 delivery signature
+	Secret    string     `json:"secret"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// Url HTTPS callback URL
+	Url string `json:"url"`
+}
+
+// UpdateSubscription defines model for UpdateSubscription.
+type UpdateSubscription struct {
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// EventTypes delivered event types, all events are delivered when it is empty
+	EventTypes *[]EventType `json:"event_types,omitempty"`
+
+	// Url HTTPS callback URL
+	Url *string `json:"url,omitempty"`
+}
+
+// ListWebhooksParams defines parameters for ListWebhooks.
+type ListWebhooksParams struct {
+	// Page page number
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// Pagesize number of listed items
+	Pagesize *int `form:"pagesize,omitempty" json:"pagesize,omitempty"`
+}
+
+// ListWebhookDeliveriesParams defines parameters for ListWebhookDeliveries.
+type ListWebhookDeliveriesParams struct {
+	// Page page number
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// Pagesize number of listed items
+	Pagesize *int `form:"pagesize,omitempty" json:"pagesize,omitempty"`
+}
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody = NewSubscription
+
+// UpdateWebhookByIDJSONRequestBody defines body for UpdateWebhookByID for application/json ContentType.
+type UpdateWebhookByIDJSONRequestBody = UpdateSubscription
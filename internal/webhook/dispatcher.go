@@ -0,0 +1,374 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+type (
+	// DispatcherConfig holds the delivery parameters of the webhooks.
+	DispatcherConfig struct {
+		Workers                int
+		QueueSize              int
+		MaxAttempts            int
+		InitialBackoff         time.Duration
+		MaxBackoff             time.Duration
+		Timeout                time.Duration
+		MaxConsecutiveFailures int
+		// AllowPrivateTargets allows the deliveries to private, loopback and link-local addresses, i.e. for local testing
+		AllowPrivateTargets bool
+	}
+
+	deliveryJob struct {
+		subscription  Subscription
+		deliveryID    uuid.UUID
+		correlationID string
+		event         user.UserEvent
+		body          []byte
+		attempt       int
+	}
+
+	// Dispatcher delivers the user events to the matching webhook subscriptions.
+	// Failed deliveries are retried with exponential backoff and every attempt is saved to the delivery log.
+	// The retries are queued again by a timer when their backoff is over, so they don't block the workers.
+	Dispatcher struct {
+		repository repository
+		client     *http.Client
+		config     DispatcherConfig
+		queue      chan deliveryJob
+		ctx        context.Context
+		cancel     context.CancelFunc
+		mu         sync.RWMutex
+		closed     bool
+		// jobs counts the deliveries from their dispatch until their last attempt, including the waiting retries
+		jobs sync.WaitGroup
+		wg   sync.WaitGroup
+	}
+)
+
+// NewDispatcherConfig reads the webhook delivery configuration from the environment.
+func NewDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		Workers:                common.GetEnvInt("WEBHOOK_WORKERS", 4),
+		QueueSize:              common.GetEnvInt("WEBHOOK_QUEUE_SIZE", 1000),
+		MaxAttempts:            common.GetEnvInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		InitialBackoff:         common.GetEnvDuration("WEBHOOK_INITIAL_BACKOFF", time.Second),
+		MaxBackoff:             common.GetEnvDuration("WEBHOOK_MAX_BACKOFF", time.Minute),
+		Timeout:                common.GetEnvDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+		MaxConsecutiveFailures: common.GetEnvInt("WEBHOOK_MAX_CONSECUTIVE_FAILURES", 10),
+		AllowPrivateTargets:    strings.EqualFold(common.GetEnv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false"), "true"),
+	}
+}
+
+// NewDispatcher starts the delivery workers.
+func NewDispatcher(repository repository, config DispatcherConfig) *Dispatcher {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		repository: repository,
+		client:     newHTTPClient(config),
+		config:     config,
+		queue:      make(chan deliveryJob, config.QueueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+
+	for i := 0; i < config.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// dispatch queues a delivery of the event for every enabled subscription what accepts its type.
+func (d *Dispatcher) dispatch(ctx context.Context, event user.UserEvent) {
+	correlationID := common.GetCorrelationID(ctx)
+	subscriptions, err := d.repository.listEnabled(ctx)
+	if err != nil {
+		log.Err(err).
+			Str(common.CorrelationID, correlationID).
+			Msg("failed to list webhook subscriptions")
+		return
+	}
+
+	body, err := json.Marshal(&event)
+	if err != nil {
+		log.Err(err).
+			Str(common.CorrelationID, correlationID).
+			Msg("failed to encode webhook payload")
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return
+	}
+
+	for _, s := range subscriptions {
+		if !s.accepts(event.Type) {
+			continue
+		}
+
+		job := deliveryJob{
+			subscription:  s,
+			deliveryID:    uuid.New(),
+			correlationID: correlationID,
+			event:         event,
+			body:          body,
+			attempt:       1,
+		}
+		d.jobs.Add(1)
+		select {
+		case d.queue <- job:
+		default:
+			d.jobs.Done()
+			log.Warn().
+				Str(common.CorrelationID, correlationID).
+				Stringer("subscription", s.ID).
+				Str("type", string(event.Type)).
+				Msg("webhook delivery queue is full, delivery dropped")
+		}
+	}
+}
+
+// close stops accepting new deliveries and waits until the queued ones and their retries are done.
+// The pending retries are abandoned when the context is done.
+func (d *Dispatcher) close(ctx context.Context) error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil
+	}
+	d.closed = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.jobs.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	d.cancel()
+	d.wg.Wait()
+	d.abandonQueued()
+	return err
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for {
+		select {
+		case job := <-d.queue:
+			d.deliver(job)
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// abandonQueued drops the jobs what were left in the queue by the stopped workers.
+func (d *Dispatcher) abandonQueued() {
+	for {
+		select {
+		case <-d.queue:
+			d.jobs.Done()
+		default:
+			return
+		}
+	}
+}
+
+// deliver attempts the delivery once, and schedules its retry when it failed and it has attempts left.
+func (d *Dispatcher) deliver(job deliveryJob) {
+	logger := log.With().
+		Str(common.CorrelationID, job.correlationID).
+		Stringer("subscription", job.subscription.ID).
+		Stringer("delivery", job.deliveryID).
+		Logger()
+
+	if d.attempt(job, job.attempt) {
+		if err := d.repository.recordSuccess(d.ctx, job.subscription.ID); err != nil {
+			logger.Err(err).Msg("failed to reset webhook failures")
+		}
+		d.jobs.Done()
+		return
+	}
+
+	if job.attempt < d.config.MaxAttempts && d.ctx.Err() == nil {
+		d.retry(job)
+		return
+	}
+
+	disabled, err := d.repository.recordFailure(d.ctx, job.subscription.ID, d.config.MaxConsecutiveFailures)
+	if err != nil {
+		logger.Err(err).Msg("failed to record webhook failure")
+	}
+	if disabled {
+		logger.Warn().Str("url", job.subscription.URL).Msg("webhook subscription disabled because of failing deliveries")
+	}
+	d.jobs.Done()
+}
+
+// retry queues the next attempt of the job after its backoff. The retry is abandoned when the dispatcher was stopped
+// in the meantime.
+func (d *Dispatcher) retry(job deliveryJob) {
+	backoff := d.backoff(job.attempt)
+	job.attempt++
+	time.AfterFunc(backoff, func() {
+		select {
+		case d.queue <- job:
+		case <-d.ctx.Done():
+			d.jobs.Done()
+		}
+	})
+}
+
+// attempt sends the event once and saves the result to the delivery log.
+func (d *Dispatcher) attempt(job deliveryJob, attempt int) bool {
+	delivery := Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: job.subscription.ID,
+		DeliveryID:     job.deliveryID,
+		EventType:      job.event.Type,
+		UserID:         job.event.UserID,
+		Attempt:        attempt,
+	}
+
+	start := time.Now()
+	statusCode, err := d.send(job)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if statusCode > 0 {
+		delivery.StatusCode = common.Ptr(statusCode)
+	}
+	if err == nil && (statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices) {
+		err = fmt.Errorf("%w %d", errUnexpectedStatusCode, statusCode)
+	}
+	if err != nil {
+		delivery.Error = common.Ptr(err.Error())
+		log.Warn().Err(err).
+			Str(common.CorrelationID, job.correlationID).
+			Stringer("subscription", job.subscription.ID).
+			Int("attempt", attempt).
+			Msg("webhook delivery failed")
+	}
+	delivery.Success = err == nil
+
+	if e := d.repository.saveDelivery(d.ctx, delivery); e != nil {
+		log.Err(e).
+			Str(common.CorrelationID, job.correlationID).
+			Stringer("subscription", job.subscription.ID).
+			Msg("failed to save webhook delivery")
+	}
+	return delivery.Success
+}
+
+func (d *Dispatcher) send(job deliveryJob) (int, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, job.subscription.URL, bytes.NewReader(job.body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderXRequestID, job.correlationID)
+	req.Header.Set(HeaderEvent, string(job.event.Type))
+	req.Header.Set(HeaderDelivery, job.deliveryID.String())
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(job.subscription.Secret, timestamp, job.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	backoff := d.config.InitialBackoff << (attempt - 1)
+	if backoff <= 0 || (d.config.MaxBackoff > 0 && backoff > d.config.MaxBackoff) {
+		return d.config.MaxBackoff
+	}
+	return backoff
+}
+
+// newHTTPClient creates the client of the deliveries what refuses to connect to private, loopback and link-local
+// addresses unless they are allowed. The resolved address is checked on every connection, so a host name can't
+// be pointed to an internal address after it was validated.
+func newHTTPClient(config DispatcherConfig) *http.Client {
+	if config.AllowPrivateTargets {
+		return &http.Client{Timeout: config.Timeout}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   rejectPrivateTarget,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: config.Timeout, Transport: transport}
+}
+
+func rejectPrivateTarget(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s", errPrivateTarget, host)
+	}
+	return nil
+}
+
+// Sign returns the value of the X-Webhook-Signature header what the receivers could use to verify the deliveries.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	dispatcherTestSuite struct {
+		repoMock *mockRepository
+		suite.Suite
+	}
+)
+
+func TestDispatcherTestSuite(t *testing.T) {
+	suite.Run(t, new(dispatcherTestSuite))
+}
+
+func (s *dispatcherTestSuite) SetupTest() {
+	s.repoMock = newMockRepository(s.T())
+}
+
+func (s *dispatcherTestSuite) TestDispatch_DeliversSignedEvent() {
+	event := user.UserEvent{Type: user.UserEventTypeCreated, UserID: uuid.New(), Time: time.Now()}
+	received := make(chan *http.Request, 1)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+	}))
+	defer server.Close()
+
+	sub := Subscription{ID: uuid.New(), URL: server.URL, Secret: "secret", Enabled: true}
+	skipped := Subscription{ID: uuid.New(), URL: server.URL, EventTypes: []user.UserEventType{user.UserEventTypeDeleted}, Enabled: true}
+	s.repoMock.On(listEnabled, mock.Anything).Return([]Subscription{sub, skipped}, nil).Once()
+	s.repoMock.
+		On(saveDelivery, mock.Anything, mock.MatchedBy(func(d Delivery) bool {
+			return d.Success && d.Attempt == 1 && d.SubscriptionID == sub.ID && *d.StatusCode == http.StatusOK
+		})).
+		Return(nil).
+		Once()
+	s.repoMock.On(recordSuccess, mock.Anything, sub.ID).Return(nil).Once()
+
+	d := NewDispatcher(s.repoMock, s.config(1))
+	ctx := context.WithValue(context.TODO(), common.CorrelationID, "test-correlation-id")
+	d.dispatch(ctx, event)
+
+	var req *http.Request
+	select {
+	case req = <-received:
+	case <-time.After(time.Second):
+		s.FailNow("webhook was not delivered")
+	}
+	s.NoError(d.close(context.TODO()))
+
+	expectedBody, err := json.Marshal(&event)
+	s.NoError(err)
+	s.Equal(expectedBody, body)
+	s.Equal("USER_CREATED", req.Header.Get(HeaderEvent))
+	s.Equal("test-correlation-id", req.Header.Get("X-Request-Id"))
+	s.Equal(Sign("secret", req.Header.Get(HeaderTimestamp), body), req.Header.Get(HeaderSignature))
+}
+
+func (s *dispatcherTestSuite) TestDispatch_RetriesAndRecordsFailure() {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sub := Subscription{ID: uuid.New(), URL: server.URL, Enabled: true}
+	s.repoMock.On(listEnabled, mock.Anything).Return([]Subscription{sub}, nil).Once()
+	s.repoMock.
+		On(saveDelivery, mock.Anything, mock.MatchedBy(func(d Delivery) bool {
+			return !d.Success && *d.StatusCode == http.StatusServiceUnavailable && d.Error != nil
+		})).
+		Return(nil).
+		Times(3)
+	s.repoMock.On(recordFailure, mock.Anything, sub.ID, 2).Return(true, nil).Once()
+
+	d := NewDispatcher(s.repoMock, s.config(3))
+	d.dispatch(context.TODO(), user.UserEvent{Type: user.UserEventTypeDeleted, UserID: uuid.New()})
+	s.Eventually(func() bool { return atomic.LoadInt32(&calls) == 3 }, time.Second, 10*time.Millisecond)
+	s.NoError(d.close(context.TODO()))
+}
+
+func (s *dispatcherTestSuite) TestDispatch_RetriesDontBlockWorkers() {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	received := make(chan struct{}, 1)
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+	}))
+	defer healthy.Close()
+
+	failingSub := Subscription{ID: uuid.New(), URL: failing.URL, Enabled: true}
+	healthySub := Subscription{ID: uuid.New(), URL: healthy.URL, Enabled: true}
+	s.repoMock.On(listEnabled, mock.Anything).Return([]Subscription{failingSub, healthySub}, nil).Once()
+	s.repoMock.On(saveDelivery, mock.Anything, mock.Anything).Return(nil).Twice()
+	s.repoMock.On(recordSuccess, mock.Anything, healthySub.ID).Return(nil).Once()
+	s.repoMock.On(recordFailure, mock.Anything, failingSub.ID, 2).Return(false, nil).Maybe()
+
+	config := s.config(2)
+	config.InitialBackoff = time.Minute
+	config.MaxBackoff = time.Minute
+	d := NewDispatcher(s.repoMock, config)
+	d.dispatch(context.TODO(), user.UserEvent{Type: user.UserEventTypeDeleted, UserID: uuid.New()})
+
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		s.FailNow("the single worker was blocked by the backoff of the failed delivery")
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	s.ErrorIs(d.close(ctx), context.DeadlineExceeded, "the pending retry is abandoned")
+}
+
+func (s *dispatcherTestSuite) TestDispatch_RejectsPrivateTargets() {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	sub := Subscription{ID: uuid.New(), URL: server.URL, Enabled: true}
+	s.repoMock.On(listEnabled, mock.Anything).Return([]Subscription{sub}, nil).Once()
+	s.repoMock.
+		On(saveDelivery, mock.Anything, mock.MatchedBy(func(d Delivery) bool {
+			return !d.Success && d.StatusCode == nil && strings.Contains(*d.Error, errPrivateTarget.Error())
+		})).
+		Return(nil).
+		Once()
+	s.repoMock.On(recordFailure, mock.Anything, sub.ID, 2).Return(false, nil).Once()
+
+	config := s.config(1)
+	config.AllowPrivateTargets = false
+	d := NewDispatcher(s.repoMock, config)
+	d.dispatch(context.TODO(), user.UserEvent{Type: user.UserEventTypeDeleted, UserID: uuid.New()})
+	s.NoError(d.close(context.TODO()))
+	s.Zero(atomic.LoadInt32(&calls))
+}
+
+func (s *dispatcherTestSuite) TestBackoff() {
+	d := Dispatcher{config: DispatcherConfig{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+	s.Equal(time.Second, d.backoff(1))
+	s.Equal(2*time.Second, d.backoff(2))
+	s.Equal(4*time.Second, d.backoff(3))
+	s.Equal(5*time.Second, d.backoff(4))
+	s.Equal(5*time.Second, d.backoff(100))
+}
+
+func (s *dispatcherTestSuite) config(maxAttempts int) DispatcherConfig {
+	return DispatcherConfig{
+		Workers:                1,
+		QueueSize:              10,
+		MaxAttempts:            maxAttempts,
+		InitialBackoff:         time.Millisecond,
+		MaxBackoff:             time.Millisecond,
+		Timeout:                time.Second,
+		MaxConsecutiveFailures: 2,
+		AllowPrivateTargets:    true,
+	}
+}
@@ -0,0 +1,49 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package webhook
+
+import (
+	context "context"
+	user "faceit/internal/user"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockDispatcher is an autogenerated mock type for the dispatcher type
+type mockDispatcher struct {
+	mock.Mock
+}
+
+// close provides a mock function with given fields: ctx
+func (_m *mockDispatcher) close(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// dispatch provides a mock function with given fields: ctx, event
+func (_m *mockDispatcher) dispatch(ctx context.Context, event user.UserEvent) {
+	_m.Called(ctx, event)
+}
+
+type mockConstructorTestingTnewMockDispatcher interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockDispatcher creates a new instance of mockDispatcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockDispatcher(t mockConstructorTestingTnewMockDispatcher) *mockDispatcher {
+	mock := &mockDispatcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,233 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package webhook
+
+import (
+	context "context"
+	common "faceit/internal/common"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// mockRepository is an autogenerated mock type for the repository type
+type mockRepository struct {
+	mock.Mock
+}
+
+// create provides a mock function with given fields: ctx, subscription
+func (_m *mockRepository) create(ctx context.Context, subscription Subscription) (*Subscription, error) {
+	ret := _m.Called(ctx, subscription)
+
+	var r0 *Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, Subscription) *Subscription); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, Subscription) error); ok {
+		r1 = rf(ctx, subscription)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// deleteByID provides a mock function with given fields: ctx, id
+func (_m *mockRepository) deleteByID(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// findByID provides a mock function with given fields: ctx, id
+func (_m *mockRepository) findByID(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *Subscription); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// list provides a mock function with given fields: ctx, pagination
+func (_m *mockRepository) list(ctx context.Context, pagination common.Pagination) ([]Subscription, error) {
+	ret := _m.Called(ctx, pagination)
+
+	var r0 []Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, common.Pagination) []Subscription); ok {
+		r0 = rf(ctx, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, common.Pagination) error); ok {
+		r1 = rf(ctx, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// listDeliveries provides a mock function with given fields: ctx, subscriptionID, pagination
+func (_m *mockRepository) listDeliveries(ctx context.Context, subscriptionID uuid.UUID, pagination common.Pagination) ([]Delivery, error) {
+	ret := _m.Called(ctx, subscriptionID, pagination)
+
+	var r0 []Delivery
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, common.Pagination) []Delivery); ok {
+		r0 = rf(ctx, subscriptionID, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, common.Pagination) error); ok {
+		r1 = rf(ctx, subscriptionID, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// listEnabled provides a mock function with given fields: ctx
+func (_m *mockRepository) listEnabled(ctx context.Context) ([]Subscription, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []Subscription
+	if rf, ok := ret.Get(0).(func(context.Context) []Subscription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// recordFailure provides a mock function with given fields: ctx, id, maxFailures
+func (_m *mockRepository) recordFailure(ctx context.Context, id uuid.UUID, maxFailures int) (bool, error) {
+	ret := _m.Called(ctx, id, maxFailures)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) bool); ok {
+		r0 = rf(ctx, id, maxFailures)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) error); ok {
+		r1 = rf(ctx, id, maxFailures)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// recordSuccess provides a mock function with given fields: ctx, id
+func (_m *mockRepository) recordSuccess(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// saveDelivery provides a mock function with given fields: ctx, delivery
+func (_m *mockRepository) saveDelivery(ctx context.Context, delivery Delivery) error {
+	ret := _m.Called(ctx, delivery)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, Delivery) error); ok {
+		r0 = rf(ctx, delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// update provides a mock function with given fields: ctx, id, update
+func (_m *mockRepository) update(ctx context.Context, id uuid.UUID, update SubscriptionUpdate) (*Subscription, error) {
+	ret := _m.Called(ctx, id, update)
+
+	var r0 *Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, SubscriptionUpdate) *Subscription); ok {
+		r0 = rf(ctx, id, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, SubscriptionUpdate) error); ok {
+		r1 = rf(ctx, id, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTnewMockRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockRepository creates a new instance of mockRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockRepository(t mockConstructorTestingTnewMockRepository) *mockRepository {
+	mock := &mockRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"errors"
+	"faceit/internal/user"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSubscriptionNotFound     = &user.Error{Kind: user.KindNotFound, Code: "webhook_not_found", Message: "webhook subscription not found"}
+	ErrNilUUIDNotAllowed        = user.ErrNilUUIDNotAllowed
+	ErrNewSubscriptionWithID    = &user.Error{Kind: user.KindValidation, Code: "webhook_id_not_allowed", Message: "new webhook subscription can't have a predefined ID"}
+	ErrInvalidSubscriptionInput = &user.Error{Kind: user.KindValidation, Code: "invalid_webhook", Message: "input webhook subscription data is invalid"}
+	ErrInvalidPagination        = user.ErrInvalidPagination
+	errUnexpectedStatusCode     = errors.New("unexpected status code")
+	errPrivateTarget            = errors.New("url must not target a private, loopback or link-local address")
+)
+
+// Subscription is a registered callback URL what receives the user events.
+// An empty EventTypes list means that every event type is delivered.
+type Subscription struct {
+	ID                  uuid.UUID            `json:"id"`
+	URL                 string               `json:"url"`
+	Secret              string               `json:"-"`
+	EventTypes          []user.UserEventType `json:"event_types" gorm:"serializer:json"`
+	Enabled             bool                 `json:"enabled"`
+	ConsecutiveFailures int                  `json:"consecutive_failures"`
+	DisabledAt          *time.Time           `json:"disabled_at"`
+	CreatedAt           time.Time            `json:"created_at"`
+	UpdatedAt           *time.Time           `json:"updated_at"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+func (s Subscription) accepts(eventType user.UserEventType) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscriptionUpdate holds the changes of a subscription, nil fields are not changed.
+type SubscriptionUpdate struct {
+	URL        *string
+	EventTypes *[]user.UserEventType
+	Enabled    *bool
+}
+
+// Delivery is a single delivery attempt of a user event to a subscription.
+// The retry attempts of the same event share the same DeliveryID.
+type Delivery struct {
+	ID             uuid.UUID          `json:"id"`
+	SubscriptionID uuid.UUID          `json:"subscription_id"`
+	DeliveryID     uuid.UUID          `json:"delivery_id"`
+	EventType      user.UserEventType `json:"event_type"`
+	UserID         uuid.UUID          `json:"user_id"`
+	Attempt        int                `json:"attempt"`
+	StatusCode     *int               `json:"status_code"`
+	Error          *string            `json:"error"`
+	Success        bool               `json:"success"`
+	DurationMs     int64              `json:"duration_ms"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// validateURL checks the scheme and the host of the callback URL. The IP addresses and the localhost names
+// of the private, loopback and link-local targets are rejected unless they are allowed,
+// the host names are checked by the dispatcher when they are resolved.
+func validateURL(callbackURL string, allowHTTP bool, allowPrivate bool) error {
+	u, err := url.Parse(callbackURL)
+	switch {
+	case err != nil:
+		return fmt.Errorf("invalid url: %s", err.Error())
+	case u.Host == "":
+		return errors.New("url must have a host")
+	case !allowPrivate && isPrivateHost(u.Hostname()):
+		return errPrivateTarget
+	case u.Scheme == "https":
+		return nil
+	case u.Scheme == "http" && allowHTTP:
+		return nil
+	}
+	return errors.New("url must use https scheme")
+}
+
+func isPrivateHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && !isPublicIP(ip)
+}
+
+// isPublicIP is false for the private, loopback, link-local and unspecified addresses
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsUnspecified()
+}
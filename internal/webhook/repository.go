@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"faceit/internal/common"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new DB connection
+func NewRepository() (*gormRepository, error) {
+	db, err := common.OpenPostgres()
+	if err != nil {
+		return nil, err
+	}
+
+	return &gormRepository{db: db}, nil
+}
+
+func (r gormRepository) findByID(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	var s *Subscription
+	if err := r.db.WithContext(ctx).Take(&s, id).Error; err != nil {
+		return nil, handleNotFoundError(err)
+	}
+
+	return s, nil
+}
+
+func (r gormRepository) list(ctx context.Context, pagination common.Pagination) ([]Subscription, error) {
+	var subscriptions []Subscription
+	err := r.db.WithContext(ctx).
+		Offset(pagination.GetOffset()).
+		Limit(pagination.GetLimit()).
+		Order("created_at asc").
+		Find(&subscriptions).
+		Error
+	return subscriptions, err
+}
+
+func (r gormRepository) listEnabled(ctx context.Context) ([]Subscription, error) {
+	var subscriptions []Subscription
+	err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Find(&subscriptions).
+		Error
+	return subscriptions, err
+}
+
+func (r gormRepository) create(ctx context.Context, subscription Subscription) (*Subscription, error) {
+	if err := r.db.WithContext(ctx).Create(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// update saves the non-nil fields of the update.
+// Enabling a subscription also resets its consecutive failures.
+func (r gormRepository) update(ctx context.Context, id uuid.UUID, update SubscriptionUpdate) (*Subscription, error) {
+	changes := map[string]any{}
+	if update.URL != nil {
+		changes["url"] = *update.URL
+	}
+	if update.EventTypes != nil {
+		eventTypes, err := json.Marshal(*update.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		changes["event_types"] = string(eventTypes)
+	}
+	if update.Enabled != nil {
+		changes["enabled"] = *update.Enabled
+		if *update.Enabled {
+			changes["consecutive_failures"] = 0
+			changes["disabled_at"] = nil
+		}
+	}
+
+	var updated []Subscription
+	err := r.db.WithContext(ctx).
+		Model(&updated).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Updates(changes).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	if len(updated) == 0 {
+		return nil, ErrSubscriptionNotFound
+	}
+	return &updated[0], nil
+}
+
+// deleteByID deletes the subscription, ErrSubscriptionNotFound is returned when it doesn't exist.
+func (r gormRepository) deleteByID(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&Subscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r gormRepository) saveDelivery(ctx context.Context, delivery Delivery) error {
+	return r.db.WithContext(ctx).Create(&delivery).Error
+}
+
+func (r gormRepository) listDeliveries(ctx context.Context, subscriptionID uuid.UUID, pagination common.Pagination) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := r.db.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		Offset(pagination.GetOffset()).
+		Limit(pagination.GetLimit()).
+		Order("created_at desc").
+		Order("attempt desc").
+		Find(&deliveries).
+		Error
+	return deliveries, err
+}
+
+// recordSuccess resets the consecutive failures of the subscription.
+func (r gormRepository) recordSuccess(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&Subscription{}).
+		Where("id = ? AND consecutive_failures > 0", id).
+		Update("consecutive_failures", 0).
+		Error
+}
+
+// recordFailure increases the consecutive failures of the subscription
+// and disables it when the failures reach the given limit.
+// It returns true when the subscription was disabled.
+func (r gormRepository) recordFailure(ctx context.Context, id uuid.UUID, maxFailures int) (bool, error) {
+	var disabled bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var updated []Subscription
+		err := tx.Model(&updated).
+			Clauses(clause.Returning{}).
+			Where("id = ?", id).
+			Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).
+			Error
+		if err != nil || len(updated) == 0 {
+			return handleNotFoundError(err)
+		}
+
+		s := updated[0]
+		if !s.Enabled || maxFailures <= 0 || s.ConsecutiveFailures < maxFailures {
+			return nil
+		}
+
+		disabled = true
+		return tx.Model(&Subscription{}).
+			Where("id = ?", id).
+			Updates(map[string]any{"enabled": false, "disabled_at": time.Now()}).
+			Error
+	})
+	return disabled, err
+}
+
+func handleNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSubscriptionNotFound
+	}
+	return err
+}
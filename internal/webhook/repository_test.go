@@ -0,0 +1,118 @@
+//go:build integration
+// +build integration
+
+package webhook
+
+import (
+	"context"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	repositoryTestSuite struct {
+		repo gormRepository
+		suite.Suite
+	}
+)
+
+func TestRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(repositoryTestSuite))
+}
+
+func (s *repositoryTestSuite) SetupSuite() {
+	r, err := NewRepository()
+	s.Require().NoError(err)
+	s.repo = *r
+}
+
+func (s *repositoryTestSuite) TestCreateAndUpdate() {
+	sub, err := s.repo.create(context.TODO(), Subscription{
+		ID:         uuid.New(),
+		URL:        "https://example.com/hook",
+		Secret:     "secret",
+		EventTypes: []user.UserEventType{user.UserEventTypeCreated},
+		Enabled:    true,
+	})
+	s.Require().NoError(err)
+	defer s.repo.deleteByID(context.TODO(), sub.ID)
+
+	saved, err := s.repo.findByID(context.TODO(), sub.ID)
+	s.NoError(err)
+	s.Equal("secret", saved.Secret)
+	s.Equal([]user.UserEventType{user.UserEventTypeCreated}, saved.EventTypes)
+
+	updated, err := s.repo.update(context.TODO(), sub.ID, SubscriptionUpdate{
+		EventTypes: &[]user.UserEventType{user.UserEventTypeDeleted, user.UserEventTypeUpdated},
+		Enabled:    common.Ptr(false),
+	})
+	s.NoError(err)
+	s.False(updated.Enabled)
+	s.Equal([]user.UserEventType{user.UserEventTypeDeleted, user.UserEventTypeUpdated}, updated.EventTypes)
+	s.NotNil(updated.UpdatedAt)
+}
+
+func (s *repositoryTestSuite) TestUpdate_ReturnsNotFound() {
+	_, err := s.repo.update(context.TODO(), uuid.New(), SubscriptionUpdate{Enabled: common.Ptr(true)})
+	s.ErrorIs(err, ErrSubscriptionNotFound)
+}
+
+func (s *repositoryTestSuite) TestDeleteByID_ReturnsNotFound() {
+	s.ErrorIs(s.repo.deleteByID(context.TODO(), uuid.New()), ErrSubscriptionNotFound)
+}
+
+func (s *repositoryTestSuite) TestRecordFailure_DisablesSubscription() {
+	sub, err := s.repo.create(context.TODO(), Subscription{ID: uuid.New(), URL: "https://example.com", Secret: "secret", EventTypes: []user.UserEventType{}, Enabled: true})
+	s.Require().NoError(err)
+	defer s.repo.deleteByID(context.TODO(), sub.ID)
+
+	disabled, err := s.repo.recordFailure(context.TODO(), sub.ID, 2)
+	s.NoError(err)
+	s.False(disabled)
+
+	disabled, err = s.repo.recordFailure(context.TODO(), sub.ID, 2)
+	s.NoError(err)
+	s.True(disabled)
+
+	saved, err := s.repo.findByID(context.TODO(), sub.ID)
+	s.NoError(err)
+	s.False(saved.Enabled)
+	s.NotNil(saved.DisabledAt)
+	s.Equal(2, saved.ConsecutiveFailures)
+
+	// enabling resets the failures
+	updated, err := s.repo.update(context.TODO(), sub.ID, SubscriptionUpdate{Enabled: common.Ptr(true)})
+	s.NoError(err)
+	s.True(updated.Enabled)
+	s.Nil(updated.DisabledAt)
+	s.Zero(updated.ConsecutiveFailures)
+}
+
+func (s *repositoryTestSuite) TestDeliveries() {
+	sub, err := s.repo.create(context.TODO(), Subscription{ID: uuid.New(), URL: "https://example.com", Secret: "secret", EventTypes: []user.UserEventType{}, Enabled: true})
+	s.Require().NoError(err)
+	defer s.repo.deleteByID(context.TODO(), sub.ID)
+
+	deliveryID := uuid.New()
+	for attempt := 1; attempt <= 3; attempt++ {
+		s.NoError(s.repo.saveDelivery(context.TODO(), Delivery{
+			ID:             uuid.New(),
+			SubscriptionID: sub.ID,
+			DeliveryID:     deliveryID,
+			EventType:      user.UserEventTypeDeleted,
+			UserID:         uuid.New(),
+			Attempt:        attempt,
+			Success:        attempt == 3,
+		}))
+	}
+
+	deliveries, err := s.repo.listDeliveries(context.TODO(), sub.ID, common.Pagination{PageSize: 2})
+	s.NoError(err)
+	s.Len(deliveries, 2)
+	s.Equal(3, deliveries[0].Attempt)
+	s.True(deliveries[0].Success)
+}
@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type (
+	repository interface {
+		findByID(ctx context.Context, id uuid.UUID) (*Subscription, error)
+		list(ctx context.Context, pagination common.Pagination) ([]Subscription, error)
+		listEnabled(ctx context.Context) ([]Subscription, error)
+		create(ctx context.Context, subscription Subscription) (*Subscription, error)
+		update(ctx context.Context, id uuid.UUID, update SubscriptionUpdate) (*Subscription, error)
+		deleteByID(ctx context.Context, id uuid.UUID) error
+		saveDelivery(ctx context.Context, delivery Delivery) error
+		listDeliveries(ctx context.Context, subscriptionID uuid.UUID, pagination common.Pagination) ([]Delivery, error)
+		recordSuccess(ctx context.Context, id uuid.UUID) error
+		recordFailure(ctx context.Context, id uuid.UUID, maxFailures int) (bool, error)
+	}
+
+	dispatcher interface {
+		dispatch(ctx context.Context, event user.UserEvent)
+		close(ctx context.Context) error
+	}
+
+	// Service manages the webhook subscriptions and delivers the user events to them
+	Service struct {
+		repository   repository
+		dispatcher   dispatcher
+		allowHTTP    bool
+		allowPrivate bool
+	}
+)
+
+// NewService creates a new Service with it's all required dependencies
+func NewService() (*Service, error) {
+	r, err := NewRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	config := NewDispatcherConfig()
+	return &Service{
+		repository:   r,
+		dispatcher:   NewDispatcher(r, config),
+		allowHTTP:    strings.EqualFold(common.GetEnv("WEBHOOK_ALLOW_HTTP", "false"), "true"),
+		allowPrivate: config.AllowPrivateTargets,
+	}, nil
+}
+
+// Create validates and saves a new subscription with a generated signing secret.
+func (s Service) Create(ctx context.Context, subscription Subscription) (*Subscription, error) {
+	if subscription.ID != uuid.Nil {
+		return nil, ErrNewSubscriptionWithID
+	}
+
+	if err := validateURL(subscription.URL, s.allowHTTP, s.allowPrivate); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSubscriptionInput, err.Error())
+	}
+	if err := user.ValidateEventTypes(subscription.EventTypes); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSubscriptionInput, err.Error())
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.ID = uuid.New()
+	subscription.Secret = secret
+	subscription.ConsecutiveFailures = 0
+	subscription.DisabledAt = nil
+	if subscription.EventTypes == nil {
+		subscription.EventTypes = []user.UserEventType{}
+	}
+	return s.repository.create(ctx, subscription)
+}
+
+// Get retrieves a single subscription.
+func (s Service) Get(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	if id == uuid.Nil {
+		return nil, ErrNilUUIDNotAllowed
+	}
+	return s.repository.findByID(ctx, id)
+}
+
+// List returns a paged slice of subscriptions ordered by creation time.
+func (s Service) List(ctx context.Context, pagination common.Pagination) ([]Subscription, error) {
+	if err := pagination.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPagination, err.Error())
+	}
+	return s.repository.list(ctx, pagination)
+}
+
+// Update validates and saves changes on an existing subscription.
+// Enabling a subscription resets its consecutive failures.
+func (s Service) Update(ctx context.Context, id uuid.UUID, update SubscriptionUpdate) (*Subscription, error) {
+	if id == uuid.Nil {
+		return nil, ErrNilUUIDNotAllowed
+	}
+
+	if update.URL != nil {
+		if err := validateURL(*update.URL, s.allowHTTP, s.allowPrivate); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSubscriptionInput, err.Error())
+		}
+	}
+	if update.EventTypes != nil {
+		if err := user.ValidateEventTypes(*update.EventTypes); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSubscriptionInput, err.Error())
+		}
+	}
+
+	if update == (SubscriptionUpdate{}) {
+		return s.repository.findByID(ctx, id)
+	}
+	return s.repository.update(ctx, id, update)
+}
+
+// Delete removes an existing subscription with its delivery log, ErrSubscriptionNotFound is returned when it doesn't exist.
+func (s Service) Delete(ctx context.Context, id uuid.UUID) error {
+	if id == uuid.Nil {
+		return ErrNilUUIDNotAllowed
+	}
+	return s.repository.deleteByID(ctx, id)
+}
+
+// ListDeliveries returns the paged delivery log of a subscription, the latest attempts first.
+func (s Service) ListDeliveries(ctx context.Context, id uuid.UUID, pagination common.Pagination) ([]Delivery, error) {
+	if id == uuid.Nil {
+		return nil, ErrNilUUIDNotAllowed
+	}
+	if err := pagination.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPagination, err.Error())
+	}
+	if _, err := s.repository.findByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repository.listDeliveries(ctx, id, pagination)
+}
+
+// OnUserEvent queues the deliveries of a user event to the matching subscriptions.
+func (s Service) OnUserEvent(ctx context.Context, event user.UserEvent) {
+	s.dispatcher.dispatch(ctx, event)
+}
+
+// Close waits for the pending deliveries.
+func (s Service) Close(ctx context.Context) error {
+	return s.dispatcher.close(ctx)
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"context"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	findByID       = "findByID"
+	create         = "create"
+	list           = "list"
+	listEnabled    = "listEnabled"
+	update         = "update"
+	deleteByID     = "deleteByID"
+	saveDelivery   = "saveDelivery"
+	listDeliveries = "listDeliveries"
+	recordSuccess  = "recordSuccess"
+	recordFailure  = "recordFailure"
+	dispatch       = "dispatch"
+)
+
+type (
+	serviceTestSuite struct {
+		repoMock       *mockRepository
+		dispatcherMock *mockDispatcher
+		service        Service
+		suite.Suite
+	}
+)
+
+func TestServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(serviceTestSuite))
+}
+
+func (s *serviceTestSuite) SetupTest() {
+	s.repoMock = newMockRepository(s.T())
+	s.dispatcherMock = newMockDispatcher(s.T())
+	s.service = Service{
+		repository: s.repoMock,
+		dispatcher: s.dispatcherMock,
+	}
+}
+
+func (s *serviceTestSuite) TestCreate() {
+	s.repoMock.
+		On(create, mock.Anything, mock.MatchedBy(func(sub Subscription) bool {
+			return sub.ID != uuid.Nil &&
+				len(sub.Secret) == 64 &&
+				sub.URL == "https://example.com/hook" &&
+				len(sub.EventTypes) == 1
+		})).
+		Return(func(_ context.Context, sub Subscription) *Subscription { return &sub }, nil).
+		Once()
+
+	sub, err := s.service.Create(nil, Subscription{
+		URL:        "https://example.com/hook",
+		EventTypes: []user.UserEventType{user.UserEventTypeDeleted},
+		Enabled:    true,
+	})
+	s.NoError(err)
+	s.NotEmpty(sub.Secret)
+}
+
+func (s *serviceTestSuite) TestCreate_ReturnsErrorOnInvalidInput() {
+	for _, test := range []struct {
+		name          string
+		subscription  Subscription
+		expectedError error
+	}{
+		{
+			name:          "predefined id",
+			subscription:  Subscription{ID: uuid.New(), URL: "https://example.com"},
+			expectedError: ErrNewSubscriptionWithID,
+		},
+		{
+			name:          "http url",
+			subscription:  Subscription{URL: "http://example.com"},
+			expectedError: ErrInvalidSubscriptionInput,
+		},
+		{
+			name:          "missing host",
+			subscription:  Subscription{URL: "https:///hook"},
+			expectedError: ErrInvalidSubscriptionInput,
+		},
+		{
+			name:          "loopback address",
+			subscription:  Subscription{URL: "https://127.0.0.1/hook"},
+			expectedError: ErrInvalidSubscriptionInput,
+		},
+		{
+			name:          "private address",
+			subscription:  Subscription{URL: "https://10.0.0.5/hook"},
+			expectedError: ErrInvalidSubscriptionInput,
+		},
+		{
+			name:          "link-local address",
+			subscription:  Subscription{URL: "https://[fe80::1]/hook"},
+			expectedError: ErrInvalidSubscriptionInput,
+		},
+		{
+			name:          "cloud metadata address",
+			subscription:  Subscription{URL: "https://169.254.169.254/latest/meta-data"},
+			expectedError: ErrInvalidSubscriptionInput,
+		},
+		{
+			name:          "localhost",
+			subscription:  Subscription{URL: "https://localhost:8443/hook"},
+			expectedError: ErrInvalidSubscriptionInput,
+		},
+		{
+			name:          "unknown event type",
+			subscription:  Subscription{URL: "https://example.com", EventTypes: []user.UserEventType{"USER_LOGGED_IN"}},
+			expectedError: ErrInvalidSubscriptionInput,
+		},
+	} {
+		s.Run(test.name, func() {
+			_, err := s.service.Create(nil, test.subscription)
+			s.ErrorIs(err, test.expectedError)
+			s.repoMock.AssertNotCalled(s.T(), create)
+		})
+	}
+}
+
+func (s *serviceTestSuite) TestCreate_AllowsHTTPWhenConfigured() {
+	s.service.allowHTTP = true
+	s.service.allowPrivate = true
+	s.repoMock.
+		On(create, mock.Anything, mock.Anything).
+		Return(&Subscription{}, nil).
+		Once()
+
+	_, err := s.service.Create(nil, Subscription{URL: "http://localhost:8080/hook"})
+	s.NoError(err)
+}
+
+func (s *serviceTestSuite) TestUpdate() {
+	id := uuid.New()
+	changes := SubscriptionUpdate{Enabled: common.Ptr(true)}
+	s.repoMock.
+		On(update, mock.Anything, id, changes).
+		Return(&Subscription{ID: id, Enabled: true}, nil).
+		Once()
+
+	sub, err := s.service.Update(nil, id, changes)
+	s.NoError(err)
+	s.True(sub.Enabled)
+}
+
+func (s *serviceTestSuite) TestUpdate_ReturnsErrorOnInvalidInput() {
+	_, err := s.service.Update(nil, uuid.Nil, SubscriptionUpdate{})
+	s.ErrorIs(err, ErrNilUUIDNotAllowed)
+
+	_, err = s.service.Update(nil, uuid.New(), SubscriptionUpdate{URL: common.Ptr("ftp://example.com")})
+	s.ErrorIs(err, ErrInvalidSubscriptionInput)
+
+	_, err = s.service.Update(nil, uuid.New(), SubscriptionUpdate{EventTypes: &[]user.UserEventType{"x"}})
+	s.ErrorIs(err, ErrInvalidSubscriptionInput)
+
+	s.repoMock.AssertNotCalled(s.T(), update)
+}
+
+func (s *serviceTestSuite) TestListDeliveries() {
+	id := uuid.New()
+	pagination := common.Pagination{Page: 1, PageSize: 5}
+	s.repoMock.
+		On(findByID, mock.Anything, id).
+		Return(&Subscription{ID: id}, nil).
+		Once()
+	s.repoMock.
+		On(listDeliveries, mock.Anything, id, pagination).
+		Return([]Delivery{{SubscriptionID: id, Attempt: 2}}, nil).
+		Once()
+
+	deliveries, err := s.service.ListDeliveries(nil, id, pagination)
+	s.NoError(err)
+	s.Len(deliveries, 1)
+	s.Equal(2, deliveries[0].Attempt)
+}
+
+func (s *serviceTestSuite) TestListDeliveries_ReturnsNotFound() {
+	id := uuid.New()
+	s.repoMock.
+		On(findByID, mock.Anything, id).
+		Return(nil, ErrSubscriptionNotFound).
+		Once()
+
+	_, err := s.service.ListDeliveries(nil, id, common.Pagination{})
+	s.ErrorIs(err, ErrSubscriptionNotFound)
+	s.repoMock.AssertNotCalled(s.T(), listDeliveries)
+}
+
+func (s *serviceTestSuite) TestList_ReturnsErrorOnInvalidPagination() {
+	_, err := s.service.List(nil, common.Pagination{Page: -1})
+	s.ErrorIs(err, ErrInvalidPagination)
+	s.repoMock.AssertNotCalled(s.T(), list)
+}
+
+func (s *serviceTestSuite) TestOnUserEvent() {
+	event := user.UserEvent{Type: user.UserEventTypeDeleted, UserID: uuid.New()}
+	s.dispatcherMock.
+		On(dispatch, mock.Anything, event).
+		Return().
+		Once()
+
+	s.service.OnUserEvent(nil, event)
+}
@@ -1,11 +1,30 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"expvar"
+	apispec "faceit/api"
+	adminapi "faceit/internal/admin/api"
 	"faceit/internal/common"
+	"faceit/internal/inbound"
+	usersvc "faceit/internal/user"
 	"faceit/internal/user/api"
+	"faceit/internal/user/apiv2"
+	"faceit/internal/webhook"
+	webhookapi "faceit/internal/webhook/api"
+	"faceit/pkg/admin"
 	srv "faceit/pkg/server"
 	"faceit/pkg/user"
+	userpb "faceit/pkg/user/pb"
+	webhookhandler "faceit/pkg/webhook"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
@@ -20,19 +39,119 @@ func main() {
 	server.Use(srv.RequestIDMiddleware, srv.LoggerMiddleware, srv.CorsMiddleware)
 	server.HTTPErrorHandler = srv.HTTPErrorHandler
 
-	usersHandler, err := user.NewHandler()
+	webhooks, err := webhook.NewService()
 	if err != nil {
-		log.Fatal().Msgf("failed to create users handler: %+v", err)
+		log.Fatal().Msgf("failed to create webhook service: %+v", err)
 	}
-	api.RegisterHandlersWithBaseURL(server, usersHandler, "api/v1")
+	webhookapi.RegisterHandlersWithBaseURL(srv.CustomMethodRouter{Echo: server}, webhookhandler.NewHandler(webhooks), "api/v1")
 
-	health := srv.NewHealth()
+	// the user service is shared by the APIs and the inbound consumer, so they publish through the same event queue
+	users, err := usersvc.NewService(webhooks)
+	if err != nil {
+		log.Fatal().Msgf("failed to create user service: %+v", err)
+	}
+	usersHandler := user.NewHandler(users)
+	v1Router := srv.CustomMethodRouter{Echo: server}
+	if deprecation, ok := srv.NewV1Deprecation(); ok {
+		v1Router.Middlewares = []echo.MiddlewareFunc{srv.DeprecationMiddleware(deprecation)}
+	}
+	api.RegisterHandlersWithBaseURL(v1Router, usersHandler, "api/v1")
+	v2Router := srv.CustomMethodRouter{Echo: server, Middlewares: []echo.MiddlewareFunc{srv.ProblemDetailsMiddleware}}
+	apiv2.RegisterHandlersWithBaseURL(v2Router, user.NewHandlerV2(usersHandler), "api/v2")
+
+	graphQLHandler, err := user.NewGraphQLHandler(usersHandler)
+	if err != nil {
+		log.Fatal().Msgf("failed to create GraphQL handler: %+v", err)
+	}
+	server.POST("/graphql", graphQLHandler.Serve)
+
+	if adminAuth, ok := admin.NewAuthMiddleware(); ok {
+		adminRouter := srv.CustomMethodRouter{Echo: server, Middlewares: []echo.MiddlewareFunc{adminAuth}}
+		adminapi.RegisterHandlersWithBaseURL(adminRouter, admin.NewHandler(users.Replayer()), "api/v1")
+	}
+
+	inboundConfig, err := inbound.NewConfig()
+	if err != nil {
+		log.Fatal().Msgf("failed to read inbound consumer config: %+v", err)
+	}
+	var inboundConsumer *inbound.Consumer
+	if inboundConfig.Enabled() {
+		if inboundConsumer, err = inbound.NewConsumer(inboundConfig, users); err != nil {
+			log.Fatal().Msgf("failed to create inbound consumer: %+v", err)
+		}
+	}
+
+	health := srv.NewHealth(users.Connections())
 	server.GET("/health", health.Check)
+	server.GET("/metrics", echo.WrapHandler(expvar.Handler()))
+	server.GET("/asyncapi.yaml", func(ctx echo.Context) error {
+		return ctx.Blob(http.StatusOK, "application/yaml", apispec.AsyncAPI)
+	})
+	server.StaticFS("/schemas", echo.MustSubFS(apispec.EventSchemas, "schemas"))
+	server.GET("/user_events.proto", func(ctx echo.Context) error {
+		return ctx.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, apispec.UserEventsProto)
+	})
+	server.GET("/users.graphql", func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, apispec.UsersGraphQL)
+	})
 
 	host := common.GetEnv("SERVER_HOST", "localhost")
 	port := common.GetEnv("SERVER_PORT", "8000")
 
-	if err := server.Start(fmt.Sprintf("%s:%s", host, port)); err != nil {
-		log.Fatal().Msgf("failed to start server: %+v", err)
+	grpcServer := srv.NewGRPCServer()
+	userpb.RegisterUserServiceServer(grpcServer, user.NewGRPCServer(usersHandler))
+	grpcPort := common.GetEnv("GRPC_PORT", "9090")
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%s", host, grpcPort))
+	if err != nil {
+		log.Fatal().Msgf("failed to listen on gRPC port: %+v", err)
+	}
+
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatal().Msgf("failed to start gRPC server: %+v", err)
+		}
+	}()
+
+	go func() {
+		if err := server.Start(fmt.Sprintf("%s:%s", host, port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Msgf("failed to start server: %+v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	t := common.GetEnv("SHUTDOWN_TIMEOUT", "10s")
+	timeout, err := time.ParseDuration(t)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to parse shutdown timeout")
+		timeout = 10 * time.Second
+	}
+
+	// the open event streams only end when their clients disconnect, so they are closed before waiting for the calls
+	// of the servers, what ends the gRPC Watch streams as well
+	server.Server.RegisterOnShutdown(users.CloseStreams)
+
+	log.Info().Msg("shutting down server")
+	closeWithTimeout(timeout, "failed to shut down server", server.Shutdown)
+	closeWithTimeout(timeout, "", func(ctx context.Context) error {
+		srv.StopGRPCServer(ctx, grpcServer)
+		return nil
+	})
+	if inboundConsumer != nil {
+		closeWithTimeout(timeout, "failed to close inbound consumer", inboundConsumer.Close)
+	}
+	closeWithTimeout(timeout, "failed to flush pending user events", users.Close)
+	closeWithTimeout(timeout, "failed to finish pending webhook deliveries", webhooks.Close)
+}
+
+// closeWithTimeout calls close with its own timeout, so a slow close doesn't leave an expired context to the next ones
+func closeWithTimeout(timeout time.Duration, errMsg string, close func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := close(ctx); err != nil {
+		log.Err(err).Msg(errMsg)
 	}
 }
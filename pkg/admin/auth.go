@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"faceit/internal/common"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// NewAuthMiddleware returns the middleware what accepts only the admin requests with the ADMIN_API_TOKEN bearer token.
+// The admin API could republish every stored event, so it's served only when the token is set, false is returned
+// otherwise.
+func NewAuthMiddleware() (echo.MiddlewareFunc, bool) {
+	token := common.GetEnv("ADMIN_API_TOKEN", "")
+	if token == "" {
+		return nil, false
+	}
+	return authMiddleware(token), true
+}
+
+func authMiddleware(token string) echo.MiddlewareFunc {
+	return middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		Validator: func(key string, _ echo.Context) (bool, error) {
+			return subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1, nil
+		},
+		ErrorHandler: func(err error, _ echo.Context) error {
+			return echo.NewHTTPError(http.StatusUnauthorized, "missing or invalid admin token")
+		},
+	})
+}
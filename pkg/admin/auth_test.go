@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	authTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(authTestSuite))
+}
+
+func (s *authTestSuite) TestNewAuthMiddleware_DisabledWithoutToken() {
+	s.T().Setenv("ADMIN_API_TOKEN", "")
+	_, ok := NewAuthMiddleware()
+	s.False(ok)
+}
+
+func (s *authTestSuite) TestAuthMiddleware() {
+	next := func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }
+	handler := authMiddleware("secret")(next)
+
+	for _, test := range []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "valid token", authorization: "Bearer secret", expectedStatus: http.StatusOK},
+		{name: "invalid token", authorization: "Bearer other", expectedStatus: http.StatusUnauthorized},
+		{name: "missing token", expectedStatus: http.StatusUnauthorized},
+		{name: "other scheme", authorization: "Basic secret", expectedStatus: http.StatusUnauthorized},
+	} {
+		s.Run(test.name, func() {
+			req := httptest.NewRequest(http.MethodPost, adminUrl+"/replay", nil)
+			if test.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, test.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			err := handler(echo.New().NewContext(req, rec))
+			if test.expectedStatus == http.StatusOK {
+				s.NoError(err)
+				s.Equal(http.StatusOK, rec.Code)
+				return
+			}
+			s.Equal(test.expectedStatus, err.(*echo.HTTPError).Code)
+		})
+	}
+}
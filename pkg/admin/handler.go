@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"context"
+	"faceit/internal/admin/api"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"faceit/pkg/server"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	replayer interface {
+		Replay(ctx context.Context, req user.ReplayRequest) (int, error)
+		Snapshot(ctx context.Context, target user.ReplayTarget) (int, error)
+	}
+
+	Handler struct {
+		timeout  time.Duration
+		replayer replayer
+	}
+)
+
+// NewHandler creates the admin handlers on top of the replayer of the user service (see user.Service.Replayer).
+// The replay could take long, so it has a separate REPLAY_TIMEOUT instead of the request timeout.
+func NewHandler(replayer *user.Replayer) *Handler {
+	return &Handler{
+		timeout:  common.GetEnvDuration("REPLAY_TIMEOUT", 5*time.Minute),
+		replayer: replayer,
+	}
+}
+
+func (h Handler) ReplayEvents(ctx echo.Context) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	var in *api.ReplayRequest
+	if err := ctx.Bind(&in); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if in == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing request body")
+	}
+
+	req := user.ReplayRequest{
+		ReplayTarget: toReplayTarget(api.ReplayTarget{Exchange: in.Exchange, RoutingKey: in.RoutingKey}),
+	}
+	if in.Since != nil {
+		req.Since = *in.Since
+	}
+	if in.UserIds != nil {
+		req.UserIDs = *in.UserIds
+	}
+	if in.EventTypes != nil {
+		for _, t := range *in.EventTypes {
+			req.Types = append(req.Types, user.UserEventType(t))
+		}
+	}
+
+	published, err := h.replayer.Replay(c, req)
+	if err != nil {
+		return h.handleError(c, err, "ReplayEvents", published)
+	}
+
+	log.Info().
+		Str(common.CorrelationID, common.GetCorrelationID(c)).
+		Int("published", published).
+		Msg("user events replayed")
+	return ctx.JSON(http.StatusOK, api.ReplayResult{Published: published})
+}
+
+func (h Handler) PublishSnapshot(ctx echo.Context) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	var in *api.ReplayTarget
+	if err := ctx.Bind(&in); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if in == nil {
+		in = &api.ReplayTarget{}
+	}
+
+	published, err := h.replayer.Snapshot(c, toReplayTarget(*in))
+	if err != nil {
+		return h.handleError(c, err, "PublishSnapshot", published)
+	}
+
+	log.Info().
+		Str(common.CorrelationID, common.GetCorrelationID(c)).
+		Int("published", published).
+		Msg("user snapshot published")
+	return ctx.JSON(http.StatusOK, api.ReplayResult{Published: published})
+}
+
+func (h Handler) handleError(ctx context.Context, err error, operation string, published int) error {
+	log.Err(err).
+		Str("operation", operation).
+		Str(common.CorrelationID, common.GetCorrelationID(ctx)).
+		Int("published", published).
+		Send()
+
+	return server.NewHTTPError(err)
+}
+
+func (h Handler) contextWithTimeout(ctx echo.Context) (context.Context, context.CancelFunc) {
+	ec := ctx.Request().Context()
+	c := context.WithValue(ec, common.CorrelationID, common.GetEchoCorrelationID(ctx))
+	return context.WithTimeout(c, h.timeout)
+}
+
+func toReplayTarget(t api.ReplayTarget) user.ReplayTarget {
+	target := user.ReplayTarget{}
+	if t.Exchange != nil {
+		target.Exchange = *t.Exchange
+	}
+	if t.RoutingKey != nil {
+		target.RoutingKey = *t.RoutingKey
+	}
+	return target
+}
@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"faceit/internal/admin/api"
+	"faceit/internal/user"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	adminUrl = "/api/v1/admin/events"
+	Replay   = "Replay"
+	Snapshot = "Snapshot"
+)
+
+type (
+	handlerTestSuite struct {
+		replayerMock *mockReplayer
+		handler      Handler
+		wrapper      api.ServerInterfaceWrapper
+		e            *echo.Echo
+		suite.Suite
+	}
+)
+
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(handlerTestSuite))
+}
+
+func (s *handlerTestSuite) SetupTest() {
+	s.e = echo.New()
+	s.replayerMock = newMockReplayer(s.T())
+	s.handler = Handler{
+		timeout:  time.Second,
+		replayer: s.replayerMock,
+	}
+
+	// register wrapper to test OpenAPI validation as well
+	s.wrapper = api.ServerInterfaceWrapper{
+		Handler: s.handler,
+	}
+}
+
+func (s *handlerTestSuite) TestReplayEvents() {
+	userID := uuid.New()
+	since := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	expected := user.ReplayRequest{
+		ReplayTarget: user.ReplayTarget{Exchange: "events.rebuild", RoutingKey: "billing"},
+		EventFilter: user.EventFilter{
+			Types:   []user.UserEventType{user.UserEventTypeUpdated},
+			UserIDs: []uuid.UUID{userID},
+			Since:   since,
+		},
+	}
+	s.replayerMock.
+		On(Replay, mock.Anything, expected).
+		Return(3, nil).
+		Once()
+
+	body := fmt.Sprintf(`{"exchange":"events.rebuild","routing_key":"billing","since":"2023-01-02T15:04:05Z","user_ids":["%s"],"event_types":["USER_UPDATED"]}`, userID)
+	ctx, rec := s.call(adminUrl+"/replay", strings.NewReader(body))
+
+	s.NoError(s.wrapper.ReplayEvents(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+
+	var res api.ReplayResult
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Equal(3, res.Published)
+}
+
+func (s *handlerTestSuite) TestReplayEvents_ReturnsError() {
+	for _, test := range []struct {
+		name           string
+		returnErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "invalid request",
+			returnErr:      fmt.Errorf("%w: exchange missing doesn't exist", user.ErrInvalidReplayRequest),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "publish error",
+			returnErr:      errors.New("channel closed"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.replayerMock.
+				On(Replay, mock.Anything, mock.Anything).
+				Return(0, test.returnErr).
+				Once()
+
+			ctx, _ := s.call(adminUrl+"/replay", strings.NewReader(`{"exchange":"missing"}`))
+
+			err := s.wrapper.ReplayEvents(ctx).(*echo.HTTPError)
+			s.Equal(test.expectedStatus, err.Code)
+		})
+	}
+}
+
+func (s *handlerTestSuite) TestPublishSnapshot() {
+	s.replayerMock.
+		On(Snapshot, mock.Anything, user.ReplayTarget{RoutingKey: "search"}).
+		Return(42, nil).
+		Once()
+
+	ctx, rec := s.call(adminUrl+"/snapshot", strings.NewReader(`{"routing_key":"search"}`))
+
+	s.NoError(s.wrapper.PublishSnapshot(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+
+	var res api.ReplayResult
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Equal(42, res.Published)
+}
+
+func (s *handlerTestSuite) call(url string, body io.Reader) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, url, body)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	return s.e.NewContext(req, rec), rec
+}
@@ -0,0 +1,72 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package admin
+
+import (
+	context "context"
+	user "faceit/internal/user"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockReplayer is an autogenerated mock type for the replayer type
+type mockReplayer struct {
+	mock.Mock
+}
+
+// Replay provides a mock function with given fields: ctx, req
+func (_m *mockReplayer) Replay(ctx context.Context, req user.ReplayRequest) (int, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, user.ReplayRequest) int); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, user.ReplayRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Snapshot provides a mock function with given fields: ctx, target
+func (_m *mockReplayer) Snapshot(ctx context.Context, target user.ReplayTarget) (int, error) {
+	ret := _m.Called(ctx, target)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, user.ReplayTarget) int); ok {
+		r0 = rf(ctx, target)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, user.ReplayTarget) error); ok {
+		r1 = rf(ctx, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTnewMockReplayer interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockReplayer creates a new instance of mockReplayer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockReplayer(t mockConstructorTestingTnewMockReplayer) *mockReplayer {
+	mock := &mockReplayer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,39 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package api
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockClientOption is an autogenerated mock type for the ClientOption type
+type MockClientOption struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *MockClientOption) Execute(_a0 *Client) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*Client) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMockClientOption interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockClientOption creates a new instance of MockClientOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockClientOption(t mockConstructorTestingTNewMockClientOption) *MockClientOption {
+	mock := &MockClientOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
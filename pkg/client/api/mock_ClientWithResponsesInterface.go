@@ -0,0 +1,422 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package api
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockClientWithResponsesInterface is an autogenerated mock type for the ClientWithResponsesInterface type
+type MockClientWithResponsesInterface struct {
+	mock.Mock
+}
+
+// BatchGetWithBodyWithResponse provides a mock function with given fields: ctx, params, contentType, body, reqEditors
+func (_m *MockClientWithResponsesInterface) BatchGetWithBodyWithResponse(ctx context.Context, params *BatchGetParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*BatchGetResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params, contentType, body)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *BatchGetResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *BatchGetParams, string, io.Reader, ...RequestEditorFn) *BatchGetResponse); ok {
+		r0 = rf(ctx, params, contentType, body, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*BatchGetResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *BatchGetParams, string, io.Reader, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, params, contentType, body, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BatchGetWithResponse provides a mock function with given fields: ctx, params, body, reqEditors
+func (_m *MockClientWithResponsesInterface) BatchGetWithResponse(ctx context.Context, params *BatchGetParams, body BatchGetRequest, reqEditors ...RequestEditorFn) (*BatchGetResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params, body)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *BatchGetResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *BatchGetParams, BatchGetRequest, ...RequestEditorFn) *BatchGetResponse); ok {
+		r0 = rf(ctx, params, body, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*BatchGetResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *BatchGetParams, BatchGetRequest, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, params, body, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWithBodyWithResponse provides a mock function with given fields: ctx, params, contentType, body, reqEditors
+func (_m *MockClientWithResponsesInterface) CreateWithBodyWithResponse(ctx context.Context, params *CreateParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CreateResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params, contentType, body)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *CreateResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *CreateParams, string, io.Reader, ...RequestEditorFn) *CreateResponse); ok {
+		r0 = rf(ctx, params, contentType, body, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*CreateResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *CreateParams, string, io.Reader, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, params, contentType, body, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWithResponse provides a mock function with given fields: ctx, params, body, reqEditors
+func (_m *MockClientWithResponsesInterface) CreateWithResponse(ctx context.Context, params *CreateParams, body UserWithPassword, reqEditors ...RequestEditorFn) (*CreateResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params, body)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *CreateResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *CreateParams, UserWithPassword, ...RequestEditorFn) *CreateResponse); ok {
+		r0 = rf(ctx, params, body, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*CreateResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *CreateParams, UserWithPassword, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, params, body, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteByIDWithResponse provides a mock function with given fields: ctx, id, reqEditors
+func (_m *MockClientWithResponsesInterface) DeleteByIDWithResponse(ctx context.Context, id uuid.UUID, reqEditors ...RequestEditorFn) (*DeleteByIDResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *DeleteByIDResponse
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, ...RequestEditorFn) *DeleteByIDResponse); ok {
+		r0 = rf(ctx, id, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DeleteByIDResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, id, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIDWithResponse provides a mock function with given fields: ctx, id, params, reqEditors
+func (_m *MockClientWithResponsesInterface) GetByIDWithResponse(ctx context.Context, id uuid.UUID, params *GetByIDParams, reqEditors ...RequestEditorFn) (*GetByIDResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *GetByIDResponse
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *GetByIDParams, ...RequestEditorFn) *GetByIDResponse); ok {
+		r0 = rf(ctx, id, params, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*GetByIDResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *GetByIDParams, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, id, params, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEventKeysWithResponse provides a mock function with given fields: ctx, reqEditors
+func (_m *MockClientWithResponsesInterface) ListEventKeysWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ListEventKeysResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *ListEventKeysResponse
+	if rf, ok := ret.Get(0).(func(context.Context, ...RequestEditorFn) *ListEventKeysResponse); ok {
+		r0 = rf(ctx, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ListEventKeysResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWithResponse provides a mock function with given fields: ctx, params, reqEditors
+func (_m *MockClientWithResponsesInterface) ListWithResponse(ctx context.Context, params *ListParams, reqEditors ...RequestEditorFn) (*ListResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *ListResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *ListParams, ...RequestEditorFn) *ListResponse); ok {
+		r0 = rf(ctx, params, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ListResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *ListParams, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, params, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchWithBodyWithResponse provides a mock function with given fields: ctx, params, contentType, body, reqEditors
+func (_m *MockClientWithResponsesInterface) SearchWithBodyWithResponse(ctx context.Context, params *SearchParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*SearchResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params, contentType, body)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *SearchResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *SearchParams, string, io.Reader, ...RequestEditorFn) *SearchResponse); ok {
+		r0 = rf(ctx, params, contentType, body, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*SearchResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *SearchParams, string, io.Reader, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, params, contentType, body, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchWithResponse provides a mock function with given fields: ctx, params, body, reqEditors
+func (_m *MockClientWithResponsesInterface) SearchWithResponse(ctx context.Context, params *SearchParams, body SearchRequest, reqEditors ...RequestEditorFn) (*SearchResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params, body)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *SearchResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *SearchParams, SearchRequest, ...RequestEditorFn) *SearchResponse); ok {
+		r0 = rf(ctx, params, body, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*SearchResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *SearchParams, SearchRequest, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, params, body, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamEventsWithResponse provides a mock function with given fields: ctx, params, reqEditors
+func (_m *MockClientWithResponsesInterface) StreamEventsWithResponse(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*StreamEventsResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *StreamEventsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *StreamEventsParams, ...RequestEditorFn) *StreamEventsResponse); ok {
+		r0 = rf(ctx, params, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*StreamEventsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *StreamEventsParams, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, params, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateByIDWithBodyWithResponse provides a mock function with given fields: ctx, id, contentType, body, reqEditors
+func (_m *MockClientWithResponsesInterface) UpdateByIDWithBodyWithResponse(ctx context.Context, id uuid.UUID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*UpdateByIDResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id, contentType, body)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *UpdateByIDResponse
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, io.Reader, ...RequestEditorFn) *UpdateByIDResponse); ok {
+		r0 = rf(ctx, id, contentType, body, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*UpdateByIDResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, io.Reader, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, id, contentType, body, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateByIDWithResponse provides a mock function with given fields: ctx, id, body, reqEditors
+func (_m *MockClientWithResponsesInterface) UpdateByIDWithResponse(ctx context.Context, id uuid.UUID, body UpdateUserWithPassword, reqEditors ...RequestEditorFn) (*UpdateByIDResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id, body)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *UpdateByIDResponse
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, UpdateUserWithPassword, ...RequestEditorFn) *UpdateByIDResponse); ok {
+		r0 = rf(ctx, id, body, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*UpdateByIDResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, UpdateUserWithPassword, ...RequestEditorFn) error); ok {
+		r1 = rf(ctx, id, body, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewMockClientWithResponsesInterface interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockClientWithResponsesInterface creates a new instance of MockClientWithResponsesInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockClientWithResponsesInterface(t mockConstructorTestingTNewMockClientWithResponsesInterface) *MockClientWithResponsesInterface {
+	mock := &MockClientWithResponsesInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
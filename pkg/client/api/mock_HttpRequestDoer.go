@@ -0,0 +1,52 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package api
+
+import (
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockHttpRequestDoer is an autogenerated mock type for the HttpRequestDoer type
+type MockHttpRequestDoer struct {
+	mock.Mock
+}
+
+// Do provides a mock function with given fields: req
+func (_m *MockHttpRequestDoer) Do(req *http.Request) (*http.Response, error) {
+	ret := _m.Called(req)
+
+	var r0 *http.Response
+	if rf, ok := ret.Get(0).(func(*http.Request) *http.Response); ok {
+		r0 = rf(req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*http.Response)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*http.Request) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewMockHttpRequestDoer interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockHttpRequestDoer creates a new instance of MockHttpRequestDoer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockHttpRequestDoer(t mockConstructorTestingTNewMockHttpRequestDoer) *MockHttpRequestDoer {
+	mock := &MockHttpRequestDoer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,44 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package api
+
+import (
+	context "context"
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRequestEditorFn is an autogenerated mock type for the RequestEditorFn type
+type MockRequestEditorFn struct {
+	mock.Mock
+}
+
+// Execute provides a mock function with given fields: ctx, req
+func (_m *MockRequestEditorFn) Execute(ctx context.Context, req *http.Request) error {
+	ret := _m.Called(ctx, req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *http.Request) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMockRequestEditorFn interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockRequestEditorFn creates a new instance of MockRequestEditorFn. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockRequestEditorFn(t mockConstructorTestingTNewMockRequestEditorFn) *MockRequestEditorFn {
+	mock := &MockRequestEditorFn{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,311 @@
+// Package client is a Go client of the users API built on the client generated from `api/users.yaml`.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"faceit/internal/common"
+	"faceit/pkg/client/api"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// HeaderRequestID is the header of the correlation id
+	HeaderRequestID = "X-Request-Id"
+	// HeaderIdempotencyKey makes the non-idempotent requests retryable
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderMissingIDs lists the ids of the users what were not found by BatchGet
+	HeaderMissingIDs = "Missing-Ids"
+
+	// PatchTypeMerge is the content type of a JSON Merge Patch (RFC 7396)
+	PatchTypeMerge = "application/merge-patch+json"
+	// PatchTypeJSON is the content type of a JSON Patch (RFC 6902)
+	PatchTypeJSON = "application/json-patch+json"
+)
+
+var (
+	ErrBadRequest = errors.New("bad request")
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrServer     = errors.New("server error")
+)
+
+type (
+	// Error is returned when the API responds with an error status
+	Error struct {
+		StatusCode    int
+		CorrelationID uuid.UUID
+		Message       string
+		Time          time.Time
+	}
+
+	// Option configures the Client
+	Option func(*options)
+
+	options struct {
+		httpClient api.HttpRequestDoer
+		retry      retryConfig
+	}
+
+	// Client calls the users API. The idempotent calls and the calls with idempotency key are retried on network errors and unavailable responses,
+	// and every request has an X-Request-Id header with the correlation id of the context or a new one.
+	Client struct {
+		api api.ClientWithResponsesInterface
+	}
+)
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("users API responded %d: %s (correlation id %s)", e.StatusCode, e.Message, e.CorrelationID)
+}
+
+// Is makes the errors comparable by their status with ErrBadRequest, ErrNotFound and ErrServer.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// WithHTTPClient sets the HTTP client what sends the requests (default is http.DefaultClient).
+func WithHTTPClient(doer api.HttpRequestDoer) Option {
+	return func(o *options) {
+		o.httpClient = doer
+	}
+}
+
+// WithRetry sets the maximum number of attempts of the idempotent calls and the backoff between them.
+// The backoff is doubled after every attempt up to maxBackoff. Retries are disabled with 1 attempt.
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.retry = retryConfig{
+			maxAttempts:    maxAttempts,
+			initialBackoff: initialBackoff,
+			maxBackoff:     maxBackoff,
+		}
+	}
+}
+
+// WithRequestID returns a context what sets the correlation id of the requests.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, common.CorrelationID, requestID)
+}
+
+// New creates a Client for the server URL including the API base path (i.e. `http://localhost:8000/api/v1`).
+func New(server string, opts ...Option) (*Client, error) {
+	o := options{
+		httpClient: http.DefaultClient,
+		retry: retryConfig{
+			maxAttempts:    3,
+			initialBackoff: 100 * time.Millisecond,
+			maxBackoff:     2 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c, err := api.NewClientWithResponses(server,
+		api.WithHTTPClient(retryingDoer{doer: o.httpClient, config: o.retry}),
+		api.WithRequestEditorFn(setRequestID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{api: c}, nil
+}
+
+// API returns the generated client, i.e. to consume the event stream.
+func (c Client) API() api.ClientWithResponsesInterface {
+	return c.api
+}
+
+// Get returns the user by id.
+func (c Client) Get(ctx context.Context, id uuid.UUID) (*api.UserResponse, error) {
+	res, err := c.api.GetByIDWithResponse(ctx, id, &api.GetByIDParams{})
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, toError(res.HTTPResponse, res.Body)
+	}
+	return res.JSON200, nil
+}
+
+// List returns a page of the filtered users.
+func (c Client) List(ctx context.Context, params api.ListParams) ([]api.UserResponse, error) {
+	res, err := c.api.ListWithResponse(ctx, &params)
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, toError(res.HTTPResponse, res.Body)
+	}
+	return *res.JSON200, nil
+}
+
+// BatchGet returns the users of the ids (at most 100) in the order of the ids with the ids of the missing users
+// in a single request. It lists the users by the ids, so it is retried like the other idempotent calls.
+func (c Client) BatchGet(ctx context.Context, ids []uuid.UUID) ([]api.UserResponse, []uuid.UUID, error) {
+	res, err := c.api.ListWithResponse(ctx, &api.ListParams{Id: &ids})
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, nil, toError(res.HTTPResponse, res.Body)
+	}
+
+	missing := []uuid.UUID{}
+	if header := res.HTTPResponse.Header.Get(HeaderMissingIDs); header != "" {
+		for _, s := range strings.Split(header, ",") {
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid %s header: %w", HeaderMissingIDs, err)
+			}
+			missing = append(missing, id)
+		}
+	}
+	return *res.JSON200, missing, nil
+}
+
+// Create creates a new user. The request is sent with a new idempotency key,
+// so it is retried like the idempotent calls without creating duplicated users.
+func (c Client) Create(ctx context.Context, user api.UserWithPassword) (*api.UserResponse, error) {
+	return c.CreateWithKey(ctx, uuid.NewString(), user)
+}
+
+// CreateWithKey creates a new user with the given idempotency key, i.e. to retry a request what failed earlier.
+func (c Client) CreateWithKey(ctx context.Context, idempotencyKey string, user api.UserWithPassword) (*api.UserResponse, error) {
+	res, err := c.api.CreateWithResponse(ctx, &api.CreateParams{IdempotencyKey: &idempotencyKey}, user)
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON201 == nil {
+		return nil, toError(res.HTTPResponse, res.Body)
+	}
+	return res.JSON201, nil
+}
+
+// Update changes the set fields of the user. It is not retried because it is not idempotent.
+func (c Client) Update(ctx context.Context, id uuid.UUID, user api.UpdateUserWithPassword) (*api.UserResponse, error) {
+	res, err := c.api.UpdateByIDWithResponse(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, toError(res.HTTPResponse, res.Body)
+	}
+	return res.JSON200, nil
+}
+
+// Replace saves every field of the user, the password is only changed when it's set.
+// It is retried like the other idempotent calls.
+func (c Client) Replace(ctx context.Context, id uuid.UUID, user api.UserWithPassword) (*api.UserResponse, error) {
+	res, err := c.api.ReplaceByIDWithResponse(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, toError(res.HTTPResponse, res.Body)
+	}
+	return res.JSON200, nil
+}
+
+// Patch applies a JSON Merge Patch (PatchTypeMerge) or a JSON Patch (PatchTypeJSON) to the user.
+// A failed `test` operation of the JSON Patch returns ErrConflict, like a change of the user since it was read.
+// It is not retried because it is not idempotent.
+func (c Client) Patch(ctx context.Context, id uuid.UUID, patchType string, patch []byte) (*api.UserResponse, error) {
+	res, err := c.api.UpdateByIDWithBodyWithResponse(ctx, id, patchType, bytes.NewReader(patch))
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		return nil, toError(res.HTTPResponse, res.Body)
+	}
+	return res.JSON200, nil
+}
+
+// Delete removes the user.
+func (c Client) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := c.api.DeleteByIDWithResponse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() != http.StatusNoContent {
+		return toError(res.HTTPResponse, res.Body)
+	}
+	return nil
+}
+
+// ForEach calls fn with every user matching the filters of the params, walking all the pages from params.Page.
+// It stops on the first error returned by fn. The pages are offset based,
+// so the users created or deleted during the iteration could be skipped or repeated.
+func (c Client) ForEach(ctx context.Context, params api.ListParams, fn func(api.UserResponse) error) error {
+	if params.Page == nil {
+		params.Page = common.Ptr(0)
+	}
+	if params.Pagesize == nil {
+		params.Pagesize = common.Ptr(100)
+	}
+
+	for {
+		users, err := c.List(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			if err := fn(u); err != nil {
+				return err
+			}
+		}
+		if len(users) < *params.Pagesize {
+			return nil
+		}
+		params.Page = common.Ptr(*params.Page + 1)
+	}
+}
+
+// All returns every user matching the filters of the params from all the pages.
+func (c Client) All(ctx context.Context, params api.ListParams) ([]api.UserResponse, error) {
+	users := []api.UserResponse{}
+	err := c.ForEach(ctx, params, func(u api.UserResponse) error {
+		users = append(users, u)
+		return nil
+	})
+	return users, err
+}
+
+func setRequestID(ctx context.Context, req *http.Request) error {
+	requestID := common.GetCorrelationID(ctx)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	req.Header.Set(HeaderRequestID, requestID)
+	return nil
+}
+
+func toError(res *http.Response, body []byte) error {
+	e := Error{StatusCode: res.StatusCode}
+	var apiErr api.Error
+	if err := json.Unmarshal(body, &apiErr); err == nil {
+		e.CorrelationID = apiErr.CorrelationId
+		e.Message = apiErr.Message
+		e.Time = apiErr.Time
+	} else {
+		e.Message = http.StatusText(res.StatusCode)
+	}
+	return &e
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"faceit/internal/common"
+	"faceit/pkg/client/api"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	clientTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(clientTestSuite))
+}
+
+func (s *clientTestSuite) TestGet_RetriesUnavailableServer() {
+	id := uuid.New()
+	var calls int32
+	requestIDs := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs <- r.Header.Get(HeaderRequestID)
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, userResponse("johndoe"))
+	}))
+	defer server.Close()
+
+	c := s.client(server.URL)
+	u, err := c.Get(WithRequestID(context.TODO(), "test-request-id"), id)
+	s.NoError(err)
+	s.Equal("johndoe", u.Nickname)
+	s.EqualValues(3, atomic.LoadInt32(&calls))
+	for i := 0; i < 3; i++ {
+		s.Equal("test-request-id", <-requestIDs)
+	}
+}
+
+func (s *clientTestSuite) TestCreate_IsRetriedWithSameIdempotencyKey() {
+	var calls int32
+	keys := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get(HeaderIdempotencyKey)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		s.writeJSON(w, http.StatusCreated, userResponse("johndoe"))
+	}))
+	defer server.Close()
+
+	u, err := s.client(server.URL).Create(context.TODO(), api.UserWithPassword{Nickname: "johndoe", Email: "johndoe@email.com"})
+	s.NoError(err)
+	s.Equal("johndoe", u.Nickname)
+	s.EqualValues(2, atomic.LoadInt32(&calls))
+	first := <-keys
+	s.NotEmpty(first)
+	s.Equal(first, <-keys)
+}
+
+func (s *clientTestSuite) TestUpdate_IsNotRetried() {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		s.NotEmpty(r.Header.Get(HeaderRequestID))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := s.client(server.URL).Update(context.TODO(), uuid.New(), api.UpdateUserWithPassword{Nickname: common.Ptr("johndoe")})
+	s.ErrorIs(err, ErrServer)
+	s.EqualValues(1, atomic.LoadInt32(&calls))
+}
+
+func (s *clientTestSuite) TestPatch_ReturnsConflict() {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		s.Equal(PatchTypeJSON, r.Header.Get("Content-Type"))
+		s.writeJSON(w, http.StatusConflict, api.Error{Message: "patch test failed", Status: http.StatusConflict})
+	}))
+	defer server.Close()
+
+	_, err := s.client(server.URL).Patch(context.TODO(), uuid.New(), PatchTypeJSON,
+		[]byte(`[{"op": "test", "path": "/nickname", "value": "johndoe"}]`))
+	s.ErrorIs(err, ErrConflict)
+	s.EqualValues(1, atomic.LoadInt32(&calls))
+}
+
+func (s *clientTestSuite) TestGet_ReturnsTypedError() {
+	correlationID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.writeJSON(w, http.StatusNotFound, api.Error{
+			CorrelationId: correlationID,
+			Message:       "user not found",
+			Status:        http.StatusNotFound,
+			Time:          time.Now(),
+		})
+	}))
+	defer server.Close()
+
+	_, err := s.client(server.URL).Get(context.TODO(), uuid.New())
+	s.ErrorIs(err, ErrNotFound)
+	s.NotErrorIs(err, ErrBadRequest)
+
+	var apiErr *Error
+	s.True(errors.As(err, &apiErr))
+	s.Equal(correlationID, apiErr.CorrelationID)
+	s.Equal("user not found", apiErr.Message)
+}
+
+func (s *clientTestSuite) TestAll_WalksEveryPage() {
+	pages := [][]api.UserResponse{
+		{userResponse("user1"), userResponse("user2")},
+		{userResponse("user3"), userResponse("user4")},
+		{userResponse("user5")},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		s.Equal("2", r.URL.Query().Get("pagesize"))
+		s.Equal("US", r.URL.Query().Get("country"))
+		s.writeJSON(w, http.StatusOK, pages[page])
+	}))
+	defer server.Close()
+
+	users, err := s.client(server.URL).All(context.TODO(), api.ListParams{
+		Pagesize: common.Ptr(2),
+		Country:  common.Ptr("US"),
+	})
+	s.NoError(err)
+	s.Len(users, 5)
+	for i, u := range users {
+		s.Equal(fmt.Sprintf("user%d", i+1), u.Nickname)
+	}
+}
+
+func (s *clientTestSuite) TestBatchGet_ReturnsMissingIDs() {
+	found, missing := userResponse("johndoe"), uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal([]string{found.Id.String(), missing.String()}, r.URL.Query()["id"])
+		w.Header().Set(HeaderMissingIDs, missing.String())
+		s.writeJSON(w, http.StatusOK, []api.UserResponse{found})
+	}))
+	defer server.Close()
+
+	users, missingIDs, err := s.client(server.URL).BatchGet(context.TODO(), []uuid.UUID{found.Id, missing})
+	s.NoError(err)
+	s.Equal([]api.UserResponse{found}, users)
+	s.Equal([]uuid.UUID{missing}, missingIDs)
+}
+
+func (s *clientTestSuite) TestForEach_StopsOnError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.writeJSON(w, http.StatusOK, []api.UserResponse{userResponse("user1"), userResponse("user2")})
+	}))
+	defer server.Close()
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := s.client(server.URL).ForEach(context.TODO(), api.ListParams{Pagesize: common.Ptr(2)}, func(api.UserResponse) error {
+		calls++
+		return errStop
+	})
+	s.ErrorIs(err, errStop)
+	s.Equal(1, calls)
+}
+
+func (s *clientTestSuite) TestBackoff() {
+	r := retryingDoer{config: retryConfig{initialBackoff: time.Second, maxBackoff: 3 * time.Second}}
+	s.Equal(time.Second, r.backoff(1))
+	s.Equal(2*time.Second, r.backoff(2))
+	s.Equal(3*time.Second, r.backoff(3))
+}
+
+func (s *clientTestSuite) client(url string) *Client {
+	c, err := New(url, WithRetry(3, time.Millisecond, time.Millisecond))
+	s.Require().NoError(err)
+	return c
+}
+
+func (s *clientTestSuite) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	s.NoError(json.NewEncoder(w).Encode(body))
+}
+
+func userResponse(nickname string) api.UserResponse {
+	return api.UserResponse{
+		Id:       uuid.New(),
+		Nickname: nickname,
+		Email:    types.Email(nickname + "@email.com"),
+	}
+}
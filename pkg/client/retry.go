@@ -0,0 +1,83 @@
+package client
+
+import (
+	"faceit/pkg/client/api"
+	"io"
+	"net/http"
+	"time"
+)
+
+type (
+	retryConfig struct {
+		maxAttempts    int
+		initialBackoff time.Duration
+		maxBackoff     time.Duration
+	}
+
+	// retryingDoer retries the idempotent requests and the requests with idempotency key on network errors and on responses what mean the server is temporarily unavailable
+	retryingDoer struct {
+		doer   api.HttpRequestDoer
+		config retryConfig
+	}
+)
+
+func (r retryingDoer) Do(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req.Method) && req.Header.Get(HeaderIdempotencyKey) == "" {
+		return r.doer.Do(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		res, err := r.doer.Do(req)
+		if attempt >= r.config.maxAttempts || !shouldRetry(res, err) {
+			return res, err
+		}
+		if res != nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(r.backoff(attempt)):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+func (r retryingDoer) backoff(attempt int) time.Duration {
+	backoff := r.config.initialBackoff
+	for i := 1; i < attempt && backoff < r.config.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > r.config.maxBackoff {
+		return r.config.maxBackoff
+	}
+	return backoff
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
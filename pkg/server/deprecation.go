@@ -0,0 +1,119 @@
+package server
+
+import (
+	"faceit/internal/common"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// HeaderDeprecation is the date of the deprecation of the called API as an RFC 9745 structured date, i.e. `@1793491200`
+	HeaderDeprecation = "Deprecation"
+	// HeaderSunset is the HTTP date when the called API is removed (RFC 8594)
+	HeaderSunset = "Sunset"
+	// HeaderLink links the successor of the deprecated API
+	HeaderLink = "Link"
+)
+
+// Deprecation describes the deprecated routes of an API version what are announced in the headers of their responses
+type Deprecation struct {
+	// Version is the deprecated API version what is logged, i.e. `v1`
+	Version string
+	// Date is when the routes were deprecated
+	Date time.Time
+	// Sunset is when the routes are removed, the Sunset header isn't sent when it's zero
+	Sunset time.Time
+	// Successor is the path of the API what replaces the routes, it's linked with `successor-version` relation
+	Successor string
+	// Routes are the deprecated `METHOD path` routes with the echo path, i.e. `DELETE /api/v1/users/:id`.
+	// Only the routes what are replaced by the successor should be listed, the others are served without headers.
+	Routes []string
+}
+
+// NewV1Deprecation returns the deprecation of the v1 user routes what are replaced by the v2 API, by the
+// API_V1_DEPRECATION and API_V1_SUNSET RFC 3339 dates. The routes are deprecated only when API_V1_DEPRECATION is set,
+// so the deprecation is announced only when it's decided. The v1 routes of the features what v2 doesn't have
+// (i.e. search, batch get, events, sparse fieldsets, sort and filters) are never deprecated.
+func NewV1Deprecation() (Deprecation, bool) {
+	date := getEnvTime("API_V1_DEPRECATION")
+	if date.IsZero() {
+		return Deprecation{}, false
+	}
+
+	d := Deprecation{
+		Version:   "v1",
+		Date:      date,
+		Successor: "/api/v2/users",
+		Routes: []string{
+			http.MethodPost + " /api/v1/users",
+			http.MethodPut + " /api/v1/users/:id",
+			http.MethodPatch + " /api/v1/users/:id",
+			http.MethodDelete + " /api/v1/users/:id",
+		},
+	}
+	d.Sunset = getEnvTime("API_V1_SUNSET")
+	return d, true
+}
+
+// DeprecationMiddleware adds the Deprecation, Sunset and successor Link headers to the responses of the deprecated
+// routes, and logs the clients what still call them by their user agent and address.
+func DeprecationMiddleware(d Deprecation) echo.MiddlewareFunc {
+	deprecation := fmt.Sprintf("@%d", d.Date.Unix())
+	sunset := ""
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+	link := ""
+	if d.Successor != "" {
+		link = fmt.Sprintf(`<%s>; rel="successor-version"`, d.Successor)
+	}
+	routes := make(map[string]bool, len(d.Routes))
+	for _, r := range d.Routes {
+		routes[r] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if !routes[ctx.Request().Method+" "+ctx.Path()] {
+				return next(ctx)
+			}
+
+			header := ctx.Response().Header()
+			header.Set(HeaderDeprecation, deprecation)
+			if sunset != "" {
+				header.Set(HeaderSunset, sunset)
+			}
+			if link != "" {
+				header.Add(HeaderLink, link)
+			}
+
+			log.Info().
+				Str("version", d.Version).
+				Str("method", ctx.Request().Method).
+				Str("path", ctx.Path()).
+				Str("user_agent", ctx.Request().UserAgent()).
+				Str("remote_ip", ctx.RealIP()).
+				Str(common.CorrelationID, ctx.Request().Header.Get(echo.HeaderXRequestID)).
+				Msg("deprecated API called")
+			return next(ctx)
+		}
+	}
+}
+
+// getEnvTime returns the RFC 3339 time of the environment variable, or zero time when it's not set or invalid
+func getEnvTime(key string) time.Time {
+	v := common.GetEnv(key, "")
+	if v == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		log.Warn().Err(err).Msgf("failed to parse %s", key)
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	deprecationTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestDeprecationTestSuite(t *testing.T) {
+	suite.Run(t, new(deprecationTestSuite))
+}
+
+func (s *deprecationTestSuite) TestDeprecationMiddleware() {
+	e := echo.New()
+	router := CustomMethodRouter{Echo: e, Middlewares: []echo.MiddlewareFunc{DeprecationMiddleware(Deprecation{
+		Version:   "v1",
+		Date:      time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC),
+		Successor: "/api/v2/users",
+		Routes:    []string{"DELETE /api/v1/users/:id"},
+	})}}
+	ok := func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) }
+	router.DELETE("/api/v1/users/:id", ok)
+	router.GET("/api/v1/users/:id", ok)
+	e.DELETE("/api/v2/users/:id", ok)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/users/1", nil))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("@1793491200", rec.Header().Get(HeaderDeprecation))
+	s.Equal("Sat, 01 May 2027 00:00:00 GMT", rec.Header().Get(HeaderSunset))
+	s.Equal(`</api/v2/users>; rel="successor-version"`, rec.Header().Get(HeaderLink))
+
+	// the route isn't replaced by the successor
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/users/1", nil))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Empty(rec.Header().Get(HeaderDeprecation))
+	s.Empty(rec.Header().Get(HeaderLink))
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v2/users/1", nil))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Empty(rec.Header().Get(HeaderDeprecation))
+	s.Empty(rec.Header().Get(HeaderSunset))
+}
+
+func (s *deprecationTestSuite) TestNewV1Deprecation() {
+	s.T().Setenv("API_V1_DEPRECATION", "")
+	_, enabled := NewV1Deprecation()
+	s.False(enabled)
+
+	s.T().Setenv("API_V1_DEPRECATION", "invalid")
+	_, enabled = NewV1Deprecation()
+	s.False(enabled)
+
+	s.T().Setenv("API_V1_DEPRECATION", "2026-11-01T00:00:00Z")
+	s.T().Setenv("API_V1_SUNSET", "2027-01-31T00:00:00Z")
+	d, enabled := NewV1Deprecation()
+	s.True(enabled)
+	s.Equal(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), d.Date)
+	s.Equal(time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC), d.Sunset)
+	s.Contains(d.Routes, "DELETE /api/v1/users/:id")
+	s.NotContains(d.Routes, "GET /api/v1/users")
+}
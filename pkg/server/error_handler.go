@@ -1,7 +1,12 @@
 package server
 
 import (
+	"errors"
+	"faceit/internal/user"
 	"faceit/internal/user/api"
+	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,12 +14,42 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// MIMEApplicationProblemJSON is the content type of the RFC 7807 problem details
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// problemDetailsKey marks the requests of the routes what always return problem details
+const problemDetailsKey = "problem_details"
+
+// ProblemDetailsMiddleware makes the HTTPErrorHandler write the errors of the route as problem details
+// regardless of the Accept header, i.e. every error of the v2 API is a problem.
+func ProblemDetailsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		ctx.Set(problemDetailsKey, true)
+		return next(ctx)
+	}
+}
+
+// HTTPErrorHandler writes the error as RFC 7807 problem details when the request accepts `application/problem+json`
+// or the route is marked by ProblemDetailsMiddleware, otherwise as the api.Error of the v1 clients.
 func HTTPErrorHandler(err error, ctx echo.Context) {
-	msg := err.Error()
-	code := ctx.Response().Status
-	if e, ok := err.(*echo.HTTPError); ok {
-		msg = e.Message.(string)
-		code = e.Code
+	var he *echo.HTTPError
+	if !errors.As(err, &he) {
+		// the unexpected errors aren't returned with their details
+		he = NewHTTPError(err)
+	}
+	msg := fmt.Sprint(he.Message)
+	code := he.Code
+	if code < http.StatusBadRequest {
+		code = http.StatusInternalServerError
+	}
+
+	if ctx.Get(problemDetailsKey) == true || acceptsProblem(ctx.Request()) {
+		p := newProblem(he, ctx, code, msg)
+		log.Err(err).Msgf("%+v", p)
+		if err := writeProblem(ctx, p); err != nil {
+			log.Err(err).Msg("failed to write problem response")
+		}
+		return
 	}
 
 	e := api.Error{
@@ -27,6 +62,58 @@ func HTTPErrorHandler(err error, ctx echo.Context) {
 	ctx.JSON(e.Status, e)
 }
 
+// newProblem returns the problem details of the error with the stable code of the error and its invalid fields.
+// The code is the snake case status text when the error has no own code, i.e. `bad_request`.
+func newProblem(err error, ctx echo.Context, status int, detail string) api.Problem {
+	p := api.Problem{
+		Type:          "about:blank",
+		Title:         http.StatusText(status),
+		Status:        status,
+		Code:          strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"),
+		CorrelationId: getCorrelationId(ctx).String(),
+		Instance:      &ctx.Request().URL.Path,
+	}
+	if detail != "" {
+		p.Detail = &detail
+	}
+
+	var typed *user.Error
+	if errors.As(err, &typed) {
+		p.Code = typed.Code
+	}
+
+	var validationErr *user.ValidationError
+	if errors.As(err, &validationErr) {
+		fieldErrors := make([]api.FieldError, 0, len(validationErr.Fields))
+		for _, f := range validationErr.Fields {
+			fieldErrors = append(fieldErrors, api.FieldError{
+				Field:   f.Field,
+				Rule:    api.FieldErrorRule(f.Rule),
+				Message: f.Message,
+			})
+		}
+		p.Errors = &fieldErrors
+	}
+	return p
+}
+
+func writeProblem(ctx echo.Context, p api.Problem) error {
+	// the content type of the JSON response is kept when it's already set
+	ctx.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	return ctx.JSON(p.Status, p)
+}
+
+// acceptsProblem is true when the Accept header of the request has the problem details content type
+func acceptsProblem(req *http.Request) bool {
+	for _, accept := range strings.Split(req.Header.Get(echo.HeaderAccept), ",") {
+		mediaType, _, _ := strings.Cut(accept, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), MIMEApplicationProblemJSON) {
+			return true
+		}
+	}
+	return false
+}
+
 func getCorrelationId(ctx echo.Context) uuid.UUID {
 	correlationID := ctx.Request().Header.Get(echo.HeaderXRequestID)
 	if correlationID == "" {
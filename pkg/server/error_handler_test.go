@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"faceit/internal/user"
+	"faceit/internal/user/api"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+const correlationID = "8a0b6a6c-0a0e-4d3e-9a43-3e1f1b7f0a55"
+
+type (
+	errorHandlerTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestErrorHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(errorHandlerTestSuite))
+}
+
+func (s *errorHandlerTestSuite) TestHTTPErrorHandler_WritesError() {
+	rec := s.handle(echo.NewHTTPError(http.StatusNotFound, "user not found"), "")
+
+	s.Equal(http.StatusNotFound, rec.Code)
+	s.Equal(echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	var e api.Error
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &e))
+	s.Equal(http.StatusNotFound, e.Status)
+	s.Equal("user not found", e.Message)
+	s.Equal(correlationID, e.CorrelationId.String())
+}
+
+func (s *errorHandlerTestSuite) TestHTTPErrorHandler_WritesProblem() {
+	validationErr := &user.ValidationError{Fields: []user.FieldError{
+		{Field: "first_name", Rule: user.RuleMinLength, Message: "first name is too short"},
+		{Field: "email", Rule: user.RuleEmail, Message: "invalid email address"},
+	}}
+	rec := s.handle(NewHTTPError(validationErr), "application/json;q=0.9, application/problem+json")
+
+	s.Equal(http.StatusBadRequest, rec.Code)
+	s.Equal(MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	s.JSONEq(`{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "input user data is invalid: first name is too short, invalid email address",
+		"instance": "/api/v1/users",
+		"code": "invalid_user",
+		"correlation_id": "`+correlationID+`",
+		"errors": [
+			{"field": "first_name", "rule": "min_length", "message": "first name is too short"},
+			{"field": "email", "rule": "email", "message": "invalid email address"}
+		]
+	}`, rec.Body.String())
+}
+
+func (s *errorHandlerTestSuite) TestHTTPErrorHandler_WritesProblemWithStatusCode() {
+	for _, test := range []struct {
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{echo.NewHTTPError(http.StatusBadRequest, "invalid body"), http.StatusBadRequest, "bad_request"},
+		{echo.NewHTTPError(http.StatusUnsupportedMediaType, errors.New("unsupported")), http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{errors.New("any error"), http.StatusInternalServerError, "internal_error"},
+		{NewHTTPError(user.ErrUserNotFound), http.StatusNotFound, "user_not_found"},
+		{NewHTTPError(context.DeadlineExceeded), http.StatusGatewayTimeout, "timeout"},
+	} {
+		s.Run(test.expectedCode, func() {
+			rec := s.handle(test.err, MIMEApplicationProblemJSON)
+
+			s.Equal(test.expectedStatus, rec.Code)
+			var p api.Problem
+			s.NoError(json.Unmarshal(rec.Body.Bytes(), &p))
+			s.Equal(test.expectedCode, p.Code)
+			s.Equal(test.expectedStatus, p.Status)
+			s.Nil(p.Errors)
+		})
+	}
+}
+
+func (s *errorHandlerTestSuite) TestHTTPErrorHandler_WritesProblemOfMarkedRoute() {
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.GET("/api/v2/users/:id", func(ctx echo.Context) error {
+		return NewHTTPError(user.ErrUserNotFound)
+	}, ProblemDetailsMiddleware)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/users/42", nil))
+
+	s.Equal(http.StatusNotFound, rec.Code)
+	s.Equal(MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	var p api.Problem
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &p))
+	s.Equal("user_not_found", p.Code)
+}
+
+func (s *errorHandlerTestSuite) handle(err error, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", nil)
+	req.Header.Set(echo.HeaderXRequestID, correlationID)
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	rec := httptest.NewRecorder()
+
+	HTTPErrorHandler(err, echo.New().NewContext(req, rec))
+	return rec
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"faceit/internal/user"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// kindStatuses are the HTTP status codes of the user service error kinds
+var kindStatuses = map[user.ErrorKind]int{
+	user.KindValidation:   http.StatusBadRequest,
+	user.KindNotFound:     http.StatusNotFound,
+	user.KindConflict:     http.StatusConflict,
+	user.KindPrecondition: http.StatusUnprocessableEntity,
+	user.KindTimeout:      http.StatusGatewayTimeout,
+	user.KindUnavailable:  http.StatusServiceUnavailable,
+	user.KindInternal:     http.StatusInternalServerError,
+}
+
+// kindCodes are the gRPC status codes of the user service error kinds
+var kindCodes = map[user.ErrorKind]codes.Code{
+	user.KindValidation:   codes.InvalidArgument,
+	user.KindNotFound:     codes.NotFound,
+	user.KindConflict:     codes.Aborted,
+	user.KindPrecondition: codes.FailedPrecondition,
+	user.KindTimeout:      codes.DeadlineExceeded,
+	user.KindUnavailable:  codes.Unavailable,
+	user.KindInternal:     codes.Internal,
+}
+
+// StatusCode returns the HTTP status code of the error by its kind, every API maps the user service errors by it,
+// so the same error is reported the same way by the REST, GraphQL and gRPC APIs.
+func StatusCode(err error) int {
+	return kindStatuses[user.AsError(err).Kind]
+}
+
+// GRPCStatus returns the gRPC status of the error by its kind with its user-safe message
+func GRPCStatus(err error) *status.Status {
+	e := user.AsError(err)
+	return status.New(kindCodes[e.Kind], e.Message)
+}
+
+// NewHTTPError returns the HTTP error of the error with its user-safe message.
+// The typed error is kept as its internal error, so the problem details have its stable code.
+func NewHTTPError(err error) *echo.HTTPError {
+	e := user.AsError(err)
+	return echo.NewHTTPError(kindStatuses[e.Kind], e.Message).SetInternal(e)
+}
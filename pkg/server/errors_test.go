@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"faceit/internal/user"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+)
+
+type (
+	errorsTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(errorsTestSuite))
+}
+
+func (s *errorsTestSuite) TestMapsErrorKinds() {
+	for _, test := range []struct {
+		err            error
+		expectedStatus int
+		expectedCode   codes.Code
+	}{
+		{fmt.Errorf("%w: 1-100 ids are required", user.ErrInvalidIDs), http.StatusBadRequest, codes.InvalidArgument},
+		{user.ErrUserNotFound, http.StatusNotFound, codes.NotFound},
+		{user.ErrEmailTaken, http.StatusConflict, codes.Aborted},
+		{user.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, codes.FailedPrecondition},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, codes.DeadlineExceeded},
+		{errors.New("any error"), http.StatusInternalServerError, codes.Internal},
+	} {
+		s.Run(test.err.Error(), func() {
+			s.Equal(test.expectedStatus, StatusCode(test.err))
+			s.Equal(test.expectedCode, GRPCStatus(test.err).Code())
+
+			he := NewHTTPError(test.err)
+			s.Equal(test.expectedStatus, he.Code)
+			s.ErrorIs(he, test.err)
+		})
+	}
+}
+
+func (s *errorsTestSuite) TestNewHTTPError_HidesInternalDetails() {
+	he := NewHTTPError(errors.New("pq: relation users doesn't exist"))
+
+	s.Equal("internal error", he.Message)
+	s.Equal(http.StatusInternalServerError, he.Code)
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"faceit/internal/common"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataRequestID is the gRPC metadata key of the correlation id, the lower case X-Request-Id header
+const MetadataRequestID = "x-request-id"
+
+// correlatedStream is the server stream with the context of the correlation id
+type correlatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s correlatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// NewGRPCServer creates a gRPC server what correlates and logs the calls like the middlewares of the REST API.
+func NewGRPCServer() *grpc.Server {
+	return grpc.NewServer(
+		grpc.ChainUnaryInterceptor(RequestIDUnaryInterceptor, LoggerUnaryInterceptor),
+		grpc.ChainStreamInterceptor(RequestIDStreamInterceptor, LoggerStreamInterceptor),
+	)
+}
+
+// StopGRPCServer stops the server gracefully, or closes the pending calls when the context is done before they finish.
+func StopGRPCServer(ctx context.Context, s *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.Stop()
+	}
+}
+
+// RequestIDUnaryInterceptor keeps the x-request-id metadata of the call or generates a new one,
+// sets it as the correlation id of the context and sends it back in the response header.
+func RequestIDUnaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, cID := withCorrelationID(ctx)
+	if err := grpc.SetHeader(ctx, metadata.Pairs(MetadataRequestID, cID)); err != nil {
+		log.Warn().Err(err).Msg("failed to set correlationID header")
+	}
+	return handler(ctx, req)
+}
+
+// RequestIDStreamInterceptor is the RequestIDUnaryInterceptor of the streams.
+func RequestIDStreamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, cID := withCorrelationID(ss.Context())
+	if err := ss.SetHeader(metadata.Pairs(MetadataRequestID, cID)); err != nil {
+		log.Warn().Err(err).Msg("failed to set correlationID header")
+	}
+	return handler(srv, correlatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// LoggerUnaryInterceptor logs the method, the status code and the correlation id of the calls.
+func LoggerUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	res, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, err)
+	return res, err
+}
+
+// LoggerStreamInterceptor logs the method, the status code and the correlation id of the streams when they end.
+func LoggerStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := handler(srv, ss)
+	logCall(ss.Context(), info.FullMethod, err)
+	return err
+}
+
+func logCall(ctx context.Context, method string, err error) {
+	log.Info().
+		Str("method", method).
+		Stringer("code", status.Code(err)).
+		Str(common.CorrelationID, common.GetCorrelationID(ctx)).
+		Send()
+}
+
+func withCorrelationID(ctx context.Context) (context.Context, string) {
+	received := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataRequestID); len(values) > 0 {
+			received = values[0]
+		}
+	}
+	cID := common.CorrelationIDOrNew(received)
+	return context.WithValue(ctx, common.CorrelationID, cID), cID
+}
@@ -2,8 +2,10 @@ package server
 
 import (
 	"context"
+	"faceit/internal/common"
 	"faceit/internal/user"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -15,39 +17,57 @@ type HealthResponse struct {
 	Status   string `json:"status"`
 	RabbitMQ string `json:"rabbitmq"`
 	Postgres string `json:"postgres"`
+	Topology string `json:"topology"`
+	// Drift lists the missing exchanges and queues of the RabbitMQ topology, it doesn't change the status
+	Drift []string `json:"drift,omitempty"`
 }
 
 type Health struct {
 	publisher *user.RmqEventPublisher
 	db        *gorm.DB
+	topology  *topologyCheck
 }
 
-func NewHealth() Health {
-	p, err := user.NewEventPublisher()
-	if err != nil {
-		log.Err(err).Msg("failed to create RabbitMQ connection")
-	}
-
-	r, err := user.NewRepository()
-	if err != nil {
-		log.Err(err).Msg("failed to create Postgres connection")
-	}
+// topologyCheck caches the result of the topology verification, so the frequent health checks
+// don't open new RabbitMQ channels on every call
+type topologyCheck struct {
+	interval  time.Duration
+	verify    func() ([]string, error)
+	mu        sync.Mutex
+	checkedAt time.Time
+	drift     []string
+}
 
+// NewHealth creates the health check of the RabbitMQ publisher and the database connection of the user service.
+func NewHealth(publisher *user.RmqEventPublisher, db *gorm.DB) Health {
 	return Health{
-		publisher: p,
-		db:        r.GetDB(),
+		publisher: publisher,
+		db:        db,
+		topology: &topologyCheck{
+			interval: common.GetEnvDuration("HEALTH_TOPOLOGY_CHECK_INTERVAL", time.Minute),
+			verify:   publisher.VerifyTopology,
+		},
 	}
 }
 
+// Check reports the status of the RabbitMQ and the Postgres connections. The topology drift is only reported,
+// because a missing exchange or queue is declared again by a restart, what a failing liveness probe would
+// keep triggering.
 func (h Health) Check(echoCtx echo.Context) error {
 	_, cancel := context.WithTimeout(echoCtx.Request().Context(), time.Second)
 	defer cancel()
 
-	rmqConn := true
-	c := h.publisher.Channel()
-	if err := c.ExchangeDeclarePassive(h.publisher.ExchangeName(), "topic", true, false, false, false, nil); err != nil {
-		rmqConn = false
-		log.Err(err).Msg("RabbitMQ connection is down")
+	rmqConn := h.publisher.IsConnected()
+	if !rmqConn {
+		log.Error().Msg("RabbitMQ connection is down")
+	}
+
+	var drift []string
+	if rmqConn {
+		var err error
+		if drift, err = h.topology.check(); err != nil {
+			log.Err(err).Msg("failed to verify RabbitMQ topology")
+		}
 	}
 
 	dbConn := true
@@ -65,12 +85,35 @@ func (h Health) Check(echoCtx echo.Context) error {
 		Status:   getStatus(status == http.StatusOK),
 		RabbitMQ: getStatus(rmqConn),
 		Postgres: getStatus(dbConn),
+		Topology: getStatus(rmqConn && len(drift) == 0),
+		Drift:    drift,
 	}
 
 	echoCtx.JSON(status, resp)
 	return nil
 }
 
+// check returns the drift of the last verification when it's not older than the interval, otherwise it verifies
+// the topology again. The failed verifications are not cached.
+func (t *topologyCheck) check() ([]string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.checkedAt.IsZero() && time.Since(t.checkedAt) < t.interval {
+		return t.drift, nil
+	}
+
+	drift, err := t.verify()
+	if err != nil {
+		return nil, err
+	}
+	if len(drift) > 0 {
+		log.Warn().Strs("drift", drift).Msg("RabbitMQ topology drift is detected")
+	}
+	t.drift, t.checkedAt = drift, time.Now()
+	return drift, nil
+}
+
 func getStatus(b bool) string {
 	s := "UP"
 	if !b {
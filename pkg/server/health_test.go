@@ -0,0 +1,60 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	healthTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestHealthTestSuite(t *testing.T) {
+	suite.Run(t, new(healthTestSuite))
+}
+
+func (s *healthTestSuite) TestTopologyCheck_CachesDrift() {
+	calls := 0
+	t := topologyCheck{
+		interval: time.Minute,
+		verify: func() ([]string, error) {
+			calls++
+			return []string{"queue billing is missing"}, nil
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		drift, err := t.check()
+		s.NoError(err)
+		s.Equal([]string{"queue billing is missing"}, drift)
+	}
+	s.Equal(1, calls)
+
+	t.checkedAt = time.Now().Add(-time.Minute)
+	_, err := t.check()
+	s.NoError(err)
+	s.Equal(2, calls, "the expired result is verified again")
+}
+
+func (s *healthTestSuite) TestTopologyCheck_DoesntCacheErrors() {
+	errVerify := errors.New("connection closed")
+	calls := 0
+	t := topologyCheck{
+		interval: time.Minute,
+		verify: func() ([]string, error) {
+			calls++
+			return nil, errVerify
+		},
+	}
+
+	_, err := t.check()
+	s.ErrorIs(err, errVerify)
+	_, err = t.check()
+	s.ErrorIs(err, errVerify)
+	s.Equal(2, calls)
+}
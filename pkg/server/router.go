@@ -0,0 +1,71 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CustomMethodRouter registers the routes of the generated API handlers on the echo server. The colon of the custom
+// methods (i.e. `/users:batchGet`) is escaped, so echo matches it literally instead of routing it as a path parameter.
+// The middlewares are added to every registered route, so they could be applied to a single API version.
+type CustomMethodRouter struct {
+	*echo.Echo
+	Middlewares []echo.MiddlewareFunc
+}
+
+func (r CustomMethodRouter) CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Echo.CONNECT(escapeCustomMethod(path), h, r.with(m)...)
+}
+
+func (r CustomMethodRouter) DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Echo.DELETE(escapeCustomMethod(path), h, r.with(m)...)
+}
+
+func (r CustomMethodRouter) GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Echo.GET(escapeCustomMethod(path), h, r.with(m)...)
+}
+
+func (r CustomMethodRouter) HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Echo.HEAD(escapeCustomMethod(path), h, r.with(m)...)
+}
+
+func (r CustomMethodRouter) OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Echo.OPTIONS(escapeCustomMethod(path), h, r.with(m)...)
+}
+
+func (r CustomMethodRouter) PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Echo.PATCH(escapeCustomMethod(path), h, r.with(m)...)
+}
+
+func (r CustomMethodRouter) POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Echo.POST(escapeCustomMethod(path), h, r.with(m)...)
+}
+
+func (r CustomMethodRouter) PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Echo.PUT(escapeCustomMethod(path), h, r.with(m)...)
+}
+
+func (r CustomMethodRouter) TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Echo.TRACE(escapeCustomMethod(path), h, r.with(m)...)
+}
+
+// with returns the middlewares of the router followed by the middlewares of the route
+func (r CustomMethodRouter) with(m []echo.MiddlewareFunc) []echo.MiddlewareFunc {
+	if len(r.Middlewares) == 0 {
+		return m
+	}
+	return append(append([]echo.MiddlewareFunc{}, r.Middlewares...), m...)
+}
+
+// escapeCustomMethod escapes the colons what don't start a path parameter segment
+func escapeCustomMethod(path string) string {
+	var b strings.Builder
+	for i, c := range path {
+		if c == ':' && i > 0 && path[i-1] != '/' && path[i-1] != '\\' {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	routerTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestRouterTestSuite(t *testing.T) {
+	suite.Run(t, new(routerTestSuite))
+}
+
+func (s *routerTestSuite) TestEscapeCustomMethod() {
+	s.Equal(`/users\:batchGet`, escapeCustomMethod("/users:batchGet"))
+	s.Equal("/users/:id", escapeCustomMethod("/users/:id"))
+	s.Equal(`/users/:id\:undelete`, escapeCustomMethod("/users/:id:undelete"))
+	s.Equal(`/users\:batchGet`, escapeCustomMethod(`/users\:batchGet`))
+}
+
+func (s *routerTestSuite) TestCustomMethodRoute() {
+	e := echo.New()
+	router := CustomMethodRouter{Echo: e}
+	router.POST("/users:batchGet", func(ctx echo.Context) error { return ctx.NoContent(http.StatusOK) })
+	router.GET("/users/:id", func(ctx echo.Context) error { return ctx.String(http.StatusOK, ctx.Param("id")) })
+
+	for _, test := range []struct {
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{http.MethodPost, "/users:batchGet", http.StatusOK},
+		{http.MethodPost, "/users:batchDelete", http.StatusNotFound},
+		{http.MethodPost, "/usersbatchGet", http.StatusNotFound},
+		{http.MethodGet, "/users/42", http.StatusOK},
+	} {
+		s.Run(test.method+" "+test.path, func() {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(test.method, test.path, nil))
+			s.Equal(test.expectedStatus, rec.Code)
+		})
+	}
+}
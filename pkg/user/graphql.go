@@ -0,0 +1,296 @@
+package user
+
+import (
+	"context"
+	"errors"
+	apispec "faceit/api"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"faceit/pkg/server"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// errInvalidID is returned for the ids of the arguments what aren't UUIDs
+var errInvalidID = &user.Error{Kind: user.KindValidation, Code: "invalid_id", Message: "invalid id"}
+
+type (
+	// GraphQLHandler serves the GraphQL schema of `api/users.graphql` with the same user service as the REST Handler
+	GraphQLHandler struct {
+		timeout time.Duration
+		userSvc userService
+		schema  *graphql.Schema
+	}
+
+	// graphQLRequest is the body of a GraphQL call
+	graphQLRequest struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+
+	// graphQLError is the error of a resolver with the HTTP status code and the stable code of the same REST error
+	graphQLError struct {
+		err           *user.Error
+		status        int
+		correlationID string
+	}
+
+	graphQLResolver struct {
+		userSvc userService
+	}
+
+	userResolver struct {
+		u *user.User
+	}
+
+	userFilterInput struct {
+		FirstName *string
+		LastName  *string
+		Nickname  *string
+		Email     *string
+		Country   *string
+	}
+
+	createUserInput struct {
+		FirstName string
+		LastName  string
+		Nickname  string
+		Email     string
+		Country   string
+		Password  *string
+	}
+
+	updateUserInput struct {
+		FirstName *string
+		LastName  *string
+		Nickname  *string
+		Email     *string
+		Country   *string
+		Password  *string
+	}
+)
+
+// NewGraphQLHandler creates the GraphQL handler of the users what shares the user service and the request timeout of the handler.
+func NewGraphQLHandler(h *Handler) (*GraphQLHandler, error) {
+	schema, err := graphql.ParseSchema(apispec.UsersGraphQL, &graphQLResolver{userSvc: h.userSvc}, graphql.MaxParallelism(maxLoaderBatch))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GraphQLHandler{
+		timeout: h.timeout,
+		userSvc: h.userSvc,
+		schema:  schema,
+	}, nil
+}
+
+// Serve executes the GraphQL query of the request body. The errors of the resolvers are returned in the `errors`
+// of the response with 200, only an invalid request body gets 400.
+func (g GraphQLHandler) Serve(ctx echo.Context) error {
+	var req graphQLRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ec := ctx.Request().Context()
+	c, cancel := context.WithTimeout(context.WithValue(ec, common.CorrelationID, common.GetEchoCorrelationID(ctx)), g.timeout)
+	defer cancel()
+
+	res := g.schema.Exec(withUserLoader(c, g.userSvc), req.Query, req.OperationName, req.Variables)
+	return ctx.JSON(http.StatusOK, res)
+}
+
+func (r *graphQLResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, newGraphQLError(ctx, "User", errInvalidID)
+	}
+
+	u, err := loadUser(ctx, id)
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, newGraphQLError(ctx, "User", err)
+	}
+	return &userResolver{u: u}, nil
+}
+
+func (r *graphQLResolver) Users(ctx context.Context, args struct {
+	Page     *int32
+	PageSize *int32
+	Filter   *userFilterInput
+	Sort     *string
+}) ([]*userResolver, error) {
+	pagination := common.Pagination{}
+	if args.Page != nil {
+		pagination.Page = int(*args.Page)
+	}
+	if args.PageSize != nil {
+		pagination.PageSize = int(*args.PageSize)
+	}
+
+	filter := user.User{}
+	if f := args.Filter; f != nil {
+		filter = user.User{
+			FirstName: value(f.FirstName),
+			LastName:  value(f.LastName),
+			Nickname:  value(f.Nickname),
+			Email:     value(f.Email),
+			Country:   value(f.Country),
+		}
+	}
+
+	var sort user.Sort
+	if args.Sort != nil {
+		sort = user.ParseSort(*args.Sort)
+	}
+
+	users, err := r.userSvc.List(ctx, pagination, &filter, sort, nil)
+	if err != nil {
+		return nil, newGraphQLError(ctx, "Users", err)
+	}
+
+	res := make([]*userResolver, 0, len(users))
+	for i := range users {
+		res = append(res, &userResolver{u: &users[i]})
+	}
+	return res, nil
+}
+
+func (r *graphQLResolver) CreateUser(ctx context.Context, args struct {
+	Input          createUserInput
+	IdempotencyKey *string
+}) (*userResolver, error) {
+	userIn := user.User{
+		FirstName: args.Input.FirstName,
+		LastName:  args.Input.LastName,
+		Nickname:  args.Input.Nickname,
+		Email:     args.Input.Email,
+		Country:   args.Input.Country,
+	}
+	password := value(args.Input.Password)
+
+	var u *user.User
+	var err error
+	if args.IdempotencyKey != nil {
+		u, _, err = r.userSvc.CreateIdempotent(ctx, *args.IdempotencyKey, userIn, password)
+	} else {
+		u, err = r.userSvc.Create(ctx, userIn, password)
+	}
+	if err != nil {
+		return nil, newGraphQLError(ctx, "CreateUser", err)
+	}
+	return &userResolver{u: u}, nil
+}
+
+func (r *graphQLResolver) UpdateUser(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateUserInput
+}) (*userResolver, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, newGraphQLError(ctx, "UpdateUser", errInvalidID)
+	}
+
+	userIn := user.User{
+		FirstName: value(args.Input.FirstName),
+		LastName:  value(args.Input.LastName),
+		Nickname:  value(args.Input.Nickname),
+		Email:     value(args.Input.Email),
+		Country:   value(args.Input.Country),
+	}
+	u, err := r.userSvc.Update(ctx, id, userIn, value(args.Input.Password))
+	if err != nil {
+		return nil, newGraphQLError(ctx, "UpdateUser", err)
+	}
+	return &userResolver{u: u}, nil
+}
+
+func (r *graphQLResolver) DeleteUser(ctx context.Context, args struct{ ID graphql.ID }) (graphql.ID, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return "", newGraphQLError(ctx, "DeleteUser", errInvalidID)
+	}
+
+	if err := r.userSvc.Delete(ctx, id); err != nil {
+		return "", newGraphQLError(ctx, "DeleteUser", err)
+	}
+	return args.ID, nil
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return graphql.ID(r.u.ID.String())
+}
+
+func (r *userResolver) FirstName() string {
+	return r.u.FirstName
+}
+
+func (r *userResolver) LastName() string {
+	return r.u.LastName
+}
+
+func (r *userResolver) Nickname() string {
+	return r.u.Nickname
+}
+
+func (r *userResolver) Email() string {
+	return r.u.Email
+}
+
+func (r *userResolver) Country() string {
+	return r.u.Country
+}
+
+func (r *userResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.u.CreatedAt}
+}
+
+func (r *userResolver) UpdatedAt() *graphql.Time {
+	if r.u.UpdatedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.u.UpdatedAt}
+}
+
+// newGraphQLError logs the error of the resolver and classifies it, so only its user-safe message is returned
+func newGraphQLError(ctx context.Context, operation string, err error) *graphQLError {
+	correlationID := common.GetCorrelationID(ctx)
+	log.Err(err).
+		Str("operation", operation).
+		Str(common.CorrelationID, correlationID).
+		Send()
+
+	return &graphQLError{err: user.AsError(err), status: server.StatusCode(err), correlationID: correlationID}
+}
+
+func (e *graphQLError) Error() string {
+	return e.err.Error()
+}
+
+func (e *graphQLError) Unwrap() error {
+	return e.err
+}
+
+// Extensions are added to the error of the GraphQL response
+func (e *graphQLError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"status":         e.status,
+		"code":           e.err.Code,
+		"correlation_id": e.correlationID,
+	}
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
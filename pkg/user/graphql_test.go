@@ -0,0 +1,240 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	graphQLTestSuite struct {
+		userSvcMock *mockUserService
+		handler     *GraphQLHandler
+		e           *echo.Echo
+		suite.Suite
+	}
+
+	graphQLResponse struct {
+		Data   map[string]json.RawMessage `json:"data"`
+		Errors []struct {
+			Message    string                 `json:"message"`
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+)
+
+func TestGraphQLTestSuite(t *testing.T) {
+	suite.Run(t, new(graphQLTestSuite))
+}
+
+func (s *graphQLTestSuite) SetupTest() {
+	s.e = echo.New()
+	s.userSvcMock = newMockUserService(s.T())
+
+	var err error
+	s.handler, err = NewGraphQLHandler(&Handler{timeout: time.Second, userSvc: s.userSvcMock})
+	s.Require().NoError(err)
+}
+
+func (s *graphQLTestSuite) TestUser_BatchesLookups() {
+	createdAt := time.Date(2022, 11, 28, 10, 0, 0, 0, time.UTC)
+	s.userSvcMock.
+		On(BatchGet, mock.Anything, mock.MatchedBy(func(ids []uuid.UUID) bool {
+			return len(ids) == 2 && ids[0] != ids[1] &&
+				(ids[0] == userID || ids[0] == missingUserID) && (ids[1] == userID || ids[1] == missingUserID)
+		}), user.Fields(nil)).
+		Return([]user.User{{ID: userID, Nickname: "dome", CreatedAt: createdAt}}, []uuid.UUID{missingUserID}, nil).
+		Once()
+
+	res := s.exec(`{
+		a: user(id: "`+userID.String()+`") { id nickname createdAt updatedAt }
+		b: user(id: "`+missingUserID.String()+`") { id }
+	}`, nil)
+
+	s.Empty(res.Errors)
+	s.JSONEq(`{"id": "`+userID.String()+`", "nickname": "dome", "createdAt": "2022-11-28T10:00:00Z", "updatedAt": null}`, string(res.Data["a"]))
+	s.Equal("null", string(res.Data["b"]))
+}
+
+func (s *graphQLTestSuite) TestUser_BatchesMoreLookupsThanDefaultParallelism() {
+	const lookups = 20
+	ids := make([]uuid.UUID, lookups)
+	var query strings.Builder
+	query.WriteString("{")
+	for i := range ids {
+		ids[i] = uuid.New()
+		fmt.Fprintf(&query, "u%d: user(id: %q) { id }\n", i, ids[i])
+	}
+	query.WriteString("}")
+	s.userSvcMock.
+		On(BatchGet, mock.Anything, mock.MatchedBy(func(batch []uuid.UUID) bool {
+			return len(batch) == lookups
+		}), user.Fields(nil)).
+		Return([]user.User{}, ids, nil).
+		Once()
+
+	res := s.exec(query.String(), nil)
+
+	s.Empty(res.Errors)
+	s.Len(res.Data, lookups)
+}
+
+func (s *graphQLTestSuite) TestUser_ReturnsError() {
+	s.userSvcMock.
+		On(BatchGet, mock.Anything, []uuid.UUID{errorUserID}, user.Fields(nil)).
+		Return(nil, nil, errors.New("any error")).
+		Once()
+
+	res := s.exec(`{ user(id: "`+errorUserID.String()+`") { id } }`, nil)
+	s.Require().Len(res.Errors, 1)
+	s.Equal(float64(http.StatusInternalServerError), res.Errors[0].Extensions["status"])
+	s.Equal("internal_error", res.Errors[0].Extensions["code"])
+	s.Equal("internal error", res.Errors[0].Message)
+
+	res = s.exec(`{ user(id: "invalid") { id } }`, nil)
+	s.Require().Len(res.Errors, 1)
+	s.Equal(float64(http.StatusBadRequest), res.Errors[0].Extensions["status"])
+	s.Equal("invalid_id", res.Errors[0].Extensions["code"])
+}
+
+func (s *graphQLTestSuite) TestUsers() {
+	s.userSvcMock.
+		On(List, mock.Anything, common.Pagination{Page: 1, PageSize: 2}, &user.User{Country: "UK"},
+			user.Sort{{Field: "nickname", Desc: true}}, user.Fields(nil)).
+		Return([]user.User{{ID: userID, Country: "UK"}}, nil).
+		Once()
+	s.userSvcMock.
+		On(List, mock.Anything, common.Pagination{}, &user.User{}, user.Sort{{Field: "password"}}, user.Fields(nil)).
+		Return(nil, user.ErrInvalidSort).
+		Once()
+
+	res := s.exec(`query($filter: UserFilter) { users(page: 1, pageSize: 2, filter: $filter, sort: "-nickname") { id country } }`,
+		map[string]interface{}{"filter": map[string]interface{}{"country": "UK"}})
+	s.Empty(res.Errors)
+	s.JSONEq(`[{"id": "`+userID.String()+`", "country": "UK"}]`, string(res.Data["users"]))
+
+	res = s.exec(`{ users(sort: "password") { id } }`, nil)
+	s.Require().Len(res.Errors, 1)
+	s.Equal(float64(http.StatusBadRequest), res.Errors[0].Extensions["status"])
+}
+
+func (s *graphQLTestSuite) TestCreateUser() {
+	expectedUser := user.User{FirstName: "john", LastName: "doe", Nickname: "johndoe", Email: "test@test.com", Country: "US"}
+	savedUser := expectedUser
+	savedUser.ID = userID
+	s.userSvcMock.
+		On(CreateI, mock.Anything, "key", expectedUser, "testpwd").
+		Return(&savedUser, false, nil).
+		Once()
+
+	res := s.exec(`mutation {
+		createUser(input: {firstName: "john", lastName: "doe", nickname: "johndoe", email: "test@test.com", country: "US", password: "testpwd"}, idempotencyKey: "key") { id email }
+	}`, nil)
+
+	s.Empty(res.Errors)
+	s.JSONEq(`{"id": "`+userID.String()+`", "email": "test@test.com"}`, string(res.Data["createUser"]))
+}
+
+func (s *graphQLTestSuite) TestUpdateUser() {
+	s.userSvcMock.
+		On(Update, mock.Anything, userID, user.User{Nickname: "dome"}, "").
+		Return(&user.User{ID: userID, Nickname: "dome"}, nil).
+		Once()
+	s.userSvcMock.
+		On(Update, mock.Anything, missingUserID, user.User{Nickname: "dome"}, "").
+		Return(nil, user.ErrUserNotFound).
+		Once()
+
+	res := s.exec(`mutation { updateUser(id: "`+userID.String()+`", input: {nickname: "dome"}) { nickname } }`, nil)
+	s.Empty(res.Errors)
+	s.JSONEq(`{"nickname": "dome"}`, string(res.Data["updateUser"]))
+
+	res = s.exec(`mutation { updateUser(id: "`+missingUserID.String()+`", input: {nickname: "dome"}) { nickname } }`, nil)
+	s.Require().Len(res.Errors, 1)
+	s.Equal(float64(http.StatusNotFound), res.Errors[0].Extensions["status"])
+}
+
+func (s *graphQLTestSuite) TestDeleteUser() {
+	s.userSvcMock.
+		On(Delete, mock.Anything, userID).
+		Return(nil).
+		Once()
+
+	res := s.exec(`mutation { deleteUser(id: "`+userID.String()+`") }`, nil)
+	s.Empty(res.Errors)
+	s.Equal(`"`+userID.String()+`"`, string(res.Data["deleteUser"]))
+}
+
+func (s *graphQLTestSuite) TestServe_ReturnsErrorOnInvalidBody() {
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader("{"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	ctx := s.e.NewContext(req, httptest.NewRecorder())
+
+	err := s.handler.Serve(ctx).(*echo.HTTPError)
+	s.Equal(http.StatusBadRequest, err.Code)
+}
+
+func (s *graphQLTestSuite) TestUserLoader_LoadsFullBatchWithoutWaiting() {
+	var mu sync.Mutex
+	var batches [][]uuid.UUID
+	loader := &userLoader{
+		wait: time.Second,
+		load: func(ctx context.Context, ids []uuid.UUID) ([]user.User, []uuid.UUID, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			batches = append(batches, ids)
+			users := make([]user.User, 0, len(ids))
+			for _, id := range ids {
+				users = append(users, user.User{ID: id})
+			}
+			return users, nil, nil
+		},
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < maxLoaderBatch; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := uuid.New()
+			u, err := loader.Load(context.Background(), id)
+			s.NoError(err)
+			s.Equal(id, u.ID)
+		}()
+	}
+	wg.Wait()
+
+	s.Less(time.Since(start), loader.wait)
+	s.Require().Len(batches, 1)
+	s.Len(batches[0], maxLoaderBatch)
+}
+
+func (s *graphQLTestSuite) exec(query string, variables map[string]interface{}) graphQLResponse {
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	s.Require().NoError(err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	s.Require().NoError(s.handler.Serve(s.e.NewContext(req, rec)))
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var res graphQLResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	return res
+}
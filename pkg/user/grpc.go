@@ -0,0 +1,218 @@
+package user
+
+import (
+	"context"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"faceit/pkg/server"
+	"faceit/pkg/user/pb"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCServer serves the gRPC UserService with the same user service as the REST Handler
+type GRPCServer struct {
+	pb.UnimplementedUserServiceServer
+	timeout time.Duration
+	userSvc userService
+}
+
+// NewGRPCServer creates the gRPC server of the users what shares the user service and the request timeout of the handler.
+func NewGRPCServer(h *Handler) *GRPCServer {
+	return &GRPCServer{
+		timeout: h.timeout,
+		userSvc: h.userSvc,
+	}
+}
+
+func (s *GRPCServer) Create(ctx context.Context, req *pb.CreateRequest) (*pb.User, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	userIn := user.User{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Nickname:  req.Nickname,
+		Email:     req.Email,
+		Country:   req.Country,
+	}
+
+	var u *user.User
+	var replayed bool
+	var err error
+	if req.IdempotencyKey != "" {
+		u, replayed, err = s.userSvc.CreateIdempotent(c, req.IdempotencyKey, userIn, req.Password)
+	} else {
+		u, err = s.userSvc.Create(c, userIn, req.Password)
+	}
+	if err != nil {
+		return nil, grpcError(c, "Create", err)
+	}
+
+	if replayed {
+		if err := grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(HeaderIdempotentReplayed), "true")); err != nil {
+			log.Warn().Err(err).Msg("failed to set idempotent replayed header")
+		}
+	}
+	return toProtoUser(u), nil
+}
+
+func (s *GRPCServer) Get(ctx context.Context, req *pb.GetRequest) (*pb.User, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	id, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.userSvc.Get(c, id, user.Fields(req.Fields))
+	if err != nil {
+		return nil, grpcError(c, "Get", err)
+	}
+	return toProtoUser(u), nil
+}
+
+func (s *GRPCServer) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.User, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	id, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	userIn := user.User{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Nickname:  req.Nickname,
+		Email:     req.Email,
+		Country:   req.Country,
+	}
+	u, err := s.userSvc.Update(c, id, userIn, req.Password)
+	if err != nil {
+		return nil, grpcError(c, "Update", err)
+	}
+	return toProtoUser(u), nil
+}
+
+func (s *GRPCServer) Delete(ctx context.Context, req *pb.DeleteRequest) (*emptypb.Empty, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	id, err := parseID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userSvc.Delete(c, id); err != nil {
+		return nil, grpcError(c, "Delete", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *GRPCServer) List(ctx context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	pagination := common.Pagination{Page: int(req.Page), PageSize: int(req.PageSize)}
+	filter := user.User{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Nickname:  req.Nickname,
+		Email:     req.Email,
+		Country:   req.Country,
+	}
+	var sort user.Sort
+	if req.Sort != "" {
+		sort = user.ParseSort(req.Sort)
+	}
+
+	results, err := s.userSvc.List(c, pagination, &filter, sort, user.Fields(req.Fields))
+	if err != nil {
+		return nil, grpcError(c, "List", err)
+	}
+
+	res := &pb.ListResponse{Users: make([]*pb.User, 0, len(results))}
+	for i := range results {
+		res.Users = append(res.Users, toProtoUser(&results[i]))
+	}
+	return res, nil
+}
+
+// Watch streams the user events until the client cancels the stream.
+// The request timeout isn't applied as the stream is long living.
+func (s *GRPCServer) Watch(req *pb.WatchRequest, stream pb.UserService_WatchServer) error {
+	filter := user.EventFilter{}
+	for _, t := range req.Types {
+		filter.Types = append(filter.Types, user.UserEventType(t))
+	}
+	for _, userID := range req.UserIds {
+		id, err := parseID(userID)
+		if err != nil {
+			return err
+		}
+		filter.UserIDs = append(filter.UserIDs, id)
+	}
+
+	err := s.userSvc.StreamEvents(stream.Context(), req.LastEventId, filter, func(event *user.StoredEvent) error {
+		// gRPC keeps the connection alive on its own, so there is nothing to send without events
+		if event == nil {
+			return stream.Context().Err()
+		}
+		return stream.Send(&pb.WatchResponse{
+			Id:    event.ID,
+			Event: user.ToProtoEvent(event.UserEvent),
+		})
+	})
+	if err != nil && stream.Context().Err() == nil {
+		return grpcError(stream.Context(), "Watch", err)
+	}
+	return nil
+}
+
+// grpcError logs the error of the operation and converts it to a gRPC status by its kind
+func grpcError(ctx context.Context, operation string, err error) error {
+	log.Err(err).
+		Str("operation", operation).
+		Str(common.CorrelationID, common.GetCorrelationID(ctx)).
+		Send()
+
+	return server.GRPCStatus(err).Err()
+}
+
+func parseID(id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid id %q: %s", id, err.Error()))
+	}
+	return parsed, nil
+}
+
+func toProtoUser(u *user.User) *pb.User {
+	res := &pb.User{
+		Id:        u.ID.String(),
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Nickname:  u.Nickname,
+		Email:     u.Email,
+		Country:   u.Country,
+	}
+	if !u.CreatedAt.IsZero() {
+		res.CreatedAt = timestamppb.New(u.CreatedAt)
+	}
+	if u.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*u.UpdatedAt)
+	}
+	return res
+}
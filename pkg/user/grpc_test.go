@@ -0,0 +1,256 @@
+package user
+
+import (
+	"context"
+	"errors"
+	c "faceit/internal/common"
+	"faceit/internal/user"
+	srv "faceit/pkg/server"
+	"faceit/pkg/user/pb"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type grpcTestSuite struct {
+	userSvcMock *mockUserService
+	server      *grpc.Server
+	conn        *grpc.ClientConn
+	client      pb.UserServiceClient
+	suite.Suite
+}
+
+func TestGRPCTestSuite(t *testing.T) {
+	suite.Run(t, new(grpcTestSuite))
+}
+
+func (s *grpcTestSuite) SetupTest() {
+	s.userSvcMock = newMockUserService(s.T())
+	listener := bufconn.Listen(1024 * 1024)
+
+	s.server = srv.NewGRPCServer()
+	pb.RegisterUserServiceServer(s.server, NewGRPCServer(&Handler{timeout: time.Second, userSvc: s.userSvcMock}))
+	go func() { _ = s.server.Serve(listener) }()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	s.Require().NoError(err)
+	s.conn = conn
+	s.client = pb.NewUserServiceClient(conn)
+}
+
+func (s *grpcTestSuite) TearDownTest() {
+	s.NoError(s.conn.Close())
+	s.server.Stop()
+}
+
+func (s *grpcTestSuite) TestGet() {
+	s.userSvcMock.
+		On(Get, mock.Anything, userID, user.Fields{"nickname"}).
+		Run(func(args mock.Arguments) {
+			s.Equal("test-correlation-id", c.GetCorrelationID(args.Get(0).(context.Context)))
+		}).
+		Return(&user.User{ID: userID, Nickname: "dome"}, nil).
+		Once()
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), srv.MetadataRequestID, "test-correlation-id")
+	u, err := s.client.Get(ctx, &pb.GetRequest{Id: userID.String(), Fields: []string{"nickname"}}, grpc.Header(&header))
+
+	s.NoError(err)
+	s.Equal(userID.String(), u.Id)
+	s.Equal("dome", u.Nickname)
+	s.Nil(u.CreatedAt)
+	s.Equal([]string{"test-correlation-id"}, header.Get(srv.MetadataRequestID))
+}
+
+func (s *grpcTestSuite) TestGet_ReturnsError() {
+	s.userSvcMock.
+		On(Get, mock.Anything, missingUserID, user.Fields(nil)).
+		Return(nil, user.ErrUserNotFound).
+		Once()
+	s.userSvcMock.
+		On(Get, mock.Anything, errorUserID, user.Fields(nil)).
+		Return(nil, errors.New("any error")).
+		Once()
+
+	for _, test := range []struct {
+		name         string
+		id           string
+		expectedCode codes.Code
+	}{
+		{name: "invalid id", id: "invalid", expectedCode: codes.InvalidArgument},
+		{name: "not found", id: missingUserID.String(), expectedCode: codes.NotFound},
+		{name: "service error", id: errorUserID.String(), expectedCode: codes.Internal},
+	} {
+		s.Run(test.name, func() {
+			_, err := s.client.Get(context.Background(), &pb.GetRequest{Id: test.id})
+			s.Equal(test.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func (s *grpcTestSuite) TestCreate() {
+	expectedUser := user.User{FirstName: "john", Email: "test@test.com", Country: "US"}
+	savedUser := expectedUser
+	savedUser.ID = uuid.New()
+	savedUser.CreatedAt = time.Now()
+
+	s.userSvcMock.
+		On(Create, mock.Anything, expectedUser, "testpwd").
+		Return(&savedUser, nil).
+		Once()
+
+	var header metadata.MD
+	u, err := s.client.Create(context.Background(), &pb.CreateRequest{
+		FirstName: "john",
+		Email:     "test@test.com",
+		Country:   "US",
+		Password:  "testpwd",
+	}, grpc.Header(&header))
+
+	s.NoError(err)
+	s.Equal(savedUser.ID.String(), u.Id)
+	s.Equal(savedUser.CreatedAt.UTC(), u.CreatedAt.AsTime())
+	s.Len(header.Get(srv.MetadataRequestID), 1)
+	s.Empty(header.Get("idempotent-replayed"))
+}
+
+func (s *grpcTestSuite) TestCreate_WithIdempotencyKey() {
+	expectedUser := user.User{Email: "test@test.com"}
+	s.userSvcMock.
+		On(CreateI, mock.Anything, "key", expectedUser, "").
+		Return(&user.User{ID: userID, Email: "test@test.com"}, true, nil).
+		Once()
+	s.userSvcMock.
+		On(CreateI, mock.Anything, "reused", expectedUser, "").
+		Return(nil, false, user.ErrIdempotencyKeyReused).
+		Once()
+
+	var header metadata.MD
+	u, err := s.client.Create(context.Background(), &pb.CreateRequest{Email: "test@test.com", IdempotencyKey: "key"}, grpc.Header(&header))
+	s.NoError(err)
+	s.Equal(userID.String(), u.Id)
+	s.Equal([]string{"true"}, header.Get("idempotent-replayed"))
+
+	_, err = s.client.Create(context.Background(), &pb.CreateRequest{Email: "test@test.com", IdempotencyKey: "reused"})
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (s *grpcTestSuite) TestUpdate() {
+	s.userSvcMock.
+		On(Update, mock.Anything, userID, user.User{Nickname: "dome"}, "").
+		Return(&user.User{ID: userID, Nickname: "dome"}, nil).
+		Once()
+	s.userSvcMock.
+		On(Update, mock.Anything, invalidUserID, user.User{Nickname: "dome"}, "").
+		Return(nil, user.ErrInvalidUserInputData).
+		Once()
+
+	u, err := s.client.Update(context.Background(), &pb.UpdateRequest{Id: userID.String(), Nickname: "dome"})
+	s.NoError(err)
+	s.Equal("dome", u.Nickname)
+
+	_, err = s.client.Update(context.Background(), &pb.UpdateRequest{Id: invalidUserID.String(), Nickname: "dome"})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *grpcTestSuite) TestDelete() {
+	s.userSvcMock.
+		On(Delete, mock.Anything, userID).
+		Return(nil).
+		Once()
+
+	_, err := s.client.Delete(context.Background(), &pb.DeleteRequest{Id: userID.String()})
+	s.NoError(err)
+}
+
+func (s *grpcTestSuite) TestList() {
+	s.userSvcMock.
+		On(List, mock.Anything, c.Pagination{Page: 1, PageSize: 2}, &user.User{Country: "UK"},
+			user.Sort{{Field: "nickname", Desc: true}}, user.Fields(nil)).
+		Return([]user.User{{ID: userID}, {ID: missingUserID}}, nil).
+		Once()
+	s.userSvcMock.
+		On(List, mock.Anything, c.Pagination{}, &user.User{}, user.Sort{{Field: "password"}}, user.Fields(nil)).
+		Return(nil, user.ErrInvalidSort).
+		Once()
+
+	res, err := s.client.List(context.Background(), &pb.ListRequest{Page: 1, PageSize: 2, Country: "UK", Sort: "-nickname"})
+	s.NoError(err)
+	s.Len(res.Users, 2)
+	s.Equal(userID.String(), res.Users[0].Id)
+	s.Equal(missingUserID.String(), res.Users[1].Id)
+
+	_, err = s.client.List(context.Background(), &pb.ListRequest{Sort: "password"})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *grpcTestSuite) TestWatch() {
+	filter := user.EventFilter{
+		Types:   []user.UserEventType{user.UserEventTypeDeleted},
+		UserIDs: []uuid.UUID{userID},
+	}
+	event := user.StoredEvent{
+		ID:        42,
+		UserEvent: user.UserEvent{EventID: uuid.New(), Type: user.UserEventTypeDeleted, UserID: userID, Version: 3},
+	}
+	s.userSvcMock.
+		On(Stream, mock.Anything, c.Ptr(int64(41)), filter, mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args.Get(3).(func(*user.StoredEvent) error)
+			s.NoError(send(&event))
+			s.NoError(send(nil))
+		}).
+		Return(nil).
+		Once()
+
+	stream, err := s.client.Watch(context.Background(), &pb.WatchRequest{
+		LastEventId: c.Ptr(int64(41)),
+		Types:       []string{string(user.UserEventTypeDeleted)},
+		UserIds:     []string{userID.String()},
+	})
+	s.Require().NoError(err)
+
+	res, err := stream.Recv()
+	s.Require().NoError(err)
+	s.Equal(int64(42), res.Id)
+	s.Equal(event.EventID.String(), res.Event.EventId)
+	s.Equal(string(user.UserEventTypeDeleted), res.Event.Type)
+	s.Equal(int64(3), res.Event.Version)
+
+	_, err = stream.Recv()
+	s.Equal(io.EOF, err)
+}
+
+func (s *grpcTestSuite) TestWatch_ReturnsErrorOnInvalidFilter() {
+	s.userSvcMock.
+		On(Stream, mock.Anything, (*int64)(nil), user.EventFilter{Types: []user.UserEventType{"USER_LOGGED_IN"}}, mock.Anything).
+		Return(user.ErrInvalidFilter).
+		Once()
+
+	stream, err := s.client.Watch(context.Background(), &pb.WatchRequest{Types: []string{"USER_LOGGED_IN"}})
+	s.Require().NoError(err)
+
+	_, err = stream.Recv()
+	s.Equal(codes.InvalidArgument, status.Code(err))
+
+	stream, err = s.client.Watch(context.Background(), &pb.WatchRequest{UserIds: []string{"invalid"}})
+	s.Require().NoError(err)
+
+	_, err = stream.Recv()
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
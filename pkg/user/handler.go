@@ -2,10 +2,15 @@ package user
 
 import (
 	"context"
+	"encoding/json"
 	"faceit/internal/common"
 	"faceit/internal/user"
 	"faceit/internal/user/api"
+	"faceit/pkg/server"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/types"
@@ -15,13 +20,28 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	// HeaderIdempotentReplayed is set on the responses what were replayed for a retried request with the same idempotency key
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	// HeaderMissingIDs lists the ids of the users what were not found when the users are listed by ids
+	HeaderMissingIDs = "Missing-Ids"
+)
+
 type (
 	userService interface {
 		Create(ctx context.Context, user user.User, password string) (*user.User, error)
-		Get(ctx context.Context, id uuid.UUID) (*user.User, error)
+		CreateIdempotent(ctx context.Context, key string, user user.User, password string) (*user.User, bool, error)
+		Get(ctx context.Context, id uuid.UUID, fields user.Fields) (*user.User, error)
 		Update(ctx context.Context, id uuid.UUID, user user.User, password string) (*user.User, error)
+		Replace(ctx context.Context, id uuid.UUID, user user.User, password string) (*user.User, error)
+		Patch(ctx context.Context, id uuid.UUID, patchType user.PatchType, patch []byte) (*user.User, error)
 		Delete(ctx context.Context, id uuid.UUID) error
-		List(ctx context.Context, pagination common.Pagination, filters *user.User) ([]user.User, error)
+		List(ctx context.Context, pagination common.Pagination, filters *user.User, sort user.Sort, fields user.Fields) ([]user.User, error)
+		ListAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]user.User, bool, error)
+		Search(ctx context.Context, pagination common.Pagination, filter user.Filter) ([]user.User, error)
+		BatchGet(ctx context.Context, ids []uuid.UUID, fields user.Fields) ([]user.User, []uuid.UUID, error)
+		StreamEvents(ctx context.Context, lastEventID *int64, filter user.EventFilter, send func(*user.StoredEvent) error) error
+		EventVerificationKeys() []user.VerificationKey
 	}
 
 	Handler struct {
@@ -30,12 +50,8 @@ type (
 	}
 )
 
-func NewHandler() (*Handler, error) {
-	svc, err := user.NewService()
-	if err != nil {
-		return nil, err
-	}
-
+// NewHandler creates the handler of the users with the user service, what is owned (and closed) by the caller.
+func NewHandler(svc *user.Service) *Handler {
 	t := common.GetEnv("REQUEST_TIMEOUT", "5s")
 	timeout, err := time.ParseDuration(t)
 	if err != nil {
@@ -46,17 +62,26 @@ func NewHandler() (*Handler, error) {
 	return &Handler{
 		timeout: timeout,
 		userSvc: svc,
-	}, nil
+	}
 }
 
 func (h Handler) List(ctx echo.Context, params api.ListParams) error {
+	if params.Id != nil {
+		return h.listByIDs(ctx, params)
+	}
+
 	c, cancel := h.contextWithTimeout(ctx)
 	defer cancel()
 
 	pagination := getListPagination(params)
 	filter := getListFilter(params)
+	var sort user.Sort
+	if params.Sort != nil {
+		sort = user.ParseSort(*params.Sort)
+	}
+	fields := getFields(params.Fields)
 
-	results, err := h.userSvc.List(c, pagination, &filter)
+	results, err := h.userSvc.List(c, pagination, &filter, sort, fields)
 	if err != nil {
 		log.Err(err).
 			Str("operation", "List").
@@ -64,22 +89,111 @@ func (h Handler) List(ctx echo.Context, params api.ListParams) error {
 			Str(common.CorrelationID, common.GetCorrelationID(c)).
 			Send()
 
-		switch err {
-		case user.ErrInvalidPagination, user.ErrInvalidFilter:
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		default:
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return server.NewHTTPError(err)
+	}
+
+	return ctx.JSON(http.StatusOK, toUserResponses(results, fields))
+}
+
+// listByIDs lists the users by the id parameters in the order of the ids, the missing ids are listed in the Missing-Ids header.
+func (h Handler) listByIDs(ctx echo.Context, params api.ListParams) error {
+	if params.Page != nil || params.Pagesize != nil || params.Sort != nil || getListFilter(params) != (user.User{}) {
+		return echo.NewHTTPError(http.StatusBadRequest, "id can't be combined with other parameters than fields")
+	}
+
+	fields := getFields(params.Fields)
+	users, missing, err := h.batchGet(ctx, *params.Id, fields)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		ids := make([]string, 0, len(missing))
+		for _, id := range missing {
+			ids = append(ids, id.String())
 		}
+		ctx.Response().Header().Set(HeaderMissingIDs, strings.Join(ids, ","))
+	}
+	return ctx.JSON(http.StatusOK, toUserResponses(users, fields))
+}
+
+// BatchGet returns the users of the ids of the request body in the order of the ids with the missing ids.
+func (h Handler) BatchGet(ctx echo.Context, params api.BatchGetParams) error {
+	var req api.BatchGetRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	fields := getFields(params.Fields)
+	users, missing, err := h.batchGet(ctx, req.Ids, fields)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) > 0 {
+		// the generated result can't omit the fields of the users what are not selected
+		return ctx.JSON(http.StatusOK, map[string]interface{}{
+			"users":   toUserResponses(users, fields),
+			"missing": missing,
+		})
+	}
+	return ctx.JSON(http.StatusOK, api.BatchGetResult{
+		Users:   toFullUserResponses(users),
+		Missing: missing,
+	})
+}
+
+func (h Handler) batchGet(ctx echo.Context, ids []uuid.UUID, fields user.Fields) ([]user.User, []uuid.UUID, error) {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	users, missing, err := h.userSvc.BatchGet(c, ids, fields)
+	if err != nil {
+		log.Err(err).
+			Str("operation", "BatchGet").
+			Str("params", ctx.QueryString()).
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Int("ids", len(ids)).
+			Send()
+
+		return nil, nil, server.NewHTTPError(err)
+	}
+	return users, missing, nil
+}
+
+// Search lists the users what match the filter tree of the request body.
+func (h Handler) Search(ctx echo.Context, params api.SearchParams) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	var req api.SearchRequest
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	pagination := common.Pagination{}
+	if params.Page != nil {
+		pagination.Page = *params.Page
 	}
+	if params.Pagesize != nil {
+		pagination.PageSize = *params.Pagesize
+	}
+
+	results, err := h.userSvc.Search(c, pagination, toFilter(req.Filter))
+	if err != nil {
+		log.Err(err).
+			Str("operation", "Search").
+			Str("params", ctx.QueryString()).
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Send()
 
-	users := []api.UserResponse{}
-	for _, r := range results {
-		users = append(users, toUserResponse(&r))
+		return server.NewHTTPError(err)
 	}
-	return ctx.JSON(http.StatusOK, users)
+
+	return ctx.JSON(http.StatusOK, toUserResponses(results, nil))
 }
 
-func (h Handler) Create(ctx echo.Context) error {
+func (h Handler) Create(ctx echo.Context, params api.CreateParams) error {
 	c, cancel := h.contextWithTimeout(ctx)
 	defer cancel()
 
@@ -101,19 +215,25 @@ func (h Handler) Create(ctx echo.Context) error {
 		pass = string(*up.Password)
 	}
 
-	u, err := h.userSvc.Create(c, userIn, pass)
+	var u *user.User
+	var replayed bool
+	var err error
+	if params.IdempotencyKey != nil {
+		u, replayed, err = h.userSvc.CreateIdempotent(c, *params.IdempotencyKey, userIn, pass)
+	} else {
+		u, err = h.userSvc.Create(c, userIn, pass)
+	}
 	if err != nil {
 		log.Err(err).
 			Str("operation", "Create").
 			Str(common.CorrelationID, common.GetCorrelationID(c)).
 			Send()
 
-		switch err {
-		case user.ErrNewUserWithID, user.ErrInvalidUserInputData:
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		default:
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
-		}
+		return server.NewHTTPError(err)
+	}
+
+	if replayed {
+		ctx.Response().Header().Set(HeaderIdempotentReplayed, "true")
 	}
 	return ctx.JSON(http.StatusCreated, toUserResponse(u))
 }
@@ -129,21 +249,17 @@ func (h Handler) DeleteByID(ctx echo.Context, id uuid.UUID) error {
 			Stringer("ID", id).
 			Send()
 
-		switch err {
-		case user.ErrNilUUIDNotAllowed:
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		default:
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
-		}
+		return server.NewHTTPError(err)
 	}
 	return ctx.NoContent(http.StatusNoContent)
 }
 
-func (h Handler) GetByID(ctx echo.Context, id uuid.UUID) error {
+func (h Handler) GetByID(ctx echo.Context, id uuid.UUID, params api.GetByIDParams) error {
 	c, cancel := h.contextWithTimeout(ctx)
 	defer cancel()
 
-	u, err := h.userSvc.Get(c, id)
+	fields := getFields(params.Fields)
+	u, err := h.userSvc.Get(c, id, fields)
 	if err != nil {
 		log.Err(err).
 			Str("operation", "GetByID").
@@ -151,19 +267,22 @@ func (h Handler) GetByID(ctx echo.Context, id uuid.UUID) error {
 			Stringer("ID", id).
 			Send()
 
-		switch err {
-		case user.ErrUserNotFound:
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
-		case user.ErrNilUUIDNotAllowed:
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		default:
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
-		}
+		return server.NewHTTPError(err)
+	}
+
+	if len(fields) > 0 {
+		return ctx.JSON(http.StatusOK, toSparseUserResponse(u, fields))
 	}
 	return ctx.JSON(http.StatusOK, toUserResponse(u))
 }
 
+// UpdateByID changes the set fields of the JSON body, or applies the JSON Merge Patch or the JSON Patch of the body
+// by its content type.
 func (h Handler) UpdateByID(ctx echo.Context, id uuid.UUID) error {
+	if t, ok := patchType(ctx); ok {
+		return h.patchByID(ctx, id, t)
+	}
+
 	c, cancel := h.contextWithTimeout(ctx)
 	defer cancel()
 
@@ -201,16 +320,133 @@ func (h Handler) UpdateByID(ctx echo.Context, id uuid.UUID) error {
 			Stringer("ID", id).
 			Send()
 
-		switch err {
-		case user.ErrNilUUIDNotAllowed, user.ErrInvalidUserInputData:
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		default:
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
-		}
+		return server.NewHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, toUserResponse(u))
+}
+
+func (h Handler) patchByID(ctx echo.Context, id uuid.UUID, patchType user.PatchType) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	patch, err := readPatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	u, err := h.userSvc.Patch(c, id, patchType, patch)
+	if err != nil {
+		log.Err(err).
+			Str("operation", "PatchByID").
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Stringer("ID", id).
+			Str("type", string(patchType)).
+			Send()
+
+		return server.NewHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, toUserResponse(u))
+}
+
+// ReplaceByID saves every field of the user, the password is only changed when it's set.
+func (h Handler) ReplaceByID(ctx echo.Context, id uuid.UUID) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	var up *api.UserWithPassword
+	if err := ctx.Bind(&up); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userIn := user.User{
+		FirstName: up.FirstName,
+		LastName:  up.LastName,
+		Nickname:  up.Nickname,
+		Email:     string(up.Email),
+		Country:   up.Country,
+	}
+	password := ""
+	if up.Password != nil {
+		password = *up.Password
+	}
+
+	u, err := h.userSvc.Replace(c, id, userIn, password)
+	if err != nil {
+		log.Err(err).
+			Str("operation", "ReplaceByID").
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Stringer("ID", id).
+			Send()
+
+		return server.NewHTTPError(err)
 	}
 	return ctx.JSON(http.StatusOK, toUserResponse(u))
 }
 
+// StreamEvents writes the user events as Server-Sent Events until the client disconnects.
+// The request timeout isn't applied as the stream is long living.
+func (h Handler) StreamEvents(ctx echo.Context, params api.StreamEventsParams) error {
+	c := context.WithValue(ctx.Request().Context(), common.CorrelationID, common.GetEchoCorrelationID(ctx))
+
+	filter := getStreamFilter(params)
+	if err := filter.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	err := h.userSvc.StreamEvents(c, params.LastEventID, filter, func(event *user.StoredEvent) error {
+		if err := writeServerSentEvent(res, event); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+	if err != nil {
+		log.Err(err).
+			Str("operation", "StreamEvents").
+			Str("params", ctx.QueryString()).
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Send()
+	}
+
+	// the response is already committed, so the error could not be returned to the client
+	return nil
+}
+
+// ListEventKeys returns the public keys what verify the signatures of the user events published to RabbitMQ.
+func (h Handler) ListEventKeys(ctx echo.Context) error {
+	keys := []api.EventKey{}
+	for _, k := range h.userSvc.EventVerificationKeys() {
+		keys = append(keys, api.EventKey{
+			Kid:       k.KeyID,
+			Alg:       api.EventKeyAlg(k.Algorithm),
+			PublicKey: k.PublicKey,
+		})
+	}
+	return ctx.JSON(http.StatusOK, api.EventKeys{Keys: keys})
+}
+
+// writeServerSentEvent writes the event in the text/event-stream format, or a comment line as keepalive when it's nil.
+func writeServerSentEvent(w io.Writer, event *user.StoredEvent) error {
+	if event == nil {
+		_, err := io.WriteString(w, ": keepalive\n\n")
+		return err
+	}
+
+	data, err := json.Marshal(&event.UserEvent)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
 func toUserResponse(u *user.User) api.UserResponse {
 	return api.UserResponse{
 		Id:        u.ID,
@@ -224,6 +460,46 @@ func toUserResponse(u *user.User) api.UserResponse {
 	}
 }
 
+// toUserResponses returns the user responses, or only their selected fields when the fields are set
+func toUserResponses(users []user.User, fields user.Fields) interface{} {
+	if len(fields) > 0 {
+		sparse := []map[string]interface{}{}
+		for _, u := range users {
+			sparse = append(sparse, toSparseUserResponse(&u, fields))
+		}
+		return sparse
+	}
+	return toFullUserResponses(users)
+}
+
+func toFullUserResponses(users []user.User) []api.UserResponse {
+	res := []api.UserResponse{}
+	for _, u := range users {
+		res = append(res, toUserResponse(&u))
+	}
+	return res
+}
+
+// toSparseUserResponse returns only the selected fields of the user response
+func toSparseUserResponse(u *user.User, fields user.Fields) map[string]interface{} {
+	res := toUserResponse(u)
+	sparse := map[string]interface{}{"id": res.Id}
+	for field, value := range map[string]interface{}{
+		"first_name": res.FirstName,
+		"last_name":  res.LastName,
+		"nickname":   res.Nickname,
+		"email":      res.Email,
+		"country":    res.Country,
+		"created_at": res.CreatedAt,
+		"updated_at": res.UpdatedAt,
+	} {
+		if fields.Has(field) {
+			sparse[field] = value
+		}
+	}
+	return sparse
+}
+
 func (h Handler) contextWithTimeout(ctx echo.Context) (context.Context, context.CancelFunc) {
 	ec := ctx.Request().Context()
 	c := context.WithValue(ec, common.CorrelationID, common.GetEchoCorrelationID(ctx))
@@ -243,6 +519,13 @@ func getListPagination(p api.ListParams) common.Pagination {
 	return pagination
 }
 
+func getFields(fields *string) user.Fields {
+	if fields == nil {
+		return nil
+	}
+	return user.ParseFields(*fields)
+}
+
 func getListFilter(p api.ListParams) user.User {
 	filter := user.User{}
 	if p.FirstName != nil {
@@ -267,3 +550,50 @@ func getListFilter(p api.ListParams) user.User {
 
 	return filter
 }
+
+func toFilter(f api.Filter) user.Filter {
+	filter := user.Filter{}
+	if f.And != nil {
+		filter.And = toFilters(*f.And)
+	}
+	if f.Or != nil {
+		filter.Or = toFilters(*f.Or)
+	}
+	if f.Not != nil {
+		not := toFilter(*f.Not)
+		filter.Not = &not
+	}
+	if f.Field != nil {
+		filter.Field = string(*f.Field)
+	}
+	if f.Op != nil {
+		filter.Op = user.FilterOperator(*f.Op)
+	}
+	if f.Value != nil {
+		filter.Value = *f.Value
+	}
+	return filter
+}
+
+func toFilters(filters []api.Filter) []user.Filter {
+	converted := make([]user.Filter, 0, len(filters))
+	for _, f := range filters {
+		converted = append(converted, toFilter(f))
+	}
+	return converted
+}
+
+func getStreamFilter(p api.StreamEventsParams) user.EventFilter {
+	filter := user.EventFilter{}
+	if p.Type != nil {
+		for _, t := range *p.Type {
+			filter.Types = append(filter.Types, user.UserEventType(t))
+		}
+	}
+
+	if p.UserId != nil {
+		filter.UserIDs = *p.UserId
+	}
+
+	return filter
+}
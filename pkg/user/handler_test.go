@@ -1,12 +1,16 @@
 package user
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"faceit/internal/common"
 	c "faceit/internal/common"
 	"faceit/internal/user"
 	"faceit/internal/user/api"
+	srv "faceit/pkg/server"
 	"fmt"
 	"io"
 	"net/http"
@@ -27,9 +31,16 @@ const (
 	usersUrl = baseUrl + "/users"
 	Get      = "Get"
 	List     = "List"
+	Search   = "Search"
+	BatchGet = "BatchGet"
 	Create   = "Create"
+	CreateI  = "CreateIdempotent"
 	Delete   = "Delete"
 	Update   = "Update"
+	Replace  = "Replace"
+	Patch    = "Patch"
+	Stream   = "StreamEvents"
+	Keys     = "EventVerificationKeys"
 )
 
 var (
@@ -78,15 +89,16 @@ func (s *handlerTestSuite) SetupSuite() {
 	s.e.DELETE(usersUrl+"/:id", s.wrapper.DeleteByID)
 	s.e.GET(usersUrl+"/:id", s.wrapper.GetByID)
 	s.e.PATCH(usersUrl+"/:id", s.wrapper.UpdateByID)
+	s.e.PUT(usersUrl+"/:id", s.wrapper.ReplaceByID)
 }
 
-func (s handlerTestSuite) TestGetByID() {
+func (s *handlerTestSuite) TestGetByID() {
 	u := user.User{
 		ID:    userID,
 		Email: "test@test.com",
 	}
 	s.userSvcMock.
-		On(Get, mock.Anything, userID).
+		On(Get, mock.Anything, userID, user.Fields(nil)).
 		Return(&u, nil).
 		Once()
 
@@ -101,10 +113,37 @@ func (s handlerTestSuite) TestGetByID() {
 	s.Equal(types.Email("test@test.com"), actualUser.Email)
 }
 
-func (s handlerTestSuite) TestGetByID_ReturnsError() {
+func (s *handlerTestSuite) TestGetByID_ReturnsSelectedFields() {
+	s.userSvcMock.
+		On(Get, mock.Anything, userID, user.Fields{"nickname"}).
+		Return(&user.User{ID: userID, Nickname: "dome"}, nil).
+		Once()
+
+	ctx, rec := s.call(http.MethodGet, c.Ptr(userID.String()+"?fields=nickname"), nil)
+	ctx.SetParamValues(userID.String())
+
+	s.NoError(s.wrapper.GetByID(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+	s.JSONEq(fmt.Sprintf(`{"id": %q, "nickname": "dome"}`, userID), rec.Body.String())
+}
+
+func (s *handlerTestSuite) TestGetByID_ReturnsErrorOnInvalidFields() {
+	s.userSvcMock.
+		On(Get, mock.Anything, userID, user.Fields{"password"}).
+		Return(nil, fmt.Errorf("%w: unknown field", user.ErrInvalidFields)).
+		Once()
+
+	ctx, _ := s.call(http.MethodGet, c.Ptr(userID.String()+"?fields=password"), nil)
+	ctx.SetParamValues(userID.String())
+
+	err := s.wrapper.GetByID(ctx).(*echo.HTTPError)
+	s.Equal(http.StatusBadRequest, err.Code)
+}
+
+func (s *handlerTestSuite) TestGetByID_ReturnsError() {
 	prepareMock := func(id uuid.UUID, returnErr error) {
 		s.userSvcMock.
-			On(Get, mock.Anything, id).
+			On(Get, mock.Anything, id, user.Fields(nil)).
 			Return(nil, returnErr).
 			Once()
 	}
@@ -152,7 +191,7 @@ func (s handlerTestSuite) TestGetByID_ReturnsError() {
 	}
 }
 
-func (s handlerTestSuite) TestList() {
+func (s *handlerTestSuite) TestList() {
 	pagination := common.Pagination{Page: 1, PageSize: 2}
 	filter := user.User{
 		FirstName: "fn",
@@ -165,12 +204,13 @@ func (s handlerTestSuite) TestList() {
 		{Email: "res1@email.com"},
 		{Email: "res2@email.com"},
 	}
+	sort := user.Sort{{Field: "last_name"}, {Field: "created_at", Desc: true}}
 	s.userSvcMock.
-		On(List, mock.Anything, pagination, &filter).
+		On(List, mock.Anything, pagination, &filter, sort, user.Fields(nil)).
 		Return(expectedUsers, nil).
 		Once()
 
-	params := fmt.Sprintf("?page=%d&pagesize=%d&first_name=%s&last_name=%s&nickname=%s&email=%s&country=%s", 1, 2, "fn", "ln", "nn", "em", "uk")
+	params := fmt.Sprintf("?page=%d&pagesize=%d&first_name=%s&last_name=%s&nickname=%s&email=%s&country=%s&sort=%s", 1, 2, "fn", "ln", "nn", "em", "uk", "last_name,-created_at")
 	ctx, rec := s.call(http.MethodGet, c.Ptr(params), nil)
 
 	s.NoError(s.wrapper.List(ctx))
@@ -184,7 +224,108 @@ func (s handlerTestSuite) TestList() {
 	s.Equal("res2@email.com", res[1].Email)
 }
 
-func (s handlerTestSuite) TestList_ReturnsErrorOnInvalidParameters() {
+func (s *handlerTestSuite) TestList_ReturnsSelectedFields() {
+	secondID := uuid.New()
+	s.userSvcMock.
+		On(List, mock.Anything, common.Pagination{}, &user.User{}, user.Sort(nil), user.Fields{"id", "nickname"}).
+		Return([]user.User{{ID: userID, Nickname: "johndoe"}, {ID: secondID, Nickname: "janedoe"}}, nil).
+		Once()
+
+	ctx, rec := s.call(http.MethodGet, c.Ptr("?fields=id,nickname"), nil)
+
+	s.NoError(s.wrapper.List(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+	s.JSONEq(fmt.Sprintf(`[{"id": %q, "nickname": "johndoe"}, {"id": %q, "nickname": "janedoe"}]`, userID, secondID), rec.Body.String())
+}
+
+func (s *handlerTestSuite) TestList_ByIDs() {
+	s.userSvcMock.
+		On(BatchGet, mock.Anything, []uuid.UUID{userID, missingUserID}, user.Fields(nil)).
+		Return([]user.User{{ID: userID, Email: "johndoe@email.com"}}, []uuid.UUID{missingUserID}, nil).
+		Once()
+
+	ctx, rec := s.call(http.MethodGet, c.Ptr(fmt.Sprintf("?id=%s&id=%s", userID, missingUserID)), nil)
+
+	s.NoError(s.wrapper.List(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal(missingUserID.String(), rec.Header().Get(HeaderMissingIDs))
+
+	var res []api.UserResponse
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Len(res, 1)
+	s.Equal(userID, res[0].Id)
+}
+
+func (s *handlerTestSuite) TestList_ByIDsReturnsErrorWithOtherParameters() {
+	ctx, _ := s.call(http.MethodGet, c.Ptr(fmt.Sprintf("?id=%s&country=US", userID)), nil)
+
+	err := s.wrapper.List(ctx).(*echo.HTTPError)
+	s.Equal(http.StatusBadRequest, err.Code)
+	s.userSvcMock.AssertNotCalled(s.T(), BatchGet)
+}
+
+func (s *handlerTestSuite) TestBatchGet() {
+	s.userSvcMock.
+		On(BatchGet, mock.Anything, []uuid.UUID{missingUserID, userID}, user.Fields{"nickname"}).
+		Return([]user.User{{ID: userID, Nickname: "johndoe"}}, []uuid.UUID{missingUserID}, nil).
+		Once()
+
+	body := fmt.Sprintf(`{"ids": [%q, %q]}`, missingUserID, userID)
+	ctx, rec := s.call(http.MethodPost, c.Ptr(":batchGet?fields=nickname"), strings.NewReader(body))
+
+	s.NoError(s.wrapper.BatchGet(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+	s.JSONEq(fmt.Sprintf(`{"users": [{"id": %q, "nickname": "johndoe"}], "missing": [%q]}`, userID, missingUserID), rec.Body.String())
+}
+
+func (s *handlerTestSuite) TestBatchGet_ReturnsEveryField() {
+	s.userSvcMock.
+		On(BatchGet, mock.Anything, []uuid.UUID{userID, missingUserID}, user.Fields(nil)).
+		Return([]user.User{{ID: userID, Nickname: "johndoe", Email: "johndoe@email.com"}}, []uuid.UUID{missingUserID}, nil).
+		Once()
+
+	body := fmt.Sprintf(`{"ids": [%q, %q]}`, userID, missingUserID)
+	ctx, rec := s.call(http.MethodPost, c.Ptr(":batchGet"), strings.NewReader(body))
+
+	s.NoError(s.wrapper.BatchGet(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+
+	var res api.BatchGetResult
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Require().Len(res.Users, 1)
+	s.Equal(userID, res.Users[0].Id)
+	s.Equal("johndoe", res.Users[0].Nickname)
+	s.EqualValues("johndoe@email.com", res.Users[0].Email)
+	s.Equal([]uuid.UUID{missingUserID}, res.Missing)
+}
+
+func (s *handlerTestSuite) TestBatchGet_ReturnsError() {
+	for _, test := range []struct {
+		name           string
+		body           string
+		returnErr      error
+		expectedStatus int
+	}{
+		{"invalid body", `{"ids": ["42"]}`, nil, http.StatusBadRequest},
+		{"invalid ids", `{"ids": []}`, user.ErrInvalidIDs, http.StatusBadRequest},
+		{"service error", fmt.Sprintf(`{"ids": [%q]}`, userID), errors.New("db error"), http.StatusInternalServerError},
+	} {
+		s.Run(test.name, func() {
+			if test.returnErr != nil {
+				s.userSvcMock.
+					On(BatchGet, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, nil, test.returnErr).
+					Once()
+			}
+			ctx, _ := s.call(http.MethodPost, c.Ptr(":batchGet"), strings.NewReader(test.body))
+
+			err := s.wrapper.BatchGet(ctx).(*echo.HTTPError)
+			s.Equal(test.expectedStatus, err.Code)
+		})
+	}
+}
+
+func (s *handlerTestSuite) TestList_ReturnsErrorOnInvalidParameters() {
 	invalidPaginationQuery := "?page=-1&pagesize=-1"
 	invalidPagination := common.Pagination{Page: -1, PageSize: -1}
 	invalidFilterQuery := "?first_name=x&last_name=x&nickname=x&email=x&country=x"
@@ -195,9 +336,9 @@ func (s handlerTestSuite) TestList_ReturnsErrorOnInvalidParameters() {
 		Email:     "x",
 		Country:   "x",
 	}
-	prepareMock := func(p common.Pagination, f user.User, returnErr error) {
+	prepareMock := func(p common.Pagination, f user.User, sort user.Sort, returnErr error) {
 		s.userSvcMock.
-			On(List, mock.Anything, p, &f).
+			On(List, mock.Anything, p, &f, sort, user.Fields(nil)).
 			Return(nil, returnErr).
 			Once()
 	}
@@ -206,12 +347,19 @@ func (s handlerTestSuite) TestList_ReturnsErrorOnInvalidParameters() {
 		{
 			name:        "invalid pagination",
 			id:          c.Ptr(invalidPaginationQuery),
-			prepareMock: func() { prepareMock(invalidPagination, user.User{}, user.ErrInvalidPagination) },
+			prepareMock: func() { prepareMock(invalidPagination, user.User{}, nil, user.ErrInvalidPagination) },
 		},
 		{
-			name:        "invalid pagination",
+			name:        "invalid filter",
 			id:          c.Ptr(invalidFilterQuery),
-			prepareMock: func() { prepareMock(common.Pagination{}, invalidFilter, user.ErrInvalidFilter) },
+			prepareMock: func() { prepareMock(common.Pagination{}, invalidFilter, nil, user.ErrInvalidFilter) },
+		},
+		{
+			name: "unknown sort field",
+			id:   c.Ptr("?sort=password"),
+			prepareMock: func() {
+				prepareMock(common.Pagination{}, user.User{}, user.Sort{{Field: "password"}}, fmt.Errorf("%w: unknown sort field", user.ErrInvalidSort))
+			},
 		},
 	}
 
@@ -227,7 +375,7 @@ func (s handlerTestSuite) TestList_ReturnsErrorOnInvalidParameters() {
 
 }
 
-func (s handlerTestSuite) TestCreate() {
+func (s *handlerTestSuite) TestCreate() {
 	expectedUser := user.User{
 		FirstName: "john",
 		LastName:  "doe",
@@ -260,7 +408,7 @@ func (s handlerTestSuite) TestCreate() {
 	s.Equal("US", actualUser.Country)
 }
 
-func (s handlerTestSuite) TestCreate_ReturnsError() {
+func (s *handlerTestSuite) TestCreate_ReturnsError() {
 	expectedUser := user.User{
 		FirstName: "john",
 		LastName:  "doe",
@@ -312,7 +460,119 @@ func (s handlerTestSuite) TestCreate_ReturnsError() {
 	}
 }
 
-func (s handlerTestSuite) TestDeleteByID() {
+func (s *handlerTestSuite) TestCreate_WithIdempotencyKey() {
+	expectedUser := user.User{
+		FirstName: "john",
+		LastName:  "doe",
+		Nickname:  "johndoe",
+		Email:     "test@test.com",
+		Country:   "US",
+	}
+	savedUser := expectedUser
+	savedUser.ID = uuid.New()
+
+	tests := []struct {
+		name     string
+		replayed bool
+	}{
+		{name: "first request"},
+		{name: "replayed request", replayed: true},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			s.userSvcMock.
+				On(CreateI, mock.Anything, "key-"+test.name, expectedUser, "testpwd").
+				Return(&savedUser, test.replayed, nil).
+				Once()
+
+			jsonIn, err := toJsonBody(expectedUser, "testpwd")
+			s.NoError(err)
+			ctx, rec := s.call(http.MethodPost, nil, jsonIn)
+			ctx.Request().Header.Set("Idempotency-Key", "key-"+test.name)
+
+			s.NoError(s.wrapper.Create(ctx))
+			s.Equal(http.StatusCreated, rec.Code)
+			if test.replayed {
+				s.Equal("true", rec.Header().Get(HeaderIdempotentReplayed))
+			} else {
+				s.Empty(rec.Header().Get(HeaderIdempotentReplayed))
+			}
+
+			actualUser, err := asUserResponse(rec.Body.Bytes())
+			s.NoError(err)
+			s.Equal(savedUser.ID, actualUser.Id)
+		})
+	}
+}
+
+func (s *handlerTestSuite) TestCreate_WithIdempotencyKey_ReturnsError() {
+	expectedUser := user.User{
+		FirstName: "john",
+		LastName:  "doe",
+		Nickname:  "johndoe",
+		Email:     "test@test.com",
+		Country:   "US",
+	}
+
+	tests := []struct {
+		name           string
+		returnErr      error
+		expectedStatus int
+	}{
+		{name: "in progress", returnErr: user.ErrIdempotencyKeyInProgress, expectedStatus: http.StatusConflict},
+		{name: "reused", returnErr: user.ErrIdempotencyKeyReused, expectedStatus: http.StatusUnprocessableEntity},
+		{name: "invalid data", returnErr: user.ErrInvalidUserInputData, expectedStatus: http.StatusBadRequest},
+		{name: "service error", returnErr: errors.New("any error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			s.userSvcMock.
+				On(CreateI, mock.Anything, "key-"+test.name, expectedUser, "testpwd").
+				Return(nil, false, test.returnErr).
+				Once()
+
+			jsonIn, e := toJsonBody(expectedUser, "testpwd")
+			s.NoError(e)
+			ctx, _ := s.call(http.MethodPost, nil, jsonIn)
+			ctx.Request().Header.Set("Idempotency-Key", "key-"+test.name)
+
+			err := s.wrapper.Create(ctx).(*echo.HTTPError)
+			s.Equal(test.expectedStatus, err.Code)
+		})
+	}
+}
+
+func (s *handlerTestSuite) TestCreate_ReturnsValidationProblem() {
+	invalidUser := user.User{FirstName: "j", LastName: "d", Nickname: "johndoe", Email: "test@test.com", Country: "US"}
+	validationErr := &user.ValidationError{Fields: []user.FieldError{
+		{Field: "first_name", Rule: user.RuleMinLength, Message: "first name is too short"},
+		{Field: "last_name", Rule: user.RuleMinLength, Message: "last name is too short"},
+	}}
+	s.userSvcMock.
+		On(Create, mock.Anything, invalidUser, "testpwd").
+		Return(nil, validationErr).
+		Once()
+
+	jsonIn, err := toJsonBody(invalidUser, "testpwd")
+	s.NoError(err)
+	ctx, rec := s.call(http.MethodPost, nil, jsonIn)
+	ctx.Request().Header.Set(echo.HeaderAccept, srv.MIMEApplicationProblemJSON)
+
+	srv.HTTPErrorHandler(s.wrapper.Create(ctx), ctx)
+
+	s.Equal(http.StatusBadRequest, rec.Code)
+	var p api.Problem
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &p))
+	s.Equal("invalid_user", p.Code)
+	s.Equal(&[]api.FieldError{
+		{Field: "first_name", Rule: api.FieldErrorRuleMinLength, Message: "first name is too short"},
+		{Field: "last_name", Rule: api.FieldErrorRuleMinLength, Message: "last name is too short"},
+	}, p.Errors)
+}
+
+func (s *handlerTestSuite) TestDeleteByID() {
 	s.userSvcMock.
 		On(Delete, mock.Anything, userID).
 		Return(nil).
@@ -324,7 +584,7 @@ func (s handlerTestSuite) TestDeleteByID() {
 	s.Equal(http.StatusNoContent, rec.Code)
 }
 
-func (s handlerTestSuite) TestDeleteByID_ReturnsError() {
+func (s *handlerTestSuite) TestDeleteByID_ReturnsError() {
 	prepareMock := func(id uuid.UUID, returnErr error) {
 		s.userSvcMock.
 			On(Delete, mock.Anything, id).
@@ -351,6 +611,12 @@ func (s handlerTestSuite) TestDeleteByID_ReturnsError() {
 			expectedStatus: http.StatusInternalServerError,
 			prepareMock:    func() { prepareMock(errorUserID, errors.New("any error")) },
 		},
+		{
+			name:           "not found",
+			id:             c.Ptr(missingUserID.String()),
+			expectedStatus: http.StatusNotFound,
+			prepareMock:    func() { prepareMock(missingUserID, user.ErrUserNotFound) },
+		},
 	}
 
 	for _, test := range tests {
@@ -369,7 +635,7 @@ func (s handlerTestSuite) TestDeleteByID_ReturnsError() {
 	}
 }
 
-func (s handlerTestSuite) TestUpdate() {
+func (s *handlerTestSuite) TestUpdate() {
 	id := uuid.New()
 	expectedUser := user.User{
 		FirstName: "john",
@@ -403,7 +669,7 @@ func (s handlerTestSuite) TestUpdate() {
 	s.Equal("US", actualUser.Country)
 }
 
-func (s handlerTestSuite) TestUpdate_ReturnsError() {
+func (s *handlerTestSuite) TestUpdate_ReturnsError() {
 	expectedUser := user.User{
 		FirstName: "john",
 		LastName:  "doe",
@@ -438,6 +704,30 @@ func (s handlerTestSuite) TestUpdate_ReturnsError() {
 			expectedStatus: http.StatusInternalServerError,
 			prepareMock:    func() { prepareMock("2", errors.New("any error")) },
 		},
+		{
+			name:           "not found",
+			id:             common.Ptr(missingUserID.String()),
+			expectedStatus: http.StatusNotFound,
+			prepareMock:    func() { prepareMock("3", user.ErrUserNotFound) },
+		},
+		{
+			name:           "wrapped invalid data",
+			id:             common.Ptr(invalidUserID.String()),
+			expectedStatus: http.StatusBadRequest,
+			prepareMock:    func() { prepareMock("4", fmt.Errorf("%w: invalid email address", user.ErrInvalidUserInputData)) },
+		},
+		{
+			name:           "email taken",
+			id:             common.Ptr(userID.String()),
+			expectedStatus: http.StatusConflict,
+			prepareMock:    func() { prepareMock("5", user.ErrEmailTaken) },
+		},
+		{
+			name:           "timeout",
+			id:             common.Ptr(userID.String()),
+			expectedStatus: http.StatusGatewayTimeout,
+			prepareMock:    func() { prepareMock("6", context.DeadlineExceeded) },
+		},
 	}
 
 	for i, test := range tests {
@@ -455,7 +745,201 @@ func (s handlerTestSuite) TestUpdate_ReturnsError() {
 	}
 }
 
-func (s handlerTestSuite) call(method string, id *string, body io.Reader) (echo.Context, *httptest.ResponseRecorder) {
+func (s *handlerTestSuite) TestUpdate_AppliesPatch() {
+	id := uuid.New()
+	savedUser := user.User{ID: id, Nickname: "johnny", Email: "johnny@email.com"}
+
+	for _, patchType := range []user.PatchType{user.PatchTypeMerge, user.PatchTypeJSON} {
+		s.Run(string(patchType), func() {
+			patch := `{"nickname": "johnny"}`
+			if patchType == user.PatchTypeJSON {
+				patch = `[{"op": "test", "path": "/nickname", "value": "johndoe"}, {"op": "replace", "path": "/nickname", "value": "johnny"}]`
+			}
+			s.userSvcMock.
+				On(Patch, mock.Anything, id, patchType, []byte(patch)).
+				Return(&savedUser, nil).
+				Once()
+
+			ctx, rec := s.call(http.MethodPatch, common.Ptr(id.String()), strings.NewReader(patch))
+			ctx.Request().Header.Set(echo.HeaderContentType, string(patchType)+"; charset=utf-8")
+
+			s.NoError(s.wrapper.UpdateByID(ctx))
+			s.Equal(http.StatusOK, rec.Code)
+			actualUser, err := asUserResponse(rec.Body.Bytes())
+			s.NoError(err)
+			s.Equal("johnny", actualUser.Nickname)
+		})
+	}
+}
+
+func (s *handlerTestSuite) TestUpdate_ReturnsConflictOnFailedPatch() {
+	for _, returnErr := range []error{user.ErrPatchTestFailed, user.ErrUserModified} {
+		id := uuid.New()
+		s.userSvcMock.
+			On(Patch, mock.Anything, id, user.PatchTypeJSON, mock.Anything).
+			Return(nil, returnErr).
+			Once()
+
+		ctx, _ := s.call(http.MethodPatch, common.Ptr(id.String()), strings.NewReader(`[{"op": "test", "path": "/nickname", "value": "janedoe"}]`))
+		ctx.Request().Header.Set(echo.HeaderContentType, string(user.PatchTypeJSON))
+
+		err := s.wrapper.UpdateByID(ctx).(*echo.HTTPError)
+		s.Equal(http.StatusConflict, err.Code)
+	}
+}
+
+func (s *handlerTestSuite) TestReplace() {
+	id := uuid.New()
+	expectedUser := user.User{
+		FirstName: "john",
+		LastName:  "doe",
+		Nickname:  "johndoe",
+		Email:     "test@test.com",
+		Country:   "US",
+	}
+	savedUser := expectedUser
+	savedUser.ID = id
+
+	s.userSvcMock.
+		On(Replace, mock.Anything, id, expectedUser, "testpwd").
+		Return(&savedUser, nil).
+		Once()
+
+	jsonIn, err := toJsonBody(expectedUser, "testpwd")
+	s.NoError(err)
+	ctx, rec := s.call(http.MethodPut, common.Ptr(id.String()), jsonIn)
+
+	s.NoError(s.wrapper.ReplaceByID(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+
+	actualUser, err := asUserResponse(rec.Body.Bytes())
+	s.NoError(err)
+	s.Equal(id, actualUser.Id)
+	s.Equal("johndoe", actualUser.Nickname)
+}
+
+func (s *handlerTestSuite) TestReplace_ReturnsError() {
+	s.userSvcMock.
+		On(Replace, mock.Anything, missingUserID, mock.Anything, "").
+		Return(nil, user.ErrUserNotFound).
+		Once()
+
+	ctx, _ := s.call(http.MethodPut, common.Ptr(missingUserID.String()), strings.NewReader(
+		`{"first_name": "john", "last_name": "doe", "nickname": "johndoe", "email": "test@test.com", "country": "US"}`))
+
+	err := s.wrapper.ReplaceByID(ctx).(*echo.HTTPError)
+	s.Equal(http.StatusNotFound, err.Code)
+}
+
+func (s *handlerTestSuite) TestStreamEvents() {
+	id := uuid.New()
+	filter := user.EventFilter{
+		Types:   []user.UserEventType{user.UserEventTypeDeleted},
+		UserIDs: []uuid.UUID{id},
+	}
+	event := user.StoredEvent{
+		ID:        42,
+		UserEvent: user.UserEvent{Type: user.UserEventTypeDeleted, UserID: id},
+	}
+	s.userSvcMock.
+		On(Stream, mock.Anything, c.Ptr(int64(41)), filter, mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args.Get(3).(func(*user.StoredEvent) error)
+			s.NoError(send(&event))
+			s.NoError(send(nil))
+		}).
+		Return(nil).
+		Once()
+
+	ctx, rec := s.call(http.MethodGet, c.Ptr("/events?type=USER_DELETED&user_id="+id.String()), nil)
+	ctx.Request().Header.Set("Last-Event-ID", "41")
+
+	s.NoError(s.wrapper.StreamEvents(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("text/event-stream", rec.Header().Get(echo.HeaderContentType))
+
+	data, err := json.Marshal(&event.UserEvent)
+	s.NoError(err)
+	s.Equal("id: 42\nevent: USER_DELETED\ndata: "+string(data)+"\n\n: keepalive\n\n", rec.Body.String())
+}
+
+func (s *handlerTestSuite) TestStreamEvents_ReturnsErrorOnInvalidFilter() {
+	ctx, _ := s.call(http.MethodGet, c.Ptr("/events?type=USER_LOGGED_IN"), nil)
+
+	err := s.wrapper.StreamEvents(ctx).(*echo.HTTPError)
+	s.Equal(http.StatusBadRequest, err.Code)
+	s.userSvcMock.AssertNotCalled(s.T(), Stream)
+}
+
+func (s *handlerTestSuite) TestSearch() {
+	pagination := common.Pagination{Page: 1, PageSize: 2}
+	filter := user.Filter{And: []user.Filter{
+		{Field: "country", Op: user.FilterIn, Value: []interface{}{"UK", "US"}},
+		{Not: &user.Filter{Field: "nickname", Op: user.FilterPrefix, Value: "dome"}},
+	}}
+	s.userSvcMock.
+		On(Search, mock.Anything, pagination, filter).
+		Return([]user.User{{Email: "janedoe@email.com"}}, nil).
+		Once()
+
+	body := `{"filter":{"and":[{"field":"country","op":"in","value":["UK","US"]},{"not":{"field":"nickname","op":"prefix","value":"dome"}}]}}`
+	ctx, rec := s.call(http.MethodPost, c.Ptr("/search?page=1&pagesize=2"), strings.NewReader(body))
+
+	s.NoError(s.wrapper.Search(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+
+	var res []user.User
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Len(res, 1)
+	s.Equal("janedoe@email.com", res[0].Email)
+}
+
+func (s *handlerTestSuite) TestSearch_ReturnsError() {
+	for _, test := range []struct {
+		name           string
+		body           string
+		returnErr      error
+		expectedStatus int
+	}{
+		{"invalid body", `{"filter":`, nil, http.StatusBadRequest},
+		{"invalid filter", `{"filter":{"field":"password","op":"eq","value":"x"}}`, user.ErrInvalidFilter, http.StatusBadRequest},
+		{"invalid pagination", `{"filter":{"field":"country","op":"eq","value":"UK"}}`, user.ErrInvalidPagination, http.StatusBadRequest},
+		{"service error", `{"filter":{"field":"country","op":"eq","value":"UK"}}`, errors.New("db error"), http.StatusInternalServerError},
+	} {
+		s.Run(test.name, func() {
+			if test.returnErr != nil {
+				s.userSvcMock.
+					On(Search, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, test.returnErr).
+					Once()
+			}
+			ctx, _ := s.call(http.MethodPost, c.Ptr("/search"), strings.NewReader(test.body))
+
+			err := s.wrapper.Search(ctx).(*echo.HTTPError)
+			s.Equal(test.expectedStatus, err.Code)
+		})
+	}
+}
+
+func (s *handlerTestSuite) TestListEventKeys() {
+	publicKey := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+	s.userSvcMock.
+		On(Keys).
+		Return([]user.VerificationKey{{KeyID: "key-1", Algorithm: user.EventSigningEd25519, PublicKey: publicKey}}).
+		Once()
+
+	ctx, rec := s.call(http.MethodGet, c.Ptr("/events/keys"), nil)
+
+	s.NoError(s.wrapper.ListEventKeys(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+
+	var keys api.EventKeys
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &keys))
+	s.Equal([]api.EventKey{{Kid: "key-1", Alg: api.Ed25519, PublicKey: []byte(publicKey)}}, keys.Keys)
+	s.Contains(rec.Body.String(), base64.StdEncoding.EncodeToString(publicKey))
+}
+
+func (s *handlerTestSuite) call(method string, id *string, body io.Reader) (echo.Context, *httptest.ResponseRecorder) {
 	url := usersUrl
 	if id != nil {
 		url += *id
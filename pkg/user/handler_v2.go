@@ -0,0 +1,280 @@
+package user
+
+import (
+	"context"
+	"encoding/base64"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"faceit/internal/user/apiv2"
+	"faceit/pkg/server"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultPageLimit is the number of the listed users when the limit isn't set
+const defaultPageLimit = 10
+
+// HandlerV2 serves the v2 REST API of `api/users_v2.yaml` with the same user service as the v1 Handler.
+// The resources are wrapped in a `data` envelope and the users are paged by a cursor.
+type HandlerV2 struct {
+	timeout time.Duration
+	userSvc userService
+}
+
+// NewHandlerV2 creates the v2 handler of the users what shares the user service and the request timeout of the handler.
+func NewHandlerV2(h *Handler) *HandlerV2 {
+	return &HandlerV2{
+		timeout: h.timeout,
+		userSvc: h.userSvc,
+	}
+}
+
+// List returns a page of the users ordered by id after the user of the cursor.
+func (h HandlerV2) List(ctx echo.Context, params apiv2.ListParams) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	afterID := uuid.Nil
+	if params.Cursor != nil {
+		var err error
+		if afterID, err = decodeCursor(*params.Cursor); err != nil {
+			return server.NewHTTPError(err)
+		}
+	}
+	limit := defaultPageLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	users, more, err := h.userSvc.ListAfter(c, afterID, limit)
+	if err != nil {
+		log.Err(err).
+			Str("operation", "ListV2").
+			Str("params", ctx.QueryString()).
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Send()
+
+		return server.NewHTTPError(err)
+	}
+
+	page := apiv2.UserPage{Data: make([]apiv2.User, 0, len(users))}
+	for i := range users {
+		page.Data = append(page.Data, toUserV2(&users[i]))
+	}
+	if more {
+		page.NextCursor = common.Ptr(encodeCursor(users[len(users)-1].ID))
+	}
+	return ctx.JSON(http.StatusOK, page)
+}
+
+func (h HandlerV2) Create(ctx echo.Context, params apiv2.CreateParams) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	var req apiv2.CreateUser
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userIn := user.User{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Nickname:  req.Nickname,
+		Email:     string(req.Email),
+		Country:   req.Country,
+	}
+	password := value(req.Password)
+
+	var u *user.User
+	var replayed bool
+	var err error
+	if params.IdempotencyKey != nil {
+		u, replayed, err = h.userSvc.CreateIdempotent(c, *params.IdempotencyKey, userIn, password)
+	} else {
+		u, err = h.userSvc.Create(c, userIn, password)
+	}
+	if err != nil {
+		log.Err(err).
+			Str("operation", "CreateV2").
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Send()
+
+		return server.NewHTTPError(err)
+	}
+
+	if replayed {
+		ctx.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	}
+	return ctx.JSON(http.StatusCreated, apiv2.UserEnvelope{Data: toUserV2(u)})
+}
+
+func (h HandlerV2) GetByID(ctx echo.Context, id uuid.UUID) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	u, err := h.userSvc.Get(c, id, nil)
+	if err != nil {
+		log.Err(err).
+			Str("operation", "GetByIDV2").
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Stringer("ID", id).
+			Send()
+
+		return server.NewHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, apiv2.UserEnvelope{Data: toUserV2(u)})
+}
+
+// UpdateByID changes the set fields of the JSON body, or applies the JSON Merge Patch or the JSON Patch of the body
+// by its content type.
+func (h HandlerV2) UpdateByID(ctx echo.Context, id uuid.UUID) error {
+	if t, ok := patchType(ctx); ok {
+		return h.patchByID(ctx, id, t)
+	}
+
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	var req apiv2.UpdateUser
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userIn := user.User{
+		FirstName: value(req.FirstName),
+		LastName:  value(req.LastName),
+		Nickname:  value(req.Nickname),
+		Country:   value(req.Country),
+	}
+	if req.Email != nil {
+		userIn.Email = string(*req.Email)
+	}
+
+	u, err := h.userSvc.Update(c, id, userIn, value(req.Password))
+	if err == nil && u == nil {
+		// only the password was changed, so the user wasn't read by the update
+		u, err = h.userSvc.Get(c, id, nil)
+	}
+	if err != nil {
+		log.Err(err).
+			Str("operation", "UpdateByIDV2").
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Stringer("ID", id).
+			Send()
+
+		return server.NewHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, apiv2.UserEnvelope{Data: toUserV2(u)})
+}
+
+func (h HandlerV2) patchByID(ctx echo.Context, id uuid.UUID, patchType user.PatchType) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	patch, err := readPatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	u, err := h.userSvc.Patch(c, id, patchType, patch)
+	if err != nil {
+		log.Err(err).
+			Str("operation", "PatchByIDV2").
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Stringer("ID", id).
+			Str("type", string(patchType)).
+			Send()
+
+		return server.NewHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, apiv2.UserEnvelope{Data: toUserV2(u)})
+}
+
+// ReplaceByID saves every field of the user, the password is only changed when it's set.
+func (h HandlerV2) ReplaceByID(ctx echo.Context, id uuid.UUID) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	var req apiv2.CreateUser
+	if err := ctx.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userIn := user.User{
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Nickname:  req.Nickname,
+		Email:     string(req.Email),
+		Country:   req.Country,
+	}
+
+	u, err := h.userSvc.Replace(c, id, userIn, value(req.Password))
+	if err != nil {
+		log.Err(err).
+			Str("operation", "ReplaceByIDV2").
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Stringer("ID", id).
+			Send()
+
+		return server.NewHTTPError(err)
+	}
+	return ctx.JSON(http.StatusOK, apiv2.UserEnvelope{Data: toUserV2(u)})
+}
+
+func (h HandlerV2) DeleteByID(ctx echo.Context, id uuid.UUID) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	if err := h.userSvc.Delete(c, id); err != nil {
+		log.Err(err).
+			Str("operation", "DeleteByIDV2").
+			Str(common.CorrelationID, common.GetCorrelationID(c)).
+			Stringer("ID", id).
+			Send()
+
+		return server.NewHTTPError(err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (h HandlerV2) contextWithTimeout(ctx echo.Context) (context.Context, context.CancelFunc) {
+	ec := ctx.Request().Context()
+	c := context.WithValue(ec, common.CorrelationID, common.GetEchoCorrelationID(ctx))
+	return context.WithTimeout(c, h.timeout)
+}
+
+func toUserV2(u *user.User) apiv2.User {
+	return apiv2.User{
+		Id:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Nickname:  u.Nickname,
+		Email:     types.Email(u.Email),
+		Country:   u.Country,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+// encodeCursor returns the opaque cursor of the page after the user id
+func encodeCursor(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+func decodeCursor(cursor string) (uuid.UUID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: invalid cursor", user.ErrInvalidPagination)
+	}
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: invalid cursor", user.ErrInvalidPagination)
+	}
+	return id, nil
+}
@@ -0,0 +1,214 @@
+package user
+
+import (
+	"encoding/json"
+	"faceit/internal/user"
+	"faceit/internal/user/apiv2"
+	srv "faceit/pkg/server"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const ListAfter = "ListAfter"
+
+type (
+	handlerV2TestSuite struct {
+		userSvcMock *mockUserService
+		e           *echo.Echo
+		suite.Suite
+	}
+)
+
+func TestHandlerV2TestSuite(t *testing.T) {
+	suite.Run(t, new(handlerV2TestSuite))
+}
+
+func (s *handlerV2TestSuite) SetupTest() {
+	s.userSvcMock = newMockUserService(s.T())
+	s.e = echo.New()
+	s.e.HTTPErrorHandler = srv.HTTPErrorHandler
+
+	router := srv.CustomMethodRouter{Echo: s.e, Middlewares: []echo.MiddlewareFunc{srv.ProblemDetailsMiddleware}}
+	apiv2.RegisterHandlersWithBaseURL(router, NewHandlerV2(&Handler{timeout: time.Second, userSvc: s.userSvcMock}), "/api/v2")
+}
+
+func (s *handlerV2TestSuite) TestList_PagesByCursor() {
+	first := user.User{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), Nickname: "johndoe", Email: "johndoe@email.com"}
+	second := user.User{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), Nickname: "janedoe", Email: "janedoe@email.com"}
+	s.userSvcMock.
+		On(ListAfter, mock.Anything, uuid.Nil, 1).
+		Return([]user.User{first}, true, nil).
+		Once()
+	s.userSvcMock.
+		On(ListAfter, mock.Anything, first.ID, 1).
+		Return([]user.User{second}, false, nil).
+		Once()
+
+	var page apiv2.UserPage
+	rec := s.serve(http.MethodGet, "/api/v2/users?limit=1", nil)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &page))
+	s.Require().Len(page.Data, 1)
+	s.Equal(first.ID, page.Data[0].Id)
+	s.Require().NotNil(page.NextCursor)
+
+	cursor := *page.NextCursor
+	page = apiv2.UserPage{}
+	rec = s.serve(http.MethodGet, "/api/v2/users?limit=1&cursor="+cursor, nil)
+	s.Require().Equal(http.StatusOK, rec.Code)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &page))
+	s.Require().Len(page.Data, 1)
+	s.Equal(second.ID, page.Data[0].Id)
+	s.Nil(page.NextCursor)
+}
+
+func (s *handlerV2TestSuite) TestList_ReturnsProblemOnInvalidCursor() {
+	rec := s.serve(http.MethodGet, "/api/v2/users?cursor=invalid", nil)
+
+	s.Equal(http.StatusBadRequest, rec.Code)
+	s.Equal(srv.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	var p apiv2.Problem
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &p))
+	s.Equal("invalid_pagination", p.Code)
+}
+
+func (s *handlerV2TestSuite) TestCreate() {
+	expectedUser := user.User{FirstName: "john", LastName: "doe", Nickname: "johndoe", Email: "test@test.com", Country: "US"}
+	savedUser := expectedUser
+	savedUser.ID = userID
+	s.userSvcMock.
+		On(Create, mock.Anything, expectedUser, "testpwd").
+		Return(&savedUser, nil).
+		Once()
+
+	rec := s.serve(http.MethodPost, "/api/v2/users", strings.NewReader(
+		`{"first_name": "john", "last_name": "doe", "nickname": "johndoe", "email": "test@test.com", "country": "US", "password": "testpwd"}`))
+
+	s.Equal(http.StatusCreated, rec.Code)
+	var res apiv2.UserEnvelope
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Equal(userID, res.Data.Id)
+	s.Equal("johndoe", res.Data.Nickname)
+}
+
+func (s *handlerV2TestSuite) TestGetByID_ReturnsProblem() {
+	s.userSvcMock.
+		On(Get, mock.Anything, missingUserID, user.Fields(nil)).
+		Return(nil, user.ErrUserNotFound).
+		Once()
+
+	rec := s.serve(http.MethodGet, "/api/v2/users/"+missingUserID.String(), nil)
+
+	s.Equal(http.StatusNotFound, rec.Code)
+	s.Equal(srv.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	var p apiv2.Problem
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &p))
+	s.Equal("user_not_found", p.Code)
+}
+
+func (s *handlerV2TestSuite) TestUpdateByID_ReadsUserAfterPasswordChange() {
+	s.userSvcMock.
+		On(Update, mock.Anything, userID, user.User{}, "newpwd").
+		Return(nil, nil).
+		Once()
+	s.userSvcMock.
+		On(Get, mock.Anything, userID, user.Fields(nil)).
+		Return(&user.User{ID: userID, Nickname: "johndoe", Email: "johndoe@email.com"}, nil).
+		Once()
+
+	rec := s.serve(http.MethodPatch, "/api/v2/users/"+userID.String(), strings.NewReader(`{"password": "newpwd"}`))
+
+	s.Equal(http.StatusOK, rec.Code)
+	var res apiv2.UserEnvelope
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Equal("johndoe", res.Data.Nickname)
+}
+
+func (s *handlerV2TestSuite) TestUpdateByID_AppliesPatch() {
+	patch := `[{"op": "test", "path": "/nickname", "value": "johndoe"}, {"op": "replace", "path": "/nickname", "value": "johnny"}]`
+	s.userSvcMock.
+		On(Patch, mock.Anything, userID, user.PatchTypeJSON, []byte(patch)).
+		Return(&user.User{ID: userID, Nickname: "johnny", Email: "johndoe@email.com"}, nil).
+		Once()
+
+	rec := s.serveWithContentType(http.MethodPatch, "/api/v2/users/"+userID.String(), string(user.PatchTypeJSON), strings.NewReader(patch))
+
+	s.Equal(http.StatusOK, rec.Code)
+	var res apiv2.UserEnvelope
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Equal("johnny", res.Data.Nickname)
+}
+
+func (s *handlerV2TestSuite) TestUpdateByID_ReturnsProblemOnFailedTest() {
+	s.userSvcMock.
+		On(Patch, mock.Anything, userID, user.PatchTypeMerge, mock.Anything).
+		Return(nil, user.ErrUserModified).
+		Once()
+
+	rec := s.serveWithContentType(http.MethodPatch, "/api/v2/users/"+userID.String(), string(user.PatchTypeMerge), strings.NewReader(`{"nickname": "johnny"}`))
+
+	s.Equal(http.StatusConflict, rec.Code)
+	var p apiv2.Problem
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &p))
+	s.Equal("user_modified", p.Code)
+}
+
+func (s *handlerV2TestSuite) TestReplaceByID() {
+	expectedUser := user.User{FirstName: "john", LastName: "doe", Nickname: "johndoe", Email: "test@test.com", Country: "US"}
+	savedUser := expectedUser
+	savedUser.ID = userID
+	s.userSvcMock.
+		On(Replace, mock.Anything, userID, expectedUser, "").
+		Return(&savedUser, nil).
+		Once()
+
+	rec := s.serve(http.MethodPut, "/api/v2/users/"+userID.String(), strings.NewReader(
+		`{"first_name": "john", "last_name": "doe", "nickname": "johndoe", "email": "test@test.com", "country": "US"}`))
+
+	s.Equal(http.StatusOK, rec.Code)
+	var res apiv2.UserEnvelope
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Equal(userID, res.Data.Id)
+}
+
+func (s *handlerV2TestSuite) TestDeleteByID() {
+	s.userSvcMock.
+		On(Delete, mock.Anything, userID).
+		Return(nil).
+		Once()
+
+	rec := s.serve(http.MethodDelete, "/api/v2/users/"+userID.String(), nil)
+
+	s.Equal(http.StatusNoContent, rec.Code)
+}
+
+func (s *handlerV2TestSuite) TestCursor() {
+	id := uuid.New()
+	decoded, err := decodeCursor(encodeCursor(id))
+	s.NoError(err)
+	s.Equal(id, decoded)
+
+	_, err = decodeCursor(encodeCursor(id)[1:])
+	s.ErrorIs(err, user.ErrInvalidPagination)
+}
+
+func (s *handlerV2TestSuite) serve(method string, url string, body io.Reader) *httptest.ResponseRecorder {
+	return s.serveWithContentType(method, url, echo.MIMEApplicationJSON, body)
+}
+
+func (s *handlerV2TestSuite) serveWithContentType(method string, url string, contentType string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,126 @@
+package user
+
+import (
+	"context"
+	"faceit/internal/user"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// loaderWait is the time while the user lookups of a request are collected into a batch
+	loaderWait = 2 * time.Millisecond
+	// maxLoaderBatch is the most ids what BatchGet accepts, a full batch is loaded without waiting
+	maxLoaderBatch = 100
+)
+
+type loaderKey struct{}
+
+type (
+	// userLoader batches the user lookups of a request, the ids what are requested within loaderWait
+	// are loaded by one BatchGet, so N lookups become one repository query
+	userLoader struct {
+		load  func(ctx context.Context, ids []uuid.UUID) ([]user.User, []uuid.UUID, error)
+		wait  time.Duration
+		mu    sync.Mutex
+		batch *loaderBatch
+	}
+
+	// loaderBatch is the collected ids of a batch, its users are set when done is closed
+	loaderBatch struct {
+		ids   []uuid.UUID
+		added map[uuid.UUID]bool
+		full  chan struct{}
+		done  chan struct{}
+		users map[uuid.UUID]*user.User
+		err   error
+	}
+)
+
+func newUserLoader(svc userService) *userLoader {
+	return &userLoader{
+		load: func(ctx context.Context, ids []uuid.UUID) ([]user.User, []uuid.UUID, error) {
+			return svc.BatchGet(ctx, ids, nil)
+		},
+		wait: loaderWait,
+	}
+}
+
+// withUserLoader returns the context with a new user loader, it lives as long as the request.
+func withUserLoader(ctx context.Context, svc userService) context.Context {
+	return context.WithValue(ctx, loaderKey{}, newUserLoader(svc))
+}
+
+// loadUser returns the user of the id by the loader of the context, or ErrUserNotFound when it doesn't exist.
+func loadUser(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return ctx.Value(loaderKey{}).(*userLoader).Load(ctx, id)
+}
+
+// Load adds the id to the current batch and waits for its loading.
+func (l *userLoader) Load(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	if id == uuid.Nil {
+		return nil, user.ErrNilUUIDNotAllowed
+	}
+
+	l.mu.Lock()
+	if l.batch == nil {
+		l.batch = &loaderBatch{
+			added: map[uuid.UUID]bool{},
+			full:  make(chan struct{}),
+			done:  make(chan struct{}),
+		}
+		go l.dispatch(ctx, l.batch)
+	}
+	b := l.batch
+	if !b.added[id] {
+		b.added[id] = true
+		b.ids = append(b.ids, id)
+		if len(b.ids) == maxLoaderBatch {
+			l.batch = nil
+			close(b.full)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	u, ok := b.users[id]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	return u, nil
+}
+
+// dispatch loads the batch when it's full or the wait time is over
+func (l *userLoader) dispatch(ctx context.Context, b *loaderBatch) {
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+	select {
+	case <-b.full:
+	case <-timer.C:
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		l.mu.Unlock()
+	}
+
+	defer close(b.done)
+	users, _, err := l.load(ctx, b.ids)
+	if err != nil {
+		b.err = err
+		return
+	}
+	b.users = make(map[uuid.UUID]*user.User, len(users))
+	for i := range users {
+		b.users[users[i].ID] = &users[i]
+	}
+}
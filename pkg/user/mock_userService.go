@@ -18,6 +18,57 @@ type mockUserService struct {
 	mock.Mock
 }
 
+// BatchGet provides a mock function with given fields: ctx, ids, fields
+func (_m *mockUserService) BatchGet(ctx context.Context, ids []uuid.UUID, fields internaluser.Fields) ([]internaluser.User, []uuid.UUID, error) {
+	ret := _m.Called(ctx, ids, fields)
+
+	var r0 []internaluser.User
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID, internaluser.Fields) []internaluser.User); ok {
+		r0 = rf(ctx, ids, fields)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internaluser.User)
+		}
+	}
+
+	var r1 []uuid.UUID
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID, internaluser.Fields) []uuid.UUID); ok {
+		r1 = rf(ctx, ids, fields)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]uuid.UUID)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, []uuid.UUID, internaluser.Fields) error); ok {
+		r2 = rf(ctx, ids, fields)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Close provides a mock function with given fields: ctx
+func (_m *mockUserService) Close(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CloseStreams provides a mock function with given fields:
+func (_m *mockUserService) CloseStreams() {
+	_m.Called()
+}
+
 // Create provides a mock function with given fields: ctx, user, password
 func (_m *mockUserService) Create(ctx context.Context, user internaluser.User, password string) (*internaluser.User, error) {
 	ret := _m.Called(ctx, user, password)
@@ -41,6 +92,36 @@ func (_m *mockUserService) Create(ctx context.Context, user internaluser.User, p
 	return r0, r1
 }
 
+// CreateIdempotent provides a mock function with given fields: ctx, key, user, password
+func (_m *mockUserService) CreateIdempotent(ctx context.Context, key string, user internaluser.User, password string) (*internaluser.User, bool, error) {
+	ret := _m.Called(ctx, key, user, password)
+
+	var r0 *internaluser.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, internaluser.User, string) *internaluser.User); ok {
+		r0 = rf(ctx, key, user, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*internaluser.User)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, string, internaluser.User, string) bool); ok {
+		r1 = rf(ctx, key, user, password)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, internaluser.User, string) error); ok {
+		r2 = rf(ctx, key, user, password)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *mockUserService) Delete(ctx context.Context, id uuid.UUID) error {
 	ret := _m.Called(ctx, id)
@@ -55,13 +136,29 @@ func (_m *mockUserService) Delete(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
-// Get provides a mock function with given fields: ctx, id
-func (_m *mockUserService) Get(ctx context.Context, id uuid.UUID) (*internaluser.User, error) {
-	ret := _m.Called(ctx, id)
+// EventVerificationKeys provides a mock function with given fields:
+func (_m *mockUserService) EventVerificationKeys() []internaluser.VerificationKey {
+	ret := _m.Called()
+
+	var r0 []internaluser.VerificationKey
+	if rf, ok := ret.Get(0).(func() []internaluser.VerificationKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internaluser.VerificationKey)
+		}
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id, fields
+func (_m *mockUserService) Get(ctx context.Context, id uuid.UUID, fields internaluser.Fields) (*internaluser.User, error) {
+	ret := _m.Called(ctx, id, fields)
 
 	var r0 *internaluser.User
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *internaluser.User); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internaluser.Fields) *internaluser.User); ok {
+		r0 = rf(ctx, id, fields)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*internaluser.User)
@@ -69,8 +166,8 @@ func (_m *mockUserService) Get(ctx context.Context, id uuid.UUID) (*internaluser
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, internaluser.Fields) error); ok {
+		r1 = rf(ctx, id, fields)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -78,13 +175,13 @@ func (_m *mockUserService) Get(ctx context.Context, id uuid.UUID) (*internaluser
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx, pagination, filters
-func (_m *mockUserService) List(ctx context.Context, pagination common.Pagination, filters *internaluser.User) ([]internaluser.User, error) {
-	ret := _m.Called(ctx, pagination, filters)
+// List provides a mock function with given fields: ctx, pagination, filters, sort, fields
+func (_m *mockUserService) List(ctx context.Context, pagination common.Pagination, filters *internaluser.User, sort internaluser.Sort, fields internaluser.Fields) ([]internaluser.User, error) {
+	ret := _m.Called(ctx, pagination, filters, sort, fields)
 
 	var r0 []internaluser.User
-	if rf, ok := ret.Get(0).(func(context.Context, common.Pagination, *internaluser.User) []internaluser.User); ok {
-		r0 = rf(ctx, pagination, filters)
+	if rf, ok := ret.Get(0).(func(context.Context, common.Pagination, *internaluser.User, internaluser.Sort, internaluser.Fields) []internaluser.User); ok {
+		r0 = rf(ctx, pagination, filters, sort, fields)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]internaluser.User)
@@ -92,8 +189,8 @@ func (_m *mockUserService) List(ctx context.Context, pagination common.Paginatio
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, common.Pagination, *internaluser.User) error); ok {
-		r1 = rf(ctx, pagination, filters)
+	if rf, ok := ret.Get(1).(func(context.Context, common.Pagination, *internaluser.User, internaluser.Sort, internaluser.Fields) error); ok {
+		r1 = rf(ctx, pagination, filters, sort, fields)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -101,6 +198,119 @@ func (_m *mockUserService) List(ctx context.Context, pagination common.Paginatio
 	return r0, r1
 }
 
+// ListAfter provides a mock function with given fields: ctx, afterID, limit
+func (_m *mockUserService) ListAfter(ctx context.Context, afterID uuid.UUID, limit int) ([]internaluser.User, bool, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	var r0 []internaluser.User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) []internaluser.User); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internaluser.User)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) bool); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, int) error); ok {
+		r2 = rf(ctx, afterID, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Patch provides a mock function with given fields: ctx, id, patchType, patch
+func (_m *mockUserService) Patch(ctx context.Context, id uuid.UUID, patchType internaluser.PatchType, patch []byte) (*internaluser.User, error) {
+	ret := _m.Called(ctx, id, patchType, patch)
+
+	var r0 *internaluser.User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internaluser.PatchType, []byte) *internaluser.User); ok {
+		r0 = rf(ctx, id, patchType, patch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*internaluser.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, internaluser.PatchType, []byte) error); ok {
+		r1 = rf(ctx, id, patchType, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Replace provides a mock function with given fields: ctx, id, user, password
+func (_m *mockUserService) Replace(ctx context.Context, id uuid.UUID, user internaluser.User, password string) (*internaluser.User, error) {
+	ret := _m.Called(ctx, id, user, password)
+
+	var r0 *internaluser.User
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internaluser.User, string) *internaluser.User); ok {
+		r0 = rf(ctx, id, user, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*internaluser.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, internaluser.User, string) error); ok {
+		r1 = rf(ctx, id, user, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, pagination, filter
+func (_m *mockUserService) Search(ctx context.Context, pagination common.Pagination, filter internaluser.Filter) ([]internaluser.User, error) {
+	ret := _m.Called(ctx, pagination, filter)
+
+	var r0 []internaluser.User
+	if rf, ok := ret.Get(0).(func(context.Context, common.Pagination, internaluser.Filter) []internaluser.User); ok {
+		r0 = rf(ctx, pagination, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internaluser.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, common.Pagination, internaluser.Filter) error); ok {
+		r1 = rf(ctx, pagination, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamEvents provides a mock function with given fields: ctx, lastEventID, filter, send
+func (_m *mockUserService) StreamEvents(ctx context.Context, lastEventID *int64, filter internaluser.EventFilter, send func(*internaluser.StoredEvent) error) error {
+	ret := _m.Called(ctx, lastEventID, filter, send)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *int64, internaluser.EventFilter, func(*internaluser.StoredEvent) error) error); ok {
+		r0 = rf(ctx, lastEventID, filter, send)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, id, user, password
 func (_m *mockUserService) Update(ctx context.Context, id uuid.UUID, user internaluser.User, password string) (*internaluser.User, error) {
 	ret := _m.Called(ctx, id, user, password)
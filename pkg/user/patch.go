@@ -0,0 +1,35 @@
+package user
+
+import (
+	"faceit/internal/user"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// patchType returns the patch type of the request by its content type, or false for the other content types,
+// what are handled as a JSON body of the changed fields
+func patchType(ctx echo.Context) (user.PatchType, bool) {
+	mediaType, _, err := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return "", false
+	}
+
+	switch t := user.PatchType(mediaType); t {
+	case user.PatchTypeMerge, user.PatchTypeJSON:
+		return t, true
+	default:
+		return "", false
+	}
+}
+
+// readPatch returns the patch of the request body
+func readPatch(ctx echo.Context) ([]byte, error) {
+	patch, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return patch, nil
+}
@@ -81,7 +81,7 @@ func (s *usersAPITestSuite) SetupSuite() {
 	}
 }
 
-func (s usersAPITestSuite) TestUserCreation() {
+func (s *usersAPITestSuite) TestUserCreation() {
 	// delete test user from DB if exists
 	if err := s.db.Where(user.User{Email: "api@email.com"}).Delete(user.User{}).Error; err != nil {
 		s.Require().NoError(err)
@@ -145,7 +145,7 @@ func (s usersAPITestSuite) TestUserCreation() {
 	}
 }
 
-func (s usersAPITestSuite) getUserEvent() (*user.UserEvent, error) {
+func (s *usersAPITestSuite) getUserEvent() (*user.UserEvent, error) {
 	select {
 	case <-time.After(3 * time.Second):
 		return nil, context.DeadlineExceeded
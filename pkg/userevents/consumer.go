@@ -0,0 +1,252 @@
+package userevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrDeliveriesClosed = errors.New("user event deliveries are closed")
+
+type (
+	// Config defines the queue topology and the processing rules of a Consumer
+	Config struct {
+		// Exchange is the user event exchange (default `events.user`)
+		Exchange string
+		// Queue is the name of the consumer queue, the retry and dead-letter queues are named after it
+		Queue string
+		// BindingKey binds the queue to the exchange (default `#`)
+		BindingKey string
+		// Prefetch limits the unacknowledged messages of the consumer (default 10)
+		Prefetch int
+		// MaxAttempts is the number of processing attempts before the event is dead-lettered (default 5)
+		MaxAttempts int
+		// RetryDelay is the delay between the processing attempts (default 10s)
+		RetryDelay time.Duration
+		// Idempotency keeps track of the processed events (default in-memory store of the last 10000 events)
+		Idempotency IdempotencyStore
+		// Versions discards the events with lower version than the last processed event of the user (disabled by default)
+		Versions VersionStore
+		// Verifier dead-letters the events without a valid signature before they are decoded (disabled by default)
+		Verifier *Verifier
+	}
+
+	amqpChannel interface {
+		ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+		QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+		QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+		Qos(prefetchCount, prefetchSize int, global bool) error
+		Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+		Cancel(consumer string, noWait bool) error
+		PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	}
+
+	// Consumer receives the user events from a durable queue and calls the typed handlers.
+	// The messages are acknowledged manually after the processing. The failed events are retried
+	// through a delayed retry queue and they are dead-lettered after Config.MaxAttempts attempts.
+	Consumer struct {
+		config    Config
+		channel   amqpChannel
+		processor processor
+		tag       string
+	}
+)
+
+// NewConfig creates a Config with the default values for the given queue.
+func NewConfig(queue string) Config {
+	return Config{
+		Exchange:    "events.user",
+		Queue:       queue,
+		BindingKey:  "#",
+		Prefetch:    10,
+		MaxAttempts: 5,
+		RetryDelay:  10 * time.Second,
+		Idempotency: NewMemoryIdempotencyStore(10000),
+	}
+}
+
+// RetryQueue is the name of the queue where the failed events wait for the next attempt
+func (c Config) RetryQueue() string {
+	return c.Queue + ".retry"
+}
+
+// DeadLetterExchange is the name of the exchange where the rejected events are routed
+func (c Config) DeadLetterExchange() string {
+	return c.Queue + ".dlx"
+}
+
+// DeadLetterQueue is the name of the queue what holds the rejected events
+func (c Config) DeadLetterQueue() string {
+	return c.Queue + ".dlq"
+}
+
+func (c Config) validate() error {
+	switch {
+	case c.Exchange == "":
+		return errors.New("exchange is required")
+	case c.Queue == "":
+		return errors.New("queue is required")
+	case c.MaxAttempts < 1:
+		return errors.New("max attempts must be a positive number")
+	case c.RetryDelay <= 0:
+		return errors.New("retry delay must be positive")
+	case c.Idempotency == nil:
+		return errors.New("idempotency store is required")
+	}
+	return nil
+}
+
+// NewConsumer declares the queue topology on the channel and creates a Consumer with the handlers.
+// The channel should be used only by this consumer, and it is not closed by the consumer.
+func NewConsumer(ch *amqp.Channel, config Config, handlers Handlers) (*Consumer, error) {
+	return newConsumer(ch, config, handlers)
+}
+
+func newConsumer(ch amqpChannel, config Config, handlers Handlers) (*Consumer, error) {
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid consumer config: %w", err)
+	}
+	if err := declareTopology(ch, config); err != nil {
+		return nil, err
+	}
+
+	return &Consumer{
+		config:  config,
+		channel: ch,
+		processor: processor{
+			handlers:    handlers,
+			idempotency: config.Idempotency,
+			versions:    config.Versions,
+			maxAttempts: config.MaxAttempts,
+		},
+		tag: config.Queue + "-consumer",
+	}, nil
+}
+
+// declareTopology declares the user event exchange, the consumer queue bound to it,
+// the retry queue what returns the messages to the consumer queue after the retry delay
+// and the dead-letter exchange and queue of the rejected messages.
+func declareTopology(ch amqpChannel, config Config) error {
+	if err := ch.ExchangeDeclare(config.Exchange, "topic", true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := ch.ExchangeDeclare(config.DeadLetterExchange(), "fanout", true, false, false, false, nil); err != nil {
+		return err
+	}
+	if _, err := ch.QueueDeclare(config.DeadLetterQueue(), true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := ch.QueueBind(config.DeadLetterQueue(), "", config.DeadLetterExchange(), false, nil); err != nil {
+		return err
+	}
+
+	if _, err := ch.QueueDeclare(config.Queue, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange": config.DeadLetterExchange(),
+	}); err != nil {
+		return err
+	}
+	if err := ch.QueueBind(config.Queue, config.BindingKey, config.Exchange, false, nil); err != nil {
+		return err
+	}
+
+	_, err := ch.QueueDeclare(config.RetryQueue(), true, false, false, false, amqp.Table{
+		"x-message-ttl":             config.RetryDelay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": config.Queue,
+	})
+	return err
+}
+
+// Run consumes the events until the context is done or the deliveries are closed.
+// The events are processed one by one, more consumers could be run for parallel processing.
+func (c *Consumer) Run(ctx context.Context) error {
+	if err := c.channel.Qos(c.config.Prefetch, 0, false); err != nil {
+		return err
+	}
+
+	deliveries, err := c.channel.Consume(c.config.Queue, c.tag, false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return c.channel.Cancel(c.tag, false)
+		case d, ok := <-deliveries:
+			if !ok {
+				return ErrDeliveriesClosed
+			}
+			if err := c.handle(ctx, d); err != nil {
+				log.Err(err).
+					Str("queue", c.config.Queue).
+					Str("message_id", d.MessageId).
+					Msg("failed to acknowledge user event")
+			}
+		}
+	}
+}
+
+func (c *Consumer) handle(ctx context.Context, d amqp.Delivery) error {
+	if c.config.Verifier != nil {
+		if err := c.config.Verifier.Verify(d); err != nil {
+			log.Err(err).Str("queue", c.config.Queue).Str("message_id", d.MessageId).Msg("dead-lettering unverified user event")
+			return d.Nack(false, false)
+		}
+	}
+
+	event, err := decode(d)
+	if err != nil {
+		log.Err(err).Str("queue", c.config.Queue).Str("message_id", d.MessageId).Msg("dead-lettering invalid user event")
+		return d.Nack(false, false)
+	}
+
+	result, err := c.processor.process(ctx, event)
+	if err != nil {
+		log.Err(err).
+			Str("queue", c.config.Queue).
+			Str("event_id", event.ID).
+			Str("type", string(event.Type)).
+			Int("attempt", event.Attempt).
+			Msg("failed to process user event")
+	}
+
+	switch result {
+	case outcomeRetry:
+		if err := c.retry(ctx, d, event.Attempt); err != nil {
+			log.Err(err).Str("event_id", event.ID).Msg("failed to schedule user event retry")
+			return d.Nack(false, true)
+		}
+		return d.Ack(false)
+	case outcomeDeadLetter:
+		return d.Nack(false, false)
+	case outcomeRequeue:
+		return d.Nack(false, true)
+	default:
+		return d.Ack(false)
+	}
+}
+
+// retry publishes the original message to the retry queue with the number of the attempts.
+// The original message is acknowledged only after the retry was published.
+func (c *Consumer) retry(ctx context.Context, d amqp.Delivery, attempt int) error {
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[headerAttempt] = int32(attempt)
+
+	return c.channel.PublishWithContext(ctx, "", c.config.RetryQueue(), false, false, amqp.Publishing{
+		Headers:       headers,
+		ContentType:   d.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: d.CorrelationId,
+		MessageId:     d.MessageId,
+		Timestamp:     d.Timestamp,
+		Body:          d.Body,
+	})
+}
@@ -0,0 +1,292 @@
+package userevents
+
+import (
+	"context"
+	"errors"
+	"faceit/pkg/userevents/pb"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	exchangeDeclare    = "ExchangeDeclare"
+	queueDeclare       = "QueueDeclare"
+	queueBind          = "QueueBind"
+	publishWithContext = "PublishWithContext"
+	processed          = "Processed"
+	markProcessed      = "MarkProcessed"
+
+	createdBody = `{"event_id":"2f1c7a56-4f0e-4a8e-9d3b-6a0c5e2b7d11","type":"USER_CREATED","user_id":"9b0e5e7f-6a4b-4bd5-9c55-1d9c5b0b1b0e","version":1,"user_changes":{"id":"9b0e5e7f-6a4b-4bd5-9c55-1d9c5b0b1b0e","nickname":"johndoe"},"time":"2023-01-02T15:04:05Z"}`
+)
+
+type (
+	consumerTestSuite struct {
+		channelMock     *mockAmqpChannel
+		idempotencyMock *MockIdempotencyStore
+		suite.Suite
+	}
+
+	// acknowledger records how the delivery was acknowledged
+	acknowledger struct {
+		acked   bool
+		nacked  bool
+		requeue bool
+	}
+)
+
+func (a *acknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *acknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	a.nacked = true
+	a.requeue = requeue
+	return nil
+}
+
+func (a *acknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func TestConsumerTestSuite(t *testing.T) {
+	suite.Run(t, new(consumerTestSuite))
+}
+
+func (s *consumerTestSuite) SetupTest() {
+	s.channelMock = newMockAmqpChannel(s.T())
+	s.idempotencyMock = NewMockIdempotencyStore(s.T())
+}
+
+func (s *consumerTestSuite) TestNewConsumer_DeclaresTopology() {
+	config := s.config()
+	s.channelMock.On(exchangeDeclare, "events.user", "topic", true, false, false, false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.On(exchangeDeclare, "billing.dlx", "fanout", true, false, false, false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.On(queueDeclare, "billing.dlq", true, false, false, false, amqp.Table(nil)).Return(amqp.Queue{}, nil).Once()
+	s.channelMock.On(queueBind, "billing.dlq", "", "billing.dlx", false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.
+		On(queueDeclare, "billing", true, false, false, false, amqp.Table{"x-dead-letter-exchange": "billing.dlx"}).
+		Return(amqp.Queue{}, nil).
+		Once()
+	s.channelMock.On(queueBind, "billing", "#", "events.user", false, amqp.Table(nil)).Return(nil).Once()
+	s.channelMock.
+		On(queueDeclare, "billing.retry", true, false, false, false, amqp.Table{
+			"x-message-ttl":             int64(1000),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": "billing",
+		}).
+		Return(amqp.Queue{}, nil).
+		Once()
+
+	_, err := newConsumer(s.channelMock, config, Handlers{})
+	s.NoError(err)
+}
+
+func (s *consumerTestSuite) TestNewConsumer_ReturnsErrorOnInvalidConfig() {
+	config := s.config()
+	config.Queue = ""
+
+	_, err := newConsumer(s.channelMock, config, Handlers{})
+	s.Error(err)
+	s.channelMock.AssertNotCalled(s.T(), exchangeDeclare, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *consumerTestSuite) TestHandle_AcksProcessedEvent() {
+	var received User
+	consumer := s.consumer(Handlers{
+		Created: func(ctx context.Context, event Event, user User) error {
+			received = user
+			return nil
+		},
+	})
+	s.idempotencyMock.On(processed, mock.Anything, "event-1").Return(false, nil).Once()
+	s.idempotencyMock.On(markProcessed, mock.Anything, "event-1").Return(nil).Once()
+
+	ack := &acknowledger{}
+	s.NoError(consumer.handle(context.TODO(), s.delivery(ack, "event-1", createdBody, nil)))
+	s.True(ack.acked)
+	s.Equal("johndoe", received.Nickname)
+}
+
+func (s *consumerTestSuite) TestHandle_SkipsDuplicatedEvent() {
+	consumer := s.consumer(Handlers{
+		Created: func(ctx context.Context, event Event, user User) error {
+			s.Fail("duplicated event must not be processed")
+			return nil
+		},
+	})
+	s.idempotencyMock.On(processed, mock.Anything, "event-1").Return(true, nil).Once()
+
+	ack := &acknowledger{}
+	s.NoError(consumer.handle(context.TODO(), s.delivery(ack, "event-1", createdBody, nil)))
+	s.True(ack.acked)
+}
+
+func (s *consumerTestSuite) TestHandle_RetriesFailedEvent() {
+	consumer := s.consumer(Handlers{
+		Created: func(ctx context.Context, event Event, user User) error {
+			return errors.New("database is down")
+		},
+	})
+	s.idempotencyMock.On(processed, mock.Anything, "event-1").Return(false, nil).Once()
+	s.channelMock.
+		On(publishWithContext, mock.Anything, "", "billing.retry", false, false, mock.MatchedBy(func(msg amqp.Publishing) bool {
+			return msg.Headers[headerAttempt] == int32(2) && msg.MessageId == "event-1" && string(msg.Body) == createdBody
+		})).
+		Return(nil).
+		Once()
+
+	ack := &acknowledger{}
+	s.NoError(consumer.handle(context.TODO(), s.delivery(ack, "event-1", createdBody, amqp.Table{headerAttempt: int32(1)})))
+	s.True(ack.acked)
+}
+
+func (s *consumerTestSuite) TestHandle_DeadLettersEventAfterLastAttempt() {
+	consumer := s.consumer(Handlers{
+		Created: func(ctx context.Context, event Event, user User) error {
+			return errors.New("database is down")
+		},
+	})
+	s.idempotencyMock.On(processed, mock.Anything, "event-1").Return(false, nil).Once()
+
+	ack := &acknowledger{}
+	s.NoError(consumer.handle(context.TODO(), s.delivery(ack, "event-1", createdBody, amqp.Table{headerAttempt: int32(2)})))
+	s.True(ack.nacked)
+	s.False(ack.requeue)
+	s.channelMock.AssertNotCalled(s.T(), publishWithContext, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *consumerTestSuite) TestHandle_DeadLettersInvalidEvent() {
+	consumer := s.consumer(Handlers{})
+
+	for _, body := range []string{
+		`not json`,
+		`{"type":"USER_UPDATED","user_id":"9b0e5e7f-6a4b-4bd5-9c55-1d9c5b0b1b0e"}`,
+		`{"type":"USER_DELETED"}`,
+	} {
+		ack := &acknowledger{}
+		s.NoError(consumer.handle(context.TODO(), s.delivery(ack, "event-1", body, nil)))
+		s.True(ack.nacked)
+		s.False(ack.requeue)
+	}
+}
+
+func (s *consumerTestSuite) TestHandle_DeadLettersUnverifiedEvent() {
+	called := false
+	consumer := s.consumer(Handlers{
+		Created: func(ctx context.Context, event Event, user User) error {
+			called = true
+			return nil
+		},
+	})
+	consumer.config.Verifier = NewVerifier()
+	s.Require().NoError(consumer.config.Verifier.AddHMACKey("key-1", []byte("secret")))
+
+	ack := &acknowledger{}
+	s.NoError(consumer.handle(context.TODO(), s.delivery(ack, "event-1", createdBody, amqp.Table{
+		headerSignature:          "c2lnbmF0dXJl",
+		headerSignatureKeyID:     "key-1",
+		headerSignatureAlgorithm: AlgorithmHMAC,
+	})))
+	s.True(ack.nacked)
+	s.False(ack.requeue)
+	s.False(called)
+	s.idempotencyMock.AssertNotCalled(s.T(), processed, mock.Anything, mock.Anything)
+}
+
+func (s *consumerTestSuite) TestHandle_RequeuesWhenIdempotencyStoreFails() {
+	consumer := s.consumer(Handlers{})
+	s.idempotencyMock.On(processed, mock.Anything, mock.Anything).Return(false, errors.New("redis is down")).Once()
+
+	ack := &acknowledger{}
+	s.NoError(consumer.handle(context.TODO(), s.delivery(ack, "", createdBody, nil)))
+	s.True(ack.nacked)
+	s.True(ack.requeue)
+}
+
+func (s *consumerTestSuite) TestDecode() {
+	d := s.delivery(&acknowledger{}, "", createdBody, amqp.Table{headerSnapshot: true})
+	d.CorrelationId = "correlation-id"
+
+	event, err := decode(d)
+	s.NoError(err)
+	s.Len(event.ID, 64)
+	s.Equal(TypeCreated, event.Type)
+	s.Equal(uuid.MustParse("9b0e5e7f-6a4b-4bd5-9c55-1d9c5b0b1b0e"), event.UserID)
+	s.EqualValues(1, event.Version)
+	s.Equal("correlation-id", event.CorrelationID)
+	s.True(event.Snapshot)
+	s.False(event.Replayed)
+	s.Equal(1, event.Attempt)
+	s.Equal(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC), event.Time)
+}
+
+func (s *consumerTestSuite) TestDecode_Protobuf() {
+	userID := uuid.MustParse("9b0e5e7f-6a4b-4bd5-9c55-1d9c5b0b1b0e")
+	body, err := proto.Marshal(&pb.UserEvent{
+		EventId: "2f1c7a56-4f0e-4a8e-9d3b-6a0c5e2b7d11",
+		Type:    string(TypeCreated),
+		UserId:  userID.String(),
+		Version: 1,
+		UserChanges: &pb.User{
+			Id:        userID.String(),
+			Nickname:  "johndoe",
+			CreatedAt: timestamppb.New(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)),
+		},
+		Time: timestamppb.New(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)),
+	})
+	s.Require().NoError(err)
+	d := s.delivery(&acknowledger{}, "2f1c7a56-4f0e-4a8e-9d3b-6a0c5e2b7d11", string(body), nil)
+	d.ContentType = ContentTypeProtobuf
+
+	event, err := decode(d)
+	s.NoError(err)
+	s.Equal("2f1c7a56-4f0e-4a8e-9d3b-6a0c5e2b7d11", event.ID)
+	s.Equal(TypeCreated, event.Type)
+	s.Equal(userID, event.UserID)
+	s.EqualValues(1, event.Version)
+	s.Equal("johndoe", event.User.Nickname)
+	s.Nil(event.User.UpdatedAt)
+	s.Equal(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC), event.Time)
+
+	d.Body = []byte(createdBody)
+	_, err = decode(d)
+	s.ErrorIs(err, ErrInvalidEvent)
+}
+
+func (s *consumerTestSuite) config() Config {
+	config := NewConfig("billing")
+	config.MaxAttempts = 3
+	config.RetryDelay = time.Second
+	config.Idempotency = s.idempotencyMock
+	return config
+}
+
+func (s *consumerTestSuite) consumer(handlers Handlers) *Consumer {
+	config := s.config()
+	return &Consumer{
+		config:  config,
+		channel: s.channelMock,
+		processor: processor{
+			handlers:    handlers,
+			idempotency: config.Idempotency,
+			maxAttempts: config.MaxAttempts,
+		},
+	}
+}
+
+func (s *consumerTestSuite) delivery(ack amqp.Acknowledger, messageID string, body string, headers amqp.Table) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: ack,
+		Headers:      headers,
+		MessageId:    messageID,
+		Body:         []byte(body),
+	}
+}
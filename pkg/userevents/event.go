@@ -0,0 +1,134 @@
+package userevents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// EventType is the type of the user event
+type EventType string
+
+const (
+	TypeCreated         EventType = "USER_CREATED"
+	TypeUpdated         EventType = "USER_UPDATED"
+	TypePasswordChanged EventType = "USER_PASSWORD_CHANGED"
+	TypeDeleted         EventType = "USER_DELETED"
+	TypeEmailChanged    EventType = "USER_EMAIL_CHANGED"
+	TypeNicknameChanged EventType = "USER_NICKNAME_CHANGED"
+	TypeCountryChanged  EventType = "USER_COUNTRY_CHANGED"
+
+	// headers set by the user service on the replayed and snapshot events
+	headerReplay   = "x-replay"
+	headerSnapshot = "x-snapshot"
+	// headerAttempt counts the processing attempts of the retried messages
+	headerAttempt = "x-userevents-attempt"
+)
+
+var ErrInvalidEvent = errors.New("invalid user event")
+
+type (
+	// User is the state of the user in the USER_CREATED and USER_UPDATED events
+	User struct {
+		ID        uuid.UUID  `json:"id"`
+		FirstName string     `json:"first_name"`
+		LastName  string     `json:"last_name"`
+		Nickname  string     `json:"nickname"`
+		Email     string     `json:"email"`
+		Country   string     `json:"country"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt *time.Time `json:"updated_at"`
+	}
+
+	// Change is the old and the new value of the field in the USER_EMAIL_CHANGED, USER_NICKNAME_CHANGED and USER_COUNTRY_CHANGED events
+	Change struct {
+		Old string `json:"old"`
+		New string `json:"new"`
+	}
+
+	// Event is a user event received from the `events.user` exchange
+	Event struct {
+		// ID identifies the event for idempotent processing.
+		// It is the AMQP message id, or the hash of the message body when the message id is not set.
+		ID     string    `json:"-"`
+		Type   EventType `json:"type"`
+		UserID uuid.UUID `json:"user_id"`
+		// Version is the version of the user after the change, it is increased on every change of the user
+		Version       int64     `json:"version"`
+		User          *User     `json:"user_changes,omitempty"`
+		Change        *Change   `json:"change,omitempty"`
+		Time          time.Time `json:"time"`
+		CorrelationID string    `json:"-"`
+		// Replayed is true when the event was re-emitted from the event store of the user service
+		Replayed bool `json:"-"`
+		// Snapshot is true for the synthetic USER_CREATED events what contain the current state of an existing user
+		Snapshot bool `json:"-"`
+		// Attempt is the number of the processing attempts including the current one
+		Attempt int `json:"-"`
+	}
+)
+
+func (e Event) validate() error {
+	if e.UserID == uuid.Nil {
+		return errors.New("missing user id")
+	}
+	if (e.Type == TypeCreated || e.Type == TypeUpdated) && e.User == nil {
+		return fmt.Errorf("missing user of %s event", e.Type)
+	}
+	if (e.Type == TypeEmailChanged || e.Type == TypeNicknameChanged || e.Type == TypeCountryChanged) && e.Change == nil {
+		return fmt.Errorf("missing change of %s event", e.Type)
+	}
+	return nil
+}
+
+// decode parses the message body by its content type, the events are JSON encoded unless the content type is ContentTypeProtobuf.
+func decode(d amqp.Delivery) (Event, error) {
+	var event Event
+	unmarshal := func(body []byte, event *Event) error {
+		return json.Unmarshal(body, event)
+	}
+	if d.ContentType == ContentTypeProtobuf {
+		unmarshal = unmarshalProtobuf
+	}
+
+	if err := unmarshal(d.Body, &event); err != nil {
+		return event, fmt.Errorf("%w: %s", ErrInvalidEvent, err.Error())
+	}
+	if err := event.validate(); err != nil {
+		return event, fmt.Errorf("%w: %s", ErrInvalidEvent, err.Error())
+	}
+
+	event.ID = d.MessageId
+	if event.ID == "" {
+		hash := sha256.Sum256(d.Body)
+		event.ID = hex.EncodeToString(hash[:])
+	}
+	event.CorrelationID = d.CorrelationId
+	event.Replayed = headerBool(d.Headers, headerReplay)
+	event.Snapshot = headerBool(d.Headers, headerSnapshot)
+	event.Attempt = headerInt(d.Headers, headerAttempt) + 1
+	return event, nil
+}
+
+func headerBool(headers amqp.Table, key string) bool {
+	v, _ := headers[key].(bool)
+	return v
+}
+
+func headerInt(headers amqp.Table, key string) int {
+	switch v := headers[key].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
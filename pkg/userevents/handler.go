@@ -0,0 +1,37 @@
+package userevents
+
+import "context"
+
+// Handlers contains the typed handlers of the user events.
+// The events without handler are acknowledged without processing, so new event types don't break the existing consumers.
+// A returned error means the processing failed and the event is retried or dead-lettered.
+type Handlers struct {
+	Created         func(ctx context.Context, event Event, user User) error
+	Updated         func(ctx context.Context, event Event, user User) error
+	PasswordChanged func(ctx context.Context, event Event) error
+	Deleted         func(ctx context.Context, event Event) error
+	EmailChanged    func(ctx context.Context, event Event, change Change) error
+	NicknameChanged func(ctx context.Context, event Event, change Change) error
+	CountryChanged  func(ctx context.Context, event Event, change Change) error
+}
+
+// handle calls the handler of the event type and reports whether there was any.
+func (h Handlers) handle(ctx context.Context, event Event) (bool, error) {
+	switch {
+	case event.Type == TypeCreated && h.Created != nil:
+		return true, h.Created(ctx, event, *event.User)
+	case event.Type == TypeUpdated && h.Updated != nil:
+		return true, h.Updated(ctx, event, *event.User)
+	case event.Type == TypePasswordChanged && h.PasswordChanged != nil:
+		return true, h.PasswordChanged(ctx, event)
+	case event.Type == TypeDeleted && h.Deleted != nil:
+		return true, h.Deleted(ctx, event)
+	case event.Type == TypeEmailChanged && h.EmailChanged != nil:
+		return true, h.EmailChanged(ctx, event, *event.Change)
+	case event.Type == TypeNicknameChanged && h.NicknameChanged != nil:
+		return true, h.NicknameChanged(ctx, event, *event.Change)
+	case event.Type == TypeCountryChanged && h.CountryChanged != nil:
+		return true, h.CountryChanged(ctx, event, *event.Change)
+	}
+	return false, nil
+}
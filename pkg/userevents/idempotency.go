@@ -0,0 +1,55 @@
+package userevents
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// IdempotencyStore keeps track of the processed event ids, so the redelivered events are processed only once.
+// A persistent implementation should be used when the handlers are not idempotent on their own
+// and the duplicates must be detected across restarts or consumer instances.
+type IdempotencyStore interface {
+	Processed(ctx context.Context, eventID string) (bool, error)
+	MarkProcessed(ctx context.Context, eventID string) error
+}
+
+// MemoryIdempotencyStore remembers the last processed event ids in memory
+type MemoryIdempotencyStore struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	ids   map[string]*list.Element
+}
+
+// NewMemoryIdempotencyStore creates an IdempotencyStore what remembers the last size event ids.
+func NewMemoryIdempotencyStore(size int) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		size:  size,
+		order: list.New(),
+		ids:   map[string]*list.Element{},
+	}
+}
+
+func (s *MemoryIdempotencyStore) Processed(ctx context.Context, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.ids[eventID]
+	return ok, nil
+}
+
+func (s *MemoryIdempotencyStore) MarkProcessed(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ids[eventID]; ok {
+		return nil
+	}
+
+	s.ids[eventID] = s.order.PushBack(eventID)
+	if s.order.Len() > s.size {
+		oldest := s.order.Front()
+		s.order.Remove(oldest)
+		delete(s.ids, oldest.Value.(string))
+	}
+	return nil
+}
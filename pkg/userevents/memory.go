@@ -0,0 +1,83 @@
+package userevents
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Memory is an in-memory test double of the user event queue what applies the same idempotency and retry rules
+// as the Consumer, so the handlers could be unit tested without RabbitMQ.
+// The retries are done immediately without the retry delay.
+type Memory struct {
+	mu           sync.Mutex
+	processor    processor
+	acked        []Event
+	deadLettered []Event
+}
+
+// NewMemory creates a Memory test double with the handlers. The in-memory idempotency store is used
+// when the config doesn't have one, and the other config values are ignored besides MaxAttempts and Versions.
+func NewMemory(handlers Handlers, config Config) *Memory {
+	if config.Idempotency == nil {
+		config.Idempotency = NewMemoryIdempotencyStore(10000)
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+
+	return &Memory{
+		processor: processor{
+			handlers:    handlers,
+			idempotency: config.Idempotency,
+			versions:    config.Versions,
+			maxAttempts: config.MaxAttempts,
+		},
+	}
+}
+
+// Publish delivers the event to the handlers until it is processed or the attempts are exhausted.
+// A random event id is set when it's empty. The last handler error is returned when the event is dead-lettered.
+func (m *Memory) Publish(ctx context.Context, event Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if err := event.validate(); err != nil {
+		m.deadLettered = append(m.deadLettered, event)
+		return fmt.Errorf("%w: %s", ErrInvalidEvent, err.Error())
+	}
+
+	for attempt := 1; ; attempt++ {
+		event.Attempt = attempt
+		result, err := m.processor.process(ctx, event)
+		switch result {
+		case outcomeAck:
+			m.acked = append(m.acked, event)
+			return nil
+		case outcomeDeadLetter:
+			m.deadLettered = append(m.deadLettered, event)
+			return err
+		case outcomeRequeue:
+			return err
+		}
+	}
+}
+
+// Acked returns the acknowledged events (including the ignored and duplicated ones) in the order of publishing.
+func (m *Memory) Acked() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Event{}, m.acked...)
+}
+
+// DeadLettered returns the rejected events in the order of publishing.
+func (m *Memory) DeadLettered() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Event{}, m.deadLettered...)
+}
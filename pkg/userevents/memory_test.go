@@ -0,0 +1,111 @@
+package userevents
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	memoryTestSuite struct {
+		suite.Suite
+	}
+)
+
+func TestMemoryTestSuite(t *testing.T) {
+	suite.Run(t, new(memoryTestSuite))
+}
+
+func (s *memoryTestSuite) TestPublish_ProcessesEventOnce() {
+	calls := 0
+	memory := NewMemory(Handlers{
+		Deleted: func(ctx context.Context, event Event) error {
+			calls++
+			return nil
+		},
+	}, NewConfig("test"))
+
+	event := Event{ID: "event-1", Type: TypeDeleted, UserID: uuid.New()}
+	s.NoError(memory.Publish(context.TODO(), event))
+	s.NoError(memory.Publish(context.TODO(), event))
+	s.NoError(memory.Publish(context.TODO(), Event{Type: TypePasswordChanged, UserID: uuid.New()}))
+
+	s.Equal(1, calls)
+	s.Len(memory.Acked(), 3)
+	s.Empty(memory.DeadLettered())
+}
+
+func (s *memoryTestSuite) TestPublish_RetriesAndDeadLetters() {
+	errHandler := errors.New("handler error")
+	attempts := []int{}
+	config := NewConfig("test")
+	config.MaxAttempts = 3
+	memory := NewMemory(Handlers{
+		Updated: func(ctx context.Context, event Event, user User) error {
+			attempts = append(attempts, event.Attempt)
+			return errHandler
+		},
+	}, config)
+
+	err := memory.Publish(context.TODO(), Event{Type: TypeUpdated, UserID: uuid.New(), User: &User{}})
+	s.ErrorIs(err, errHandler)
+	s.Equal([]int{1, 2, 3}, attempts)
+	s.Len(memory.DeadLettered(), 1)
+	s.Empty(memory.Acked())
+}
+
+func (s *memoryTestSuite) TestPublish_SkipsStaleEvents() {
+	handled := []EventType{}
+	config := NewConfig("test")
+	config.Versions = NewMemoryVersionStore()
+	memory := NewMemory(Handlers{
+		Updated: func(ctx context.Context, event Event, user User) error {
+			handled = append(handled, event.Type)
+			return nil
+		},
+		Deleted: func(ctx context.Context, event Event) error {
+			handled = append(handled, event.Type)
+			return nil
+		},
+	}, config)
+
+	userID := uuid.New()
+	s.NoError(memory.Publish(context.TODO(), Event{Type: TypeDeleted, UserID: userID, Version: 3}))
+	s.NoError(memory.Publish(context.TODO(), Event{Type: TypeUpdated, UserID: userID, Version: 2, User: &User{}}))
+	s.NoError(memory.Publish(context.TODO(), Event{Type: TypeUpdated, UserID: uuid.New(), Version: 2, User: &User{}}))
+
+	s.Equal([]EventType{TypeDeleted, TypeUpdated}, handled)
+	s.Len(memory.Acked(), 3)
+}
+
+func (s *memoryTestSuite) TestPublish_HandlesFieldChangeWithSameVersion() {
+	var changes []Change
+	config := NewConfig("test")
+	config.Versions = NewMemoryVersionStore()
+	memory := NewMemory(Handlers{
+		Updated: func(ctx context.Context, event Event, user User) error {
+			return nil
+		},
+		EmailChanged: func(ctx context.Context, event Event, change Change) error {
+			changes = append(changes, change)
+			return nil
+		},
+	}, config)
+
+	userID := uuid.New()
+	s.NoError(memory.Publish(context.TODO(), Event{Type: TypeUpdated, UserID: userID, Version: 2, User: &User{}}))
+	s.NoError(memory.Publish(context.TODO(), Event{Type: TypeEmailChanged, UserID: userID, Version: 2, Change: &Change{Old: "old@email.com", New: "new@email.com"}}))
+
+	s.Equal([]Change{{Old: "old@email.com", New: "new@email.com"}}, changes)
+}
+
+func (s *memoryTestSuite) TestPublish_DeadLettersInvalidEvent() {
+	memory := NewMemory(Handlers{}, NewConfig("test"))
+
+	s.ErrorIs(memory.Publish(context.TODO(), Event{Type: TypeCreated, UserID: uuid.New()}), ErrInvalidEvent)
+	s.ErrorIs(memory.Publish(context.TODO(), Event{Type: TypeEmailChanged, UserID: uuid.New()}), ErrInvalidEvent)
+	s.Len(memory.DeadLettered(), 2)
+}
@@ -0,0 +1,64 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package userevents
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIdempotencyStore is an autogenerated mock type for the IdempotencyStore type
+type MockIdempotencyStore struct {
+	mock.Mock
+}
+
+// MarkProcessed provides a mock function with given fields: ctx, eventID
+func (_m *MockIdempotencyStore) MarkProcessed(ctx context.Context, eventID string) error {
+	ret := _m.Called(ctx, eventID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, eventID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Processed provides a mock function with given fields: ctx, eventID
+func (_m *MockIdempotencyStore) Processed(ctx context.Context, eventID string) (bool, error) {
+	ret := _m.Called(ctx, eventID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, eventID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewMockIdempotencyStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockIdempotencyStore creates a new instance of MockIdempotencyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockIdempotencyStore(t mockConstructorTestingTNewMockIdempotencyStore) *MockIdempotencyStore {
+	mock := &MockIdempotencyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package userevents
+
+import (
+	context "context"
+
+	uuid "github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockVersionStore is an autogenerated mock type for the VersionStore type
+type MockVersionStore struct {
+	mock.Mock
+}
+
+// LastVersion provides a mock function with given fields: ctx, userID
+func (_m *MockVersionStore) LastVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetVersion provides a mock function with given fields: ctx, userID, version
+func (_m *MockVersionStore) SetVersion(ctx context.Context, userID uuid.UUID, version int64) error {
+	ret := _m.Called(ctx, userID, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) error); ok {
+		r0 = rf(ctx, userID, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMockVersionStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockVersionStore creates a new instance of MockVersionStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockVersionStore(t mockConstructorTestingTNewMockVersionStore) *MockVersionStore {
+	mock := &MockVersionStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,78 @@
+package userevents
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// outcome is the result of an event processing what decides how the message is acknowledged
+type outcome int
+
+const (
+	// outcomeAck acknowledges the processed, duplicated or ignored message
+	outcomeAck outcome = iota
+	// outcomeRetry schedules the message to be processed again later
+	outcomeRetry
+	// outcomeDeadLetter rejects the message to the dead-letter queue
+	outcomeDeadLetter
+	// outcomeRequeue returns the message to the queue immediately, i.e. when the idempotency store is unavailable
+	outcomeRequeue
+)
+
+// processor applies the idempotency, ordering and retry rules on the handlers independently of the broker
+type processor struct {
+	handlers    Handlers
+	idempotency IdempotencyStore
+	versions    VersionStore
+	maxAttempts int
+}
+
+func (p processor) process(ctx context.Context, event Event) (outcome, error) {
+	processed, err := p.idempotency.Processed(ctx, event.ID)
+	if err != nil {
+		return outcomeRequeue, err
+	}
+	if processed {
+		log.Debug().Str("event_id", event.ID).Msg("skipping already processed user event")
+		return outcomeAck, nil
+	}
+
+	if p.versions != nil && event.Version > 0 {
+		last, err := p.versions.LastVersion(ctx, event.UserID)
+		if err != nil {
+			return outcomeRequeue, err
+		}
+		// the specific change events have the same version as the USER_UPDATED event of the change
+		if event.Version < last {
+			log.Debug().
+				Str("event_id", event.ID).
+				Int64("version", event.Version).
+				Int64("last_version", last).
+				Msg("skipping stale user event")
+			return outcomeAck, nil
+		}
+	}
+
+	handled, err := p.handlers.handle(ctx, event)
+	if err != nil {
+		if event.Attempt >= p.maxAttempts {
+			return outcomeDeadLetter, err
+		}
+		return outcomeRetry, err
+	}
+	if !handled {
+		return outcomeAck, nil
+	}
+
+	if err := p.idempotency.MarkProcessed(ctx, event.ID); err != nil {
+		// the event was processed, it would be only a duplicate on redelivery
+		log.Err(err).Str("event_id", event.ID).Msg("failed to mark user event as processed")
+	}
+	if p.versions != nil && event.Version > 0 {
+		if err := p.versions.SetVersion(ctx, event.UserID, event.Version); err != nil {
+			log.Err(err).Str("event_id", event.ID).Msg("failed to save user event version")
+		}
+	}
+	return outcomeAck, nil
+}
@@ -0,0 +1,72 @@
+package userevents
+
+import (
+	"faceit/pkg/userevents/pb"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ContentTypeProtobuf is the content type of the protobuf encoded events
+const ContentTypeProtobuf = "application/protobuf"
+
+// unmarshalProtobuf decodes the pb.UserEvent message of the protobuf encoded events into the event.
+func unmarshalProtobuf(body []byte, event *Event) error {
+	var msg pb.UserEvent
+	if err := proto.Unmarshal(body, &msg); err != nil {
+		return err
+	}
+
+	userID, err := parseUUID(msg.UserId)
+	if err != nil {
+		return err
+	}
+
+	*event = Event{
+		Type:    EventType(msg.Type),
+		UserID:  userID,
+		Version: msg.Version,
+		Time:    toTime(msg.Time),
+	}
+
+	if u := msg.UserChanges; u != nil {
+		id, err := parseUUID(u.Id)
+		if err != nil {
+			return err
+		}
+		event.User = &User{
+			ID:        id,
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Nickname:  u.Nickname,
+			Email:     u.Email,
+			Country:   u.Country,
+			CreatedAt: toTime(u.CreatedAt),
+		}
+		if u.UpdatedAt != nil {
+			updatedAt := toTime(u.UpdatedAt)
+			event.User.UpdatedAt = &updatedAt
+		}
+	}
+
+	if c := msg.Change; c != nil {
+		event.Change = &Change{Old: c.Old, New: c.New}
+	}
+	return nil
+}
+
+func parseUUID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
+
+func toTime(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
+}
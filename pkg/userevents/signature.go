@@ -0,0 +1,169 @@
+package userevents
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// AlgorithmEd25519 is the signing algorithm of the events signed by an Ed25519 key
+	AlgorithmEd25519 = "ed25519"
+	// AlgorithmHMAC is the signing algorithm of the events signed by a HMAC-SHA256 secret
+	AlgorithmHMAC = "hmac-sha256"
+
+	// headers set by the user service on the signed events
+	headerSignature          = "x-signature"
+	headerSignatureKeyID     = "x-signature-key-id"
+	headerSignatureAlgorithm = "x-signature-algorithm"
+)
+
+var (
+	ErrMissingSignature = errors.New("user event signature is missing")
+	ErrUnknownKey       = errors.New("user event is signed by an unknown key")
+	ErrInvalidSignature = errors.New("user event signature is invalid")
+)
+
+type (
+	// Verifier checks the signatures of the events before they are dispatched to the handlers.
+	// The signature is calculated from the message id and the body separated by a dot,
+	// and the keys are looked up by the key id of the message, so the rotated keys could be verified as well.
+	// The keys could be added while the consumer is running.
+	Verifier struct {
+		mu   sync.RWMutex
+		keys map[string]verificationKey
+	}
+
+	verificationKey struct {
+		algorithm string
+		key       []byte
+	}
+
+	// eventKeys is the response of the `GET /users/events/keys` endpoint of the user service
+	eventKeys struct {
+		Keys []struct {
+			Kid       string `json:"kid"`
+			Alg       string `json:"alg"`
+			PublicKey []byte `json:"public_key"`
+		} `json:"keys"`
+	}
+)
+
+// NewVerifier creates a Verifier without keys
+func NewVerifier() *Verifier {
+	return &Verifier{keys: map[string]verificationKey{}}
+}
+
+// AddEd25519Key adds the Ed25519 public key of the key id
+func (v *Verifier) AddEd25519Key(keyID string, publicKey ed25519.PublicKey) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("Ed25519 public key %s must be %d bytes", keyID, ed25519.PublicKeySize)
+	}
+	v.add(keyID, verificationKey{algorithm: AlgorithmEd25519, key: publicKey})
+	return nil
+}
+
+// AddHMACKey adds the HMAC-SHA256 secret of the key id
+func (v *Verifier) AddHMACKey(keyID string, secret []byte) error {
+	if len(secret) == 0 {
+		return fmt.Errorf("HMAC secret %s must not be empty", keyID)
+	}
+	v.add(keyID, verificationKey{algorithm: AlgorithmHMAC, key: secret})
+	return nil
+}
+
+// LoadKeys adds the Ed25519 public keys what are published by the user service, i.e. `http://localhost:8000/api/v1/users/events/keys`.
+// It could be called periodically to pick up the rotated keys.
+func (v *Verifier) LoadKeys(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to load user event keys: %s", res.Status)
+	}
+
+	var keys eventKeys
+	if err := json.NewDecoder(res.Body).Decode(&keys); err != nil {
+		return err
+	}
+	for _, k := range keys.Keys {
+		if k.Alg != AlgorithmEd25519 {
+			continue
+		}
+		if err := v.AddEd25519Key(k.Kid, k.PublicKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *Verifier) add(keyID string, key verificationKey) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys[keyID] = key
+}
+
+// Verify checks the signature of the message with the key of the key id header.
+// The algorithm header must match the algorithm of the key, so a public key couldn't be used as a HMAC secret.
+func (v *Verifier) Verify(d amqp.Delivery) error {
+	signature, _ := d.Headers[headerSignature].(string)
+	keyID, _ := d.Headers[headerSignatureKeyID].(string)
+	algorithm, _ := d.Headers[headerSignatureAlgorithm].(string)
+	if signature == "" || keyID == "" {
+		return ErrMissingSignature
+	}
+
+	v.mu.RLock()
+	key, ok := v.keys[keyID]
+	v.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	if key.algorithm != algorithm {
+		return fmt.Errorf("%w: key %s is not a %q key", ErrInvalidSignature, keyID, algorithm)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err.Error())
+	}
+	if !key.verify(signedContent(d.MessageId, d.Body), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (k verificationKey) verify(content []byte, signature []byte) bool {
+	switch k.algorithm {
+	case AlgorithmEd25519:
+		return ed25519.Verify(k.key, content, signature)
+	case AlgorithmHMAC:
+		mac := hmac.New(sha256.New, k.key)
+		mac.Write(content)
+		return hmac.Equal(mac.Sum(nil), signature)
+	}
+	return false
+}
+
+func signedContent(messageID string, body []byte) []byte {
+	content := make([]byte, 0, len(messageID)+1+len(body))
+	content = append(content, messageID...)
+	content = append(content, '.')
+	return append(content, body...)
+}
@@ -0,0 +1,126 @@
+package userevents
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type verifierTestSuite struct {
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+	suite.Suite
+}
+
+func TestVerifierTestSuite(t *testing.T) {
+	suite.Run(t, new(verifierTestSuite))
+}
+
+func (s *verifierTestSuite) SetupTest() {
+	s.privateKey = ed25519.NewKeyFromSeed([]byte("0123456789abcdef0123456789abcdef"))
+	s.publicKey = s.privateKey.Public().(ed25519.PublicKey)
+}
+
+func (s *verifierTestSuite) TestVerify_Ed25519() {
+	v := NewVerifier()
+	s.Require().NoError(v.AddEd25519Key("key-1", s.publicKey))
+
+	s.NoError(v.Verify(s.ed25519Delivery("event-1", createdBody)))
+}
+
+func (s *verifierTestSuite) TestVerify_HMAC() {
+	v := NewVerifier()
+	s.Require().NoError(v.AddHMACKey("key-1", []byte("secret")))
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("event-1." + createdBody))
+	d := amqp.Delivery{
+		MessageId: "event-1",
+		Body:      []byte(createdBody),
+		Headers: amqp.Table{
+			headerSignature:          base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+			headerSignatureKeyID:     "key-1",
+			headerSignatureAlgorithm: AlgorithmHMAC,
+		},
+	}
+
+	s.NoError(v.Verify(d))
+}
+
+func (s *verifierTestSuite) TestVerify_ReturnsErrorOnInvalidSignature() {
+	v := NewVerifier()
+	s.Require().NoError(v.AddEd25519Key("key-1", s.publicKey))
+
+	d := s.ed25519Delivery("event-1", createdBody)
+	d.Headers = nil
+	s.ErrorIs(v.Verify(d), ErrMissingSignature)
+
+	d = s.ed25519Delivery("event-1", createdBody)
+	d.Headers[headerSignatureKeyID] = "key-2"
+	s.ErrorIs(v.Verify(d), ErrUnknownKey)
+
+	d = s.ed25519Delivery("event-1", createdBody)
+	d.Headers[headerSignatureAlgorithm] = AlgorithmHMAC
+	s.ErrorIs(v.Verify(d), ErrInvalidSignature)
+
+	d = s.ed25519Delivery("event-1", createdBody)
+	d.Body = []byte(`{"type":"USER_DELETED"}`)
+	s.ErrorIs(v.Verify(d), ErrInvalidSignature)
+
+	d = s.ed25519Delivery("event-1", createdBody)
+	d.MessageId = "event-2"
+	s.ErrorIs(v.Verify(d), ErrInvalidSignature)
+
+	d = s.ed25519Delivery("event-1", createdBody)
+	d.Headers[headerSignature] = "not base64"
+	s.ErrorIs(v.Verify(d), ErrInvalidSignature)
+}
+
+func (s *verifierTestSuite) TestAddKey_ReturnsErrorOnInvalidKey() {
+	v := NewVerifier()
+	s.Error(v.AddEd25519Key("key-1", ed25519.PublicKey("short")))
+	s.Error(v.AddHMACKey("key-1", nil))
+}
+
+func (s *verifierTestSuite) TestLoadKeys() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"keys":[{"kid":"key-1","alg":"ed25519","public_key":"%s"}]}`, base64.StdEncoding.EncodeToString(s.publicKey))
+	}))
+	defer server.Close()
+
+	v := NewVerifier()
+	s.Require().NoError(v.LoadKeys(context.TODO(), server.Client(), server.URL))
+
+	s.NoError(v.Verify(s.ed25519Delivery("event-1", createdBody)))
+}
+
+func (s *verifierTestSuite) TestLoadKeys_ReturnsErrorOnFailedRequest() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	s.Error(NewVerifier().LoadKeys(context.TODO(), server.Client(), server.URL))
+}
+
+func (s *verifierTestSuite) ed25519Delivery(messageID string, body string) amqp.Delivery {
+	signature := ed25519.Sign(s.privateKey, []byte(messageID+"."+body))
+	return amqp.Delivery{
+		MessageId: messageID,
+		Body:      []byte(body),
+		Headers: amqp.Table{
+			headerSignature:          base64.StdEncoding.EncodeToString(signature),
+			headerSignatureKeyID:     "key-1",
+			headerSignatureAlgorithm: AlgorithmEd25519,
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package userevents
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// VersionStore keeps the version of the last processed event of every user, so the events what arrive out of order
+// (i.e. a USER_UPDATED redelivered after the USER_DELETED of the same user) could be discarded.
+type VersionStore interface {
+	LastVersion(ctx context.Context, userID uuid.UUID) (int64, error)
+	SetVersion(ctx context.Context, userID uuid.UUID, version int64) error
+}
+
+// MemoryVersionStore keeps the last processed versions in memory
+type MemoryVersionStore struct {
+	mu       sync.Mutex
+	versions map[uuid.UUID]int64
+}
+
+// NewMemoryVersionStore creates an empty VersionStore.
+func NewMemoryVersionStore() *MemoryVersionStore {
+	return &MemoryVersionStore{versions: map[uuid.UUID]int64{}}
+}
+
+func (s *MemoryVersionStore) LastVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.versions[userID], nil
+}
+
+func (s *MemoryVersionStore) SetVersion(ctx context.Context, userID uuid.UUID, version int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if version > s.versions[userID] {
+		s.versions[userID] = version
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package webhook
+
+import (
+	"context"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"faceit/internal/webhook"
+	"faceit/internal/webhook/api"
+	"faceit/pkg/server"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	webhookService interface {
+		Create(ctx context.Context, subscription webhook.Subscription) (*webhook.Subscription, error)
+		Get(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error)
+		List(ctx context.Context, pagination common.Pagination) ([]webhook.Subscription, error)
+		Update(ctx context.Context, id uuid.UUID, update webhook.SubscriptionUpdate) (*webhook.Subscription, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		ListDeliveries(ctx context.Context, id uuid.UUID, pagination common.Pagination) ([]webhook.Delivery, error)
+	}
+
+	Handler struct {
+		timeout    time.Duration
+		webhookSvc webhookService
+	}
+)
+
+// NewHandler creates the webhook subscription handlers on top of the given service.
+// The service is shared because it also has to be registered as a user event listener.
+func NewHandler(svc *webhook.Service) *Handler {
+	return &Handler{
+		timeout:    common.GetEnvDuration("REQUEST_TIMEOUT", 5*time.Second),
+		webhookSvc: svc,
+	}
+}
+
+func (h Handler) ListWebhooks(ctx echo.Context, params api.ListWebhooksParams) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	results, err := h.webhookSvc.List(c, getPagination(params.Page, params.Pagesize))
+	if err != nil {
+		return h.handleError(c, err, "ListWebhooks", uuid.Nil)
+	}
+
+	subscriptions := []api.Subscription{}
+	for _, r := range results {
+		subscriptions = append(subscriptions, toSubscriptionResponse(&r))
+	}
+	return ctx.JSON(http.StatusOK, subscriptions)
+}
+
+func (h Handler) CreateWebhook(ctx echo.Context) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	var in *api.NewSubscription
+	if err := ctx.Bind(&in); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if in == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing request body")
+	}
+
+	subscription := webhook.Subscription{
+		URL:     in.Url,
+		Enabled: true,
+	}
+	if in.Enabled != nil {
+		subscription.Enabled = *in.Enabled
+	}
+	if in.EventTypes != nil {
+		subscription.EventTypes = toEventTypes(*in.EventTypes)
+	}
+
+	s, err := h.webhookSvc.Create(c, subscription)
+	if err != nil {
+		return h.handleError(c, err, "CreateWebhook", uuid.Nil)
+	}
+
+	sub := toSubscriptionResponse(s)
+	return ctx.JSON(http.StatusCreated, api.SubscriptionWithSecret{
+		Id:                  sub.Id,
+		Url:                 sub.Url,
+		EventTypes:          sub.EventTypes,
+		Enabled:             sub.Enabled,
+		ConsecutiveFailures: sub.ConsecutiveFailures,
+		DisabledAt:          sub.DisabledAt,
+		CreatedAt:           sub.CreatedAt,
+		UpdatedAt:           sub.UpdatedAt,
+		Secret:              s.Secret,
+	})
+}
+
+func (h Handler) DeleteWebhookByID(ctx echo.Context, id uuid.UUID) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	if err := h.webhookSvc.Delete(c, id); err != nil {
+		return h.handleError(c, err, "DeleteWebhookByID", id)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (h Handler) GetWebhookByID(ctx echo.Context, id uuid.UUID) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	s, err := h.webhookSvc.Get(c, id)
+	if err != nil {
+		return h.handleError(c, err, "GetWebhookByID", id)
+	}
+	return ctx.JSON(http.StatusOK, toSubscriptionResponse(s))
+}
+
+func (h Handler) UpdateWebhookByID(ctx echo.Context, id uuid.UUID) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	var in *api.UpdateSubscription
+	if err := ctx.Bind(&in); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if in == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing request body")
+	}
+
+	update := webhook.SubscriptionUpdate{
+		URL:     in.Url,
+		Enabled: in.Enabled,
+	}
+	if in.EventTypes != nil {
+		update.EventTypes = common.Ptr(toEventTypes(*in.EventTypes))
+	}
+
+	s, err := h.webhookSvc.Update(c, id, update)
+	if err != nil {
+		return h.handleError(c, err, "UpdateWebhookByID", id)
+	}
+	return ctx.JSON(http.StatusOK, toSubscriptionResponse(s))
+}
+
+func (h Handler) ListWebhookDeliveries(ctx echo.Context, id uuid.UUID, params api.ListWebhookDeliveriesParams) error {
+	c, cancel := h.contextWithTimeout(ctx)
+	defer cancel()
+
+	results, err := h.webhookSvc.ListDeliveries(c, id, getPagination(params.Page, params.Pagesize))
+	if err != nil {
+		return h.handleError(c, err, "ListWebhookDeliveries", id)
+	}
+
+	deliveries := []api.Delivery{}
+	for _, r := range results {
+		deliveries = append(deliveries, toDeliveryResponse(&r))
+	}
+	return ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (h Handler) handleError(ctx context.Context, err error, operation string, id uuid.UUID) error {
+	log.Err(err).
+		Str("operation", operation).
+		Str(common.CorrelationID, common.GetCorrelationID(ctx)).
+		Stringer("ID", id).
+		Send()
+
+	return server.NewHTTPError(err)
+}
+
+func toSubscriptionResponse(s *webhook.Subscription) api.Subscription {
+	eventTypes := []api.EventType{}
+	for _, t := range s.EventTypes {
+		eventTypes = append(eventTypes, api.EventType(t))
+	}
+
+	return api.Subscription{
+		Id:                  s.ID,
+		Url:                 s.URL,
+		EventTypes:          eventTypes,
+		Enabled:             s.Enabled,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		DisabledAt:          s.DisabledAt,
+		CreatedAt:           s.CreatedAt,
+		UpdatedAt:           s.UpdatedAt,
+	}
+}
+
+func toDeliveryResponse(d *webhook.Delivery) api.Delivery {
+	return api.Delivery{
+		Id:         d.ID,
+		DeliveryId: d.DeliveryID,
+		EventType:  api.EventType(d.EventType),
+		UserId:     d.UserID,
+		Attempt:    d.Attempt,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Success:    d.Success,
+		DurationMs: common.Ptr(int(d.DurationMs)),
+		CreatedAt:  d.CreatedAt,
+	}
+}
+
+func toEventTypes(in []api.EventType) []user.UserEventType {
+	eventTypes := []user.UserEventType{}
+	for _, t := range in {
+		eventTypes = append(eventTypes, user.UserEventType(t))
+	}
+	return eventTypes
+}
+
+func (h Handler) contextWithTimeout(ctx echo.Context) (context.Context, context.CancelFunc) {
+	ec := ctx.Request().Context()
+	c := context.WithValue(ec, common.CorrelationID, common.GetEchoCorrelationID(ctx))
+	return context.WithTimeout(c, h.timeout)
+}
+
+func getPagination(page, pageSize *int) common.Pagination {
+	pagination := common.Pagination{}
+	if page != nil {
+		pagination.Page = *page
+	}
+
+	if pageSize != nil {
+		pagination.PageSize = *pageSize
+	}
+
+	return pagination
+}
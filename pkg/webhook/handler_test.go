@@ -0,0 +1,216 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"faceit/internal/common"
+	"faceit/internal/user"
+	"faceit/internal/webhook"
+	"faceit/internal/webhook/api"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	webhooksUrl    = "/api/v1/webhooks"
+	Get            = "Get"
+	List           = "List"
+	Create         = "Create"
+	Delete         = "Delete"
+	Update         = "Update"
+	ListDeliveries = "ListDeliveries"
+)
+
+type (
+	handlerTestSuite struct {
+		webhookSvcMock *mockWebhookService
+		handler        Handler
+		wrapper        api.ServerInterfaceWrapper
+		e              *echo.Echo
+		suite.Suite
+	}
+)
+
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(handlerTestSuite))
+}
+
+func (s *handlerTestSuite) SetupTest() {
+	s.e = echo.New()
+	s.webhookSvcMock = newMockWebhookService(s.T())
+	s.handler = Handler{
+		webhookSvc: s.webhookSvcMock,
+	}
+
+	// register wrapper to test OpenAPI validation as well
+	s.wrapper = api.ServerInterfaceWrapper{
+		Handler: s.handler,
+	}
+}
+
+func (s *handlerTestSuite) TestCreateWebhook() {
+	id := uuid.New()
+	expected := webhook.Subscription{
+		URL:        "https://example.com/hook",
+		EventTypes: []user.UserEventType{user.UserEventTypeDeleted},
+		Enabled:    true,
+	}
+	saved := expected
+	saved.ID = id
+	saved.Secret = "secret"
+	s.webhookSvcMock.
+		On(Create, mock.Anything, expected).
+		Return(&saved, nil).
+		Once()
+
+	ctx, rec := s.call(http.MethodPost, webhooksUrl, nil, strings.NewReader(`{"url":"https://example.com/hook","event_types":["USER_DELETED"]}`))
+
+	s.NoError(s.wrapper.CreateWebhook(ctx))
+	s.Equal(http.StatusCreated, rec.Code)
+
+	var res api.SubscriptionWithSecret
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Equal(id, res.Id)
+	s.Equal("secret", res.Secret)
+	s.Equal([]api.EventType{api.USERDELETED}, res.EventTypes)
+}
+
+func (s *handlerTestSuite) TestCreateWebhook_ReturnsError() {
+	for _, test := range []struct {
+		name            string
+		returnErr       error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "invalid data",
+			returnErr:       fmt.Errorf("%w: url must use https scheme", webhook.ErrInvalidSubscriptionInput),
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "input webhook subscription data is invalid: url must use https scheme",
+		},
+		{
+			name:            "service error",
+			returnErr:       errors.New("connection refused to 10.0.0.1"),
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "internal error",
+		},
+	} {
+		s.Run(test.name, func() {
+			s.webhookSvcMock.
+				On(Create, mock.Anything, mock.Anything).
+				Return(nil, test.returnErr).
+				Once()
+
+			ctx, _ := s.call(http.MethodPost, webhooksUrl, nil, strings.NewReader(`{"url":"http://example.com"}`))
+
+			err := s.wrapper.CreateWebhook(ctx).(*echo.HTTPError)
+			s.Equal(test.expectedStatus, err.Code)
+			s.Equal(test.expectedMessage, err.Message)
+		})
+	}
+}
+
+func (s *handlerTestSuite) TestGetWebhookByID_ReturnsNotFound() {
+	id := uuid.New()
+	s.webhookSvcMock.
+		On(Get, mock.Anything, id).
+		Return(nil, webhook.ErrSubscriptionNotFound).
+		Once()
+
+	ctx, _ := s.call(http.MethodGet, webhooksUrl+"/"+id.String(), common.Ptr(id.String()), nil)
+
+	err := s.wrapper.GetWebhookByID(ctx).(*echo.HTTPError)
+	s.Equal(http.StatusNotFound, err.Code)
+}
+
+func (s *handlerTestSuite) TestUpdateWebhookByID() {
+	id := uuid.New()
+	expected := webhook.SubscriptionUpdate{Enabled: common.Ptr(true)}
+	s.webhookSvcMock.
+		On(Update, mock.Anything, id, expected).
+		Return(&webhook.Subscription{ID: id, Enabled: true}, nil).
+		Once()
+
+	ctx, rec := s.call(http.MethodPatch, webhooksUrl+"/"+id.String(), common.Ptr(id.String()), strings.NewReader(`{"enabled":true}`))
+
+	s.NoError(s.wrapper.UpdateWebhookByID(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+}
+
+func (s *handlerTestSuite) TestDeleteWebhookByID() {
+	id := uuid.New()
+	s.webhookSvcMock.
+		On(Delete, mock.Anything, id).
+		Return(nil).
+		Once()
+
+	ctx, rec := s.call(http.MethodDelete, webhooksUrl+"/"+id.String(), common.Ptr(id.String()), nil)
+
+	s.NoError(s.wrapper.DeleteWebhookByID(ctx))
+	s.Equal(http.StatusNoContent, rec.Code)
+}
+
+func (s *handlerTestSuite) TestDeleteWebhookByID_ReturnsNotFound() {
+	id := uuid.New()
+	s.webhookSvcMock.
+		On(Delete, mock.Anything, id).
+		Return(webhook.ErrSubscriptionNotFound).
+		Once()
+
+	ctx, _ := s.call(http.MethodDelete, webhooksUrl+"/"+id.String(), common.Ptr(id.String()), nil)
+
+	err := s.wrapper.DeleteWebhookByID(ctx).(*echo.HTTPError)
+	s.Equal(http.StatusNotFound, err.Code)
+}
+
+func (s *handlerTestSuite) TestListWebhookDeliveries() {
+	id := uuid.New()
+	s.webhookSvcMock.
+		On(ListDeliveries, mock.Anything, id, common.Pagination{Page: 1, PageSize: 2}).
+		Return([]webhook.Delivery{{SubscriptionID: id, Attempt: 1, StatusCode: common.Ptr(500)}}, nil).
+		Once()
+
+	ctx, rec := s.call(http.MethodGet, webhooksUrl+"/"+id.String()+"/deliveries?page=1&pagesize=2", common.Ptr(id.String()), nil)
+
+	s.NoError(s.wrapper.ListWebhookDeliveries(ctx))
+	s.Equal(http.StatusOK, rec.Code)
+
+	var res []api.Delivery
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &res))
+	s.Len(res, 1)
+	s.Equal(500, *res[0].StatusCode)
+}
+
+func (s *handlerTestSuite) TestListWebhooks_ReturnsErrorOnInvalidPagination() {
+	s.webhookSvcMock.
+		On(List, mock.Anything, common.Pagination{Page: -1}).
+		Return(nil, fmt.Errorf("%w: page must be a positive number", webhook.ErrInvalidPagination)).
+		Once()
+
+	ctx, _ := s.call(http.MethodGet, webhooksUrl+"?page=-1", nil, nil)
+
+	err := s.wrapper.ListWebhooks(ctx).(*echo.HTTPError)
+	s.Equal(http.StatusBadRequest, err.Code)
+}
+
+func (s *handlerTestSuite) call(method string, url string, id *string, body io.Reader) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, url, body)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	ctx := s.e.NewContext(req, rec)
+	if id != nil {
+		ctx.SetParamNames("id")
+		ctx.SetParamValues(*id)
+	}
+	return ctx, rec
+}
@@ -0,0 +1,163 @@
+// Code generated by mockery v2.15.0. DO NOT EDIT.
+
+package webhook
+
+import (
+	context "context"
+	common "faceit/internal/common"
+
+	internalwebhook "faceit/internal/webhook"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// mockWebhookService is an autogenerated mock type for the webhookService type
+type mockWebhookService struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, subscription
+func (_m *mockWebhookService) Create(ctx context.Context, subscription internalwebhook.Subscription) (*internalwebhook.Subscription, error) {
+	ret := _m.Called(ctx, subscription)
+
+	var r0 *internalwebhook.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, internalwebhook.Subscription) *internalwebhook.Subscription); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*internalwebhook.Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, internalwebhook.Subscription) error); ok {
+		r1 = rf(ctx, subscription)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *mockWebhookService) Delete(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *mockWebhookService) Get(ctx context.Context, id uuid.UUID) (*internalwebhook.Subscription, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *internalwebhook.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *internalwebhook.Subscription); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*internalwebhook.Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, pagination
+func (_m *mockWebhookService) List(ctx context.Context, pagination common.Pagination) ([]internalwebhook.Subscription, error) {
+	ret := _m.Called(ctx, pagination)
+
+	var r0 []internalwebhook.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, common.Pagination) []internalwebhook.Subscription); ok {
+		r0 = rf(ctx, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internalwebhook.Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, common.Pagination) error); ok {
+		r1 = rf(ctx, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeliveries provides a mock function with given fields: ctx, id, pagination
+func (_m *mockWebhookService) ListDeliveries(ctx context.Context, id uuid.UUID, pagination common.Pagination) ([]internalwebhook.Delivery, error) {
+	ret := _m.Called(ctx, id, pagination)
+
+	var r0 []internalwebhook.Delivery
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, common.Pagination) []internalwebhook.Delivery); ok {
+		r0 = rf(ctx, id, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internalwebhook.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, common.Pagination) error); ok {
+		r1 = rf(ctx, id, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, update
+func (_m *mockWebhookService) Update(ctx context.Context, id uuid.UUID, update internalwebhook.SubscriptionUpdate) (*internalwebhook.Subscription, error) {
+	ret := _m.Called(ctx, id, update)
+
+	var r0 *internalwebhook.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internalwebhook.SubscriptionUpdate) *internalwebhook.Subscription); ok {
+		r0 = rf(ctx, id, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*internalwebhook.Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, internalwebhook.SubscriptionUpdate) error); ok {
+		r1 = rf(ctx, id, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTnewMockWebhookService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// newMockWebhookService creates a new instance of mockWebhookService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func newMockWebhookService(t mockConstructorTestingTnewMockWebhookService) *mockWebhookService {
+	mock := &mockWebhookService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,5 +1,9 @@
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS user_events;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
 DROP TABLE IF EXISTS users;
 
 CREATE TABLE users (
@@ -11,8 +15,67 @@ CREATE TABLE users (
     email varchar(128) NOT NULL UNIQUE,
     country varchar(2) NOT NULL,
     created_at timestamp with time zone NOT NULL DEFAULT NOW(),
-    updated_at timestamp with time zone
+    updated_at timestamp with time zone,
+    version bigint NOT NULL DEFAULT 1
 );
 
 CREATE INDEX created_at_idx on users(created_at);
-CREATE INDEX email_idx on users(email);
\ No newline at end of file
+CREATE INDEX email_idx on users(email);
+CREATE INDEX last_name_idx on users(last_name);
+CREATE INDEX nickname_idx on users(nickname);
+CREATE INDEX country_idx on users(country);
+
+CREATE TABLE webhook_subscriptions (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url varchar(2048) NOT NULL,
+    secret varchar(128) NOT NULL,
+    event_types jsonb NOT NULL DEFAULT '[]',
+    enabled boolean NOT NULL DEFAULT true,
+    consecutive_failures integer NOT NULL DEFAULT 0,
+    disabled_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp with time zone
+);
+
+CREATE TABLE webhook_deliveries (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id uuid NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    delivery_id uuid NOT NULL,
+    event_type varchar(32) NOT NULL,
+    user_id uuid NOT NULL,
+    attempt integer NOT NULL,
+    status_code integer,
+    error text,
+    success boolean NOT NULL,
+    duration_ms bigint NOT NULL DEFAULT 0,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX webhook_deliveries_subscription_idx on webhook_deliveries(subscription_id, created_at);
+
+CREATE TABLE user_events (
+    id bigserial PRIMARY KEY,
+    event_id uuid NOT NULL UNIQUE,
+    type varchar(32) NOT NULL,
+    user_id uuid NOT NULL,
+    version bigint NOT NULL,
+    user_changes jsonb,
+    change jsonb,
+    correlation_id varchar(128),
+    time timestamp with time zone NOT NULL DEFAULT NOW(),
+    tx_id xid8 NOT NULL DEFAULT pg_current_xact_id()
+);
+
+CREATE INDEX user_events_tx_idx on user_events(tx_id, id);
+CREATE INDEX user_events_user_idx on user_events(user_id, tx_id, id);
+CREATE INDEX user_events_type_idx on user_events(type, tx_id, id);
+
+CREATE TABLE idempotency_keys (
+    key varchar(255) PRIMARY KEY,
+    request_hash varchar(64) NOT NULL,
+    response jsonb,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    expires_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx on idempotency_keys(expires_at);